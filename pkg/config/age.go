@@ -0,0 +1,79 @@
+package config
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+// WithAgeIdentity decrypts age-encrypted values with the given X25519
+// identities, each an AGE-SECRET-KEY-1... string or the content of an age
+// identity file. A value is encrypted when it is written ENC[age:...] or,
+// in YAML, tagged !age, with the age ciphertext in ASCII armor or base64:
+//
+//	database:
+//	  host: db.internal
+//	  password: !age |
+//	    -----BEGIN AGE ENCRYPTED FILE-----
+//	    ...
+//	    -----END AGE ENCRYPTED FILE-----
+//
+// so the rest of the file stays readable. Decrypted keys are masked like
+// WithSecretKeys keys, and a value none of the identities can decrypt makes
+// Load fail. An identity that cannot be parsed makes Load fail as well.
+func WithAgeIdentity(identities ...string) Option {
+	return func(cm *ConfigManager) {
+		for _, s := range identities {
+			ids, err := age.ParseIdentities(strings.NewReader(s))
+			if err != nil {
+				cm.optionErr = errors.Join(cm.optionErr, fmt.Errorf("invalid age identity: %w", err))
+				continue
+			}
+			cm.ageIdentities = append(cm.ageIdentities, ids...)
+		}
+	}
+}
+
+const (
+	agePrefix = "ENC[age:"
+	ageTag    = "!age"
+)
+
+// ageEncrypted reports whether s is an ENC[age:...] value.
+func ageEncrypted(s string) bool {
+	return strings.HasPrefix(s, agePrefix) && strings.HasSuffix(s, "]")
+}
+
+// decryptAgeValue decrypts an ENC[age:...] value with identities.
+func decryptAgeValue(s string, identities []age.Identity) (string, error) {
+	body := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(s, agePrefix), "]"))
+	var r io.Reader
+	if strings.HasPrefix(body, armor.Header) {
+		// Indentation and trailing spaces are not part of the armor.
+		lines := strings.Split(body, "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimSpace(line)
+		}
+		r = armor.NewReader(strings.NewReader(strings.Join(lines, "\n") + "\n"))
+	} else {
+		data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(body), ""))
+		if err != nil {
+			return "", fmt.Errorf("invalid age value: %w", err)
+		}
+		r = strings.NewReader(string(data))
+	}
+	plain, err := age.Decrypt(r, identities...)
+	if err != nil {
+		return "", err
+	}
+	data, err := io.ReadAll(plain)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
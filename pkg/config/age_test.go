@@ -0,0 +1,109 @@
+package config
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// readAgeFixture returns a file of testdata/age, written by the age v1.2.1
+// CLI:
+//
+//	age-keygen -o identity.txt
+//	age-keygen -o other.txt
+//	printf s3cret | age -r $(age-keygen -y identity.txt) -a -o armored.age
+//	printf key-123 | age -r $(age-keygen -y identity.txt) -o binary.age
+//	age -r ... -o multichunk.age   # alphabetChars(140000), three chunks
+//	age -r ... -o exactchunk.age   # 65536 x's, one full final chunk
+//	printf shared | age -r $(age-keygen -y other.txt) -r ... -a -o recipients.age
+//	printf not-for-you | age -r $(age-keygen -y other.txt) -a -o wrong.age
+func readAgeFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "age", name))
+	require.NoError(t, err)
+	return string(data)
+}
+
+// ageArmoredValue returns an armored fixture as a YAML !age block scalar.
+func ageArmoredValue(t *testing.T, name string) string {
+	return "!age |\n    " + strings.ReplaceAll(strings.TrimSpace(readAgeFixture(t, name)), "\n", "\n    ")
+}
+
+// ageBinaryValue returns a binary fixture as an ENC[age:...] value.
+func ageBinaryValue(t *testing.T, name string) string {
+	return "ENC[age:" + base64.StdEncoding.EncodeToString([]byte(readAgeFixture(t, name))) + "]"
+}
+
+// alphabetChars returns n letters cycling through the alphabet.
+func alphabetChars(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte('a' + i%26)
+	}
+	return string(b)
+}
+
+func TestAgeValues(t *testing.T) {
+	identity := readAgeFixture(t, "identity.txt")
+	content := "database:\n  host: db.internal\n  password: " + ageArmoredValue(t, "armored.age") + "\n" +
+		"api:\n  key: " + ageBinaryValue(t, "binary.age") + "\n" +
+		"blob:\n  large: " + ageBinaryValue(t, "multichunk.age") + "\n" +
+		"  exact: " + ageBinaryValue(t, "exactchunk.age") + "\n" +
+		"  shared: " + ageArmoredValue(t, "recipients.age") + "\n"
+
+	t.Run("Decrypts CLI Output", func(t *testing.T) {
+		cfg := New(writeInterpolateConfig(t, content), zap.NewNop(), WithAgeIdentity(identity))
+		require.NoError(t, cfg.Load())
+
+		assert.Equal(t, "db.internal", cfg.GetString("database.host"))
+		assert.Equal(t, "s3cret", cfg.GetString("database.password"))
+		assert.Equal(t, "key-123", cfg.GetString("api.key"))
+		assert.Equal(t, alphabetChars(140000), cfg.GetString("blob.large"))
+		assert.Equal(t, strings.Repeat("x", 65536), cfg.GetString("blob.exact"))
+		assert.Equal(t, "shared", cfg.GetString("blob.shared"))
+		redacted := cfg.AllSettingsRedacted()
+		assert.Equal(t, RedactedValue, redacted["database"].(map[string]interface{})["password"])
+		assert.Equal(t, "db.internal", redacted["database"].(map[string]interface{})["host"])
+	})
+
+	t.Run("Other Recipient", func(t *testing.T) {
+		cfg := New(writeInterpolateConfig(t, "shared: "+ageArmoredValue(t, "recipients.age")+"\n"),
+			zap.NewNop(), WithAgeIdentity(readAgeFixture(t, "other.txt")))
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, "shared", cfg.GetString("shared"))
+	})
+
+	t.Run("Wrong Identity", func(t *testing.T) {
+		value := ageArmoredValue(t, "wrong.age")
+		cfg := New(writeInterpolateConfig(t, "api:\n  key: "+value+"\n"), zap.NewNop(), WithAgeIdentity(identity))
+		var serr *SecretError
+		require.ErrorAs(t, cfg.Load(), &serr)
+		assert.Equal(t, "api.key", serr.Key)
+		assert.NotContains(t, serr.Error(), readAgeFixture(t, "wrong.age")[40:80])
+	})
+
+	t.Run("Tampered Value", func(t *testing.T) {
+		ids := New("", zap.NewNop(), WithAgeIdentity(identity)).ageIdentities
+		data := []byte(readAgeFixture(t, "multichunk.age"))
+		data[len(data)/2] ^= 1
+		_, err := decryptAgeValue(agePrefix+base64.StdEncoding.EncodeToString(data)+"]", ids)
+		assert.Error(t, err)
+
+		// Dropping the last chunk must not pass as a shorter value.
+		truncated := []byte(readAgeFixture(t, "multichunk.age"))
+		truncated = truncated[:len(truncated)-(140000%(64*1024)+16)]
+		_, err = decryptAgeValue(agePrefix+base64.StdEncoding.EncodeToString(truncated)+"]", ids)
+		assert.Error(t, err)
+	})
+
+	t.Run("Invalid Identity", func(t *testing.T) {
+		cfg := New(writeInterpolateConfig(t, content), zap.NewNop(), WithAgeIdentity("AGE-SECRET-KEY-1INVALID"))
+		assert.ErrorContains(t, cfg.Load(), "invalid age identity")
+	})
+}
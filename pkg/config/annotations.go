@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/mitchellh/mapstructure"
+	"go.uber.org/zap"
+)
+
+// AnnotationsKey is the reserved top-level key of a remote document under
+// which writers attach attribution metadata. It is stripped from the
+// configuration tree before the document is applied.
+const AnnotationsKey = "_annotations"
+
+// Annotations carries attribution metadata attached to a config version by
+// whoever wrote it.
+type Annotations struct {
+	Author  string `mapstructure:"author" json:"author,omitempty"`
+	Ticket  string `mapstructure:"ticket" json:"ticket,omitempty"`
+	Message string `mapstructure:"message" json:"message,omitempty"`
+}
+
+// IsZero reports whether no annotation fields are set.
+func (a Annotations) IsZero() bool {
+	return a == Annotations{}
+}
+
+// annotationSource is implemented by providers that can report annotations
+// for the last document they loaded.
+type annotationSource interface {
+	lastAnnotations() Annotations
+}
+
+// extractAnnotations removes AnnotationsKey from settings and decodes it.
+func extractAnnotations(settings map[string]interface{}) (Annotations, error) {
+	var a Annotations
+	raw, ok := settings[AnnotationsKey]
+	if !ok {
+		return a, nil
+	}
+	delete(settings, AnnotationsKey)
+	if err := mapstructure.Decode(raw, &a); err != nil {
+		return a, fmt.Errorf("invalid %s: %w", AnnotationsKey, err)
+	}
+	return a, nil
+}
+
+// Annotations returns the attribution metadata of the live configuration
+// version, if its writer supplied any.
+func (cm *ConfigManager) Annotations() Annotations {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.annotations
+}
+
+// recordAnnotations picks up the provider's annotations after a successful
+// load and writes an audit log entry for attributed versions. The caller
+// must hold cm.mu.
+func (cm *ConfigManager) recordAnnotations() {
+	src, ok := cm.provider.(annotationSource)
+	if !ok {
+		return
+	}
+	cm.annotations = src.lastAnnotations()
+	if cm.annotations.IsZero() {
+		return
+	}
+	cm.logger.Info("Applied annotated configuration version",
+		zap.String("author", cm.annotations.Author),
+		zap.String("ticket", cm.annotations.Ticket),
+		zap.String("message", cm.annotations.Message))
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// writableBackend pairs a reader with a RemoteWriter for the same store.
+type writableBackend struct {
+	RemoteBackend
+	RemoteWriter
+}
+
+// endpointURL turns a provider endpoint such as "localhost:8500" into a base URL.
+func endpointURL(endpoint string) string {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "http://" + endpoint
+	}
+	return strings.TrimRight(endpoint, "/")
+}
+
+// doJSON sends a request and decodes a JSON response into out, if non-nil.
+// A 404 is reported through the returned status without an error.
+func doJSON(ctx context.Context, method, u string, body []byte, out interface{}) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return resp.StatusCode, nil
+	}
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return resp.StatusCode, fmt.Errorf("%s %s: %s: %s", method, u, resp.Status, bytes.TrimSpace(msg))
+	}
+	if out == nil {
+		return resp.StatusCode, nil
+	}
+	return resp.StatusCode, json.NewDecoder(resp.Body).Decode(out)
+}
+
+// consulWriter writes through the Consul KV HTTP API, using the key's
+// ModifyIndex for check-and-set.
+type consulWriter struct{}
+
+func (consulWriter) Put(ctx context.Context, rp *RemoteProvider, data []byte, ifRevision string) error {
+	u := endpointURL(rp.Endpoint) + "/v1/kv/" + strings.TrimLeft(rp.Path, "/")
+
+	query := ""
+	if ifRevision != "" {
+		var entries []struct {
+			ModifyIndex uint64
+			Value       []byte
+		}
+		status, err := doJSON(ctx, http.MethodGet, u, nil, &entries)
+		if err != nil {
+			return err
+		}
+		var index uint64
+		var current []byte
+		if status != http.StatusNotFound && len(entries) > 0 {
+			index, current = entries[0].ModifyIndex, entries[0].Value
+		}
+		if documentRevision(current) != ifRevision {
+			return ErrRevisionMismatch
+		}
+		query = "?cas=" + strconv.FormatUint(index, 10)
+	}
+
+	var ok bool
+	if _, err := doJSON(ctx, http.MethodPut, u+query, data, &ok); err != nil {
+		return err
+	}
+	if !ok {
+		return ErrRevisionMismatch
+	}
+	return nil
+}
+
+// etcd3Writer writes through the etcd v3 JSON gateway, using the key's
+// mod_revision in a transaction for compare-and-swap.
+type etcd3Writer struct{}
+
+func (etcd3Writer) Put(ctx context.Context, rp *RemoteProvider, data []byte, ifRevision string) error {
+	base := endpointURL(rp.Endpoint) + "/v3/kv/"
+	key := base64.StdEncoding.EncodeToString([]byte(rp.Path))
+	put := map[string]string{"key": key, "value": base64.StdEncoding.EncodeToString(data)}
+
+	if ifRevision == "" {
+		body, _ := json.Marshal(put)
+		_, err := doJSON(ctx, http.MethodPost, base+"put", body, nil)
+		return err
+	}
+
+	var rangeResp struct {
+		Kvs []struct {
+			ModRevision string `json:"mod_revision"`
+			Value       []byte `json:"value"`
+		} `json:"kvs"`
+	}
+	body, _ := json.Marshal(map[string]string{"key": key})
+	if _, err := doJSON(ctx, http.MethodPost, base+"range", body, &rangeResp); err != nil {
+		return err
+	}
+	modRevision := "0"
+	var current []byte
+	if len(rangeResp.Kvs) > 0 {
+		modRevision, current = rangeResp.Kvs[0].ModRevision, rangeResp.Kvs[0].Value
+	}
+	if documentRevision(current) != ifRevision {
+		return ErrRevisionMismatch
+	}
+
+	body, _ = json.Marshal(map[string]interface{}{
+		"compare": []map[string]string{{
+			"key": key, "target": "MOD", "result": "EQUAL", "mod_revision": modRevision,
+		}},
+		"success": []map[string]interface{}{{"request_put": put}},
+	})
+	var txnResp struct {
+		Succeeded bool `json:"succeeded"`
+	}
+	if _, err := doJSON(ctx, http.MethodPost, base+"txn", body, &txnResp); err != nil {
+		return err
+	}
+	if !txnResp.Succeeded {
+		return ErrRevisionMismatch
+	}
+	return nil
+}
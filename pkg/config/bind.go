@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+// Binding keeps a caller-owned struct in sync with the configuration.
+type Binding struct {
+	mu  sync.RWMutex
+	ptr interface{}
+	// pending holds the copy decoded by a load until it is applied.
+	pending interface{}
+}
+
+// Bind decodes the configuration into the struct ptr points to and keeps it
+// up to date: every later load decodes and validates a fresh copy, and a
+// copy that fails rejects the load like a failed schema. The struct is
+// updated under the binding's lock, so read it through Value, which returns
+// a consistent copy, rather than directly.
+func (cm *ConfigManager) Bind(ptr interface{}) (*Binding, error) {
+	rv := reflect.ValueOf(ptr)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("bind target must be a non-nil pointer to a struct, got %T", ptr)
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	b := &Binding{ptr: ptr}
+	if cm.loaded {
+		staged := scratchCopy(ptr)
+		if err := cm.decodeSchema(cm.viper, "", staged); err != nil {
+			return nil, err
+		}
+		b.set(staged)
+	}
+	cm.bindings = append(cm.bindings, b)
+	return b, nil
+}
+
+// Value returns a pointer to a copy of the bound struct as of the last
+// applied load. The copy is never modified.
+func (b *Binding) Value() interface{} {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return copyStruct(b.ptr)
+}
+
+// set copies the struct staged points to into the bound struct.
+func (b *Binding) set(staged interface{}) {
+	b.mu.Lock()
+	reflect.ValueOf(b.ptr).Elem().Set(reflect.ValueOf(staged).Elem())
+	b.mu.Unlock()
+}
+
+// stageBindings decodes and validates next into fresh copies of the bound
+// structs, to be applied by applyBindings once the load is swapped in. The
+// caller must hold cm.mu.
+func (cm *ConfigManager) stageBindings(next *viper.Viper) error {
+	for _, b := range cm.bindings {
+		staged := scratchCopy(b.ptr)
+		if err := cm.decodeSchema(next, "", staged); err != nil {
+			return fmt.Errorf("bound %T: %w", b.ptr, err)
+		}
+		b.pending = staged
+	}
+	return nil
+}
+
+// applyBindings updates the bound structs with the copies staged for the
+// load just swapped in. The caller must hold cm.mu.
+func (cm *ConfigManager) applyBindings() {
+	for _, b := range cm.bindings {
+		if b.pending != nil {
+			b.set(b.pending)
+			b.pending = nil
+		}
+	}
+}
+
+// copyStruct returns a new pointer to a shallow copy of the struct ptr
+// points to.
+func copyStruct(ptr interface{}) interface{} {
+	rv := reflect.ValueOf(ptr)
+	copied := reflect.New(rv.Elem().Type())
+	copied.Elem().Set(rv.Elem())
+	return copied.Interface()
+}
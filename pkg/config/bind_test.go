@@ -0,0 +1,71 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestBind(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	type serverSettings struct {
+		Server struct {
+			Port int `mapstructure:"port" validate:"min=1"`
+		} `mapstructure:"server"`
+	}
+	writePort := func(port int) {
+		content := "server:\n  port: " + strconv.Itoa(port) + "\n"
+		require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+	}
+
+	t.Run("Bound Before Load", func(t *testing.T) {
+		cfg := New(configPath, zap.NewNop())
+		var settings serverSettings
+		b, err := cfg.Bind(&settings)
+		require.NoError(t, err)
+
+		writePort(8080)
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 8080, b.Value().(*serverSettings).Server.Port)
+
+		writePort(9090)
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 9090, b.Value().(*serverSettings).Server.Port)
+		assert.Equal(t, 9090, settings.Server.Port)
+
+		// An invalid value rejects the whole load.
+		writePort(0)
+		err = cfg.Load()
+		var verrs ValidationErrors
+		require.ErrorAs(t, err, &verrs)
+		assert.Equal(t, "server.port", verrs[0].Key)
+		assert.Equal(t, 9090, b.Value().(*serverSettings).Server.Port)
+		assert.Equal(t, 9090, cfg.GetInt("server.port"))
+	})
+
+	t.Run("Bound After Load", func(t *testing.T) {
+		writePort(7070)
+		cfg := New(configPath, zap.NewNop())
+		require.NoError(t, cfg.Load())
+
+		var settings serverSettings
+		b, err := cfg.Bind(&settings)
+		require.NoError(t, err)
+		assert.Equal(t, 7070, b.Value().(*serverSettings).Server.Port)
+	})
+
+	t.Run("Invalid Target", func(t *testing.T) {
+		cfg := New(configPath, zap.NewNop())
+		var port int
+		_, err := cfg.Bind(&port)
+		assert.Error(t, err)
+		_, err = cfg.Bind(serverSettings{})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"reflect"
+	"runtime"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// defaultSubscriberBudget is how long a subscriber may take without
+// WithSubscriberBudget before it is logged as slow.
+const defaultSubscriberBudget = time.Second
+
+// WithSubscriberBudget logs a warning, naming the callback, whenever a
+// Subscribe, OnKeyChange, or OnPrefixChange callback takes longer than d.
+// Callbacks run one after the other, so a slow one delays every later
+// subscriber and the next reload. The default is one second; zero or less
+// disables the warning.
+func WithSubscriberBudget(d time.Duration) Option {
+	return func(cm *ConfigManager) {
+		cm.subscriberBudget = d
+	}
+}
+
+// observeCallback records the duration of the call of the subscriber id,
+// the callback name, started at start to handle event. The histogram is
+// broken down by name rather than the subscriber id, which grows with
+// every Subscribe and would make the attribute unbounded.
+func (cm *ConfigManager) observeCallback(id uint64, name string, event ChangeEvent, start time.Time) {
+	d := time.Since(start)
+	cm.telemetry.recordDuration(MetricSubscriberDuration, start, nil,
+		Attribute{Key: "config.callback", Value: name})
+	if cm.subscriberBudget <= 0 || d <= cm.subscriberBudget {
+		return
+	}
+	cm.slowCallbacks.Add(1)
+	cm.logger.Warn("Slow config subscriber",
+		zap.Uint64("subscriber", id),
+		zap.String("callback", name),
+		zap.Duration("duration", d),
+		zap.Duration("budget", cm.subscriberBudget),
+		zap.String("source", event.Source),
+		zap.Int("changes", len(event.Changes)))
+}
+
+// callbackName returns the name of the function fn, e.g.
+// "main.run.func1" for a closure in run.
+func callbackName(fn interface{}) string {
+	if f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()); f != nil {
+		return f.Name()
+	}
+	return ""
+}
+
+// callerName returns the name of the function calling the caller of
+// callerName, for subscribers without a callback of their own.
+func callerName() string {
+	pc, _, _, ok := runtime.Caller(2)
+	if !ok {
+		return ""
+	}
+	if f := runtime.FuncForPC(pc); f != nil {
+		return f.Name()
+	}
+	return ""
+}
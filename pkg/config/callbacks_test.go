@@ -0,0 +1,99 @@
+package config
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func slowSubscriber(ChangeEvent) {
+	time.Sleep(20 * time.Millisecond)
+}
+
+func TestSubscriberBudget(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	core, logs := observer.New(zap.WarnLevel)
+	meter := newFakeMeter()
+	cfg := New(configPath, zap.New(core), WithSubscriberBudget(10*time.Millisecond), WithMeter(meter))
+	defer cfg.Close()
+	require.NoError(t, cfg.Load())
+
+	cfg.Subscribe(func(ChangeEvent) {})
+	cfg.Subscribe(slowSubscriber)
+	cfg.publish(ChangeEvent{Time: time.Now(), Source: configPath, Changes: []Change{{Key: "server.port"}}})
+
+	slow := logs.FilterMessage("Slow config subscriber").All()
+	require.Len(t, slow, 1)
+	fields := slow[0].ContextMap()
+	assert.Equal(t, uint64(2), fields["subscriber"])
+	assert.Contains(t, fields["callback"], "slowSubscriber")
+	assert.Equal(t, configPath, fields["source"])
+	assert.EqualValues(t, 1, fields["changes"])
+	assert.Equal(t, uint64(1), cfg.Stats().SlowCallbacks)
+
+	durations := meter.durations[MetricSubscriberDuration]
+	require.Len(t, durations, 2)
+	assert.Contains(t, durations[0]["config.callback"], "TestSubscriberBudget.func")
+	assert.Contains(t, durations[1]["config.callback"], "slowSubscriber")
+	assert.NotContains(t, durations[1], "config.subscriber")
+	require.Len(t, meter.durations[MetricReloadLatency], 1)
+	assert.Equal(t, "ok", meter.durations[MetricReloadLatency][0]["config.result"])
+
+	t.Run("Disabled", func(t *testing.T) {
+		core, logs := observer.New(zap.WarnLevel)
+		cfg := New(configPath, zap.New(core), WithSubscriberBudget(0))
+		defer cfg.Close()
+		require.NoError(t, cfg.Load())
+		cfg.Subscribe(slowSubscriber)
+		cfg.publish(ChangeEvent{Time: time.Now()})
+		assert.Zero(t, logs.FilterMessage("Slow config subscriber").Len())
+		assert.Zero(t, cfg.Stats().SlowCallbacks)
+	})
+}
+
+func onPortChange(_, _ interface{}) {}
+
+func resizePool(int) {}
+
+func TestSubscriberNames(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	core, logs := observer.New(zap.WarnLevel)
+	meter := newFakeMeter()
+	cfg := New(configPath, zap.New(core), WithSubscriberBudget(time.Nanosecond), WithMeter(meter))
+	defer cfg.Close()
+	require.NoError(t, cfg.Load())
+
+	cfg.OnKeyChange("server.port", onPortChange)
+	cfg.BindInt("server.port", resizePool)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cfg.Changes(ctx)
+	cfg.publish(ChangeEvent{Time: time.Now(), Changes: []Change{{Key: "server.port", New: 8080}}})
+
+	// Wrapped callbacks are labeled with the function the caller passed in.
+	var names []string
+	for _, recorded := range meter.durations[MetricSubscriberDuration] {
+		names = append(names, recorded["config.callback"].(string))
+	}
+	require.Len(t, names, 3)
+	assert.True(t, strings.HasSuffix(names[0], "config.onPortChange"), names[0])
+	assert.True(t, strings.HasSuffix(names[1], "config.resizePool"), names[1])
+	assert.Contains(t, names[2], "TestSubscriberNames")
+	for _, name := range names {
+		assert.NotContains(t, name, "(*ConfigManager)")
+	}
+
+	slow := logs.FilterMessage("Slow config subscriber").All()
+	require.Len(t, slow, 3)
+	assert.Equal(t, names[1], slow[1].ContextMap()["callback"])
+}
@@ -0,0 +1,272 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// Decoder decodes a raw document into a nested settings map.
+type Decoder interface {
+	Decode(data []byte) (map[string]interface{}, error)
+}
+
+// Encoder encodes a nested settings map into a raw document.
+type Encoder interface {
+	Encode(settings map[string]interface{}) ([]byte, error)
+}
+
+// DecoderFunc adapts a function to the Decoder interface.
+type DecoderFunc func(data []byte) (map[string]interface{}, error)
+
+// Decode calls f(data).
+func (f DecoderFunc) Decode(data []byte) (map[string]interface{}, error) {
+	return f(data)
+}
+
+// EncoderFunc adapts a function to the Encoder interface.
+type EncoderFunc func(settings map[string]interface{}) ([]byte, error)
+
+// Encode calls f(settings).
+func (f EncoderFunc) Encode(settings map[string]interface{}) ([]byte, error) {
+	return f(settings)
+}
+
+var (
+	codecMu  sync.RWMutex
+	decoders = make(map[string]Decoder)
+	encoders = make(map[string]Encoder)
+
+	// mimeAliases maps well-known MIME types onto format names.
+	mimeAliases = map[string]string{
+		"application/json":   "json",
+		"text/json":          "json",
+		"application/yaml":   "yaml",
+		"application/x-yaml": "yaml",
+		"text/yaml":          "yaml",
+		"text/x-yaml":        "yaml",
+		"application/toml":   "toml",
+	}
+)
+
+func init() {
+	jsonDecoder := DecoderFunc(decodeJSON)
+	yamlDecoder := DecoderFunc(decodeYAML)
+	jsoncDecoder := DecoderFunc(func(data []byte) (map[string]interface{}, error) {
+		return decodeJSON(stripJSONC(data))
+	})
+
+	decoders["json"] = jsonDecoder
+	decoders["yaml"] = yamlDecoder
+	decoders["yml"] = yamlDecoder
+	decoders["toml"] = DecoderFunc(decodeTOML)
+	decoders["cue"] = DecoderFunc(func(data []byte) (map[string]interface{}, error) {
+		// Files and remote documents are decoded with their own name and
+		// key delimiter; this entry serves lookups without either.
+		return decodeCUE("config.cue", data, DefaultKeyDelimiter)
+	})
+	for ext := range jsoncExts {
+		decoders[ext] = jsoncDecoder
+	}
+	json5Decoder := DecoderFunc(func(data []byte) (map[string]interface{}, error) {
+		data, err := stripJSON5(data)
+		if err != nil {
+			return nil, err
+		}
+		return decodeJSON(data)
+	})
+	for ext := range json5Exts {
+		decoders[ext] = json5Decoder
+	}
+	// Remaining viper formats are decoded through a throwaway viper instance.
+	for _, ext := range viper.SupportedExts {
+		if _, ok := decoders[ext]; !ok {
+			decoders[ext] = viperDecoder(ext)
+		}
+	}
+
+	encoders["json"] = EncoderFunc(encodeJSON)
+	encoders["yaml"] = EncoderFunc(encodeYAML)
+	encoders["yml"] = EncoderFunc(encodeYAML)
+	encoders["toml"] = EncoderFunc(encodeTOML)
+}
+
+// RegisterDecoder registers a decoder for a format, keyed by file extension
+// (e.g. "xml") or MIME type (e.g. "application/xml"). It is used by local and
+// remote providers alike and replaces any decoder registered under the same key.
+func RegisterDecoder(format string, d Decoder) {
+	codecMu.Lock()
+	defer codecMu.Unlock()
+	decoders[codecKey(format)] = d
+}
+
+// RegisterEncoder registers an encoder for a format, keyed by file extension
+// or MIME type, for use when writing or exporting configuration.
+func RegisterEncoder(format string, e Encoder) {
+	codecMu.Lock()
+	defer codecMu.Unlock()
+	encoders[codecKey(format)] = e
+}
+
+// DecoderFormats returns the formats that can be decoded, sorted.
+func DecoderFormats() []string {
+	codecMu.RLock()
+	defer codecMu.RUnlock()
+	return sortedKeys(decoders)
+}
+
+// EncoderFormats returns the formats that can be encoded, sorted.
+func EncoderFormats() []string {
+	codecMu.RLock()
+	defer codecMu.RUnlock()
+	return sortedKeys(encoders)
+}
+
+func lookupDecoder(format string) (Decoder, error) {
+	codecMu.RLock()
+	defer codecMu.RUnlock()
+	key := codecKey(format)
+	if d, ok := decoders[key]; ok {
+		return d, nil
+	}
+	if d, ok := decoders[mimeAliases[key]]; ok {
+		return d, nil
+	}
+	return nil, viper.UnsupportedConfigError(format)
+}
+
+func lookupEncoder(format string) (Encoder, error) {
+	codecMu.RLock()
+	defer codecMu.RUnlock()
+	key := codecKey(format)
+	if e, ok := encoders[key]; ok {
+		return e, nil
+	}
+	if e, ok := encoders[mimeAliases[key]]; ok {
+		return e, nil
+	}
+	return nil, viper.UnsupportedConfigError(format)
+}
+
+// codecKey normalizes an extension or MIME type into a registry key.
+func codecKey(format string) string {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if strings.Contains(format, "/") {
+		if mediaType, _, err := mime.ParseMediaType(format); err == nil {
+			return mediaType
+		}
+	}
+	return strings.TrimPrefix(format, ".")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func decodeJSON(data []byte) (map[string]interface{}, error) {
+	settings := make(map[string]interface{})
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// decodeYAML decodes every document in a YAML stream and merges them in
+// order, so later documents override earlier ones. Anchors, aliases, and
+// merge keys are resolved by the decoder. Scalars tagged !age become
+// ENC[age:...] values.
+func decodeYAML(data []byte) (map[string]interface{}, error) {
+	settings := make(map[string]interface{})
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var node yaml.Node
+		if err := dec.Decode(&node); err != nil {
+			if errors.Is(err, io.EOF) {
+				return settings, nil
+			}
+			return nil, err
+		}
+		untagAge(&node)
+		var doc map[string]interface{}
+		if err := node.Decode(&doc); err != nil {
+			return nil, err
+		}
+		mergeSettings(settings, doc)
+	}
+}
+
+// untagAge rewrites the !age scalars under n as ENC[age:...] strings.
+func untagAge(n *yaml.Node) {
+	if n.Kind == yaml.ScalarNode && n.Tag == ageTag {
+		n.Tag = "!!str"
+		n.Value = agePrefix + n.Value + "]"
+		n.Style = 0
+	}
+	for _, child := range n.Content {
+		untagAge(child)
+	}
+}
+
+func decodeTOML(data []byte) (map[string]interface{}, error) {
+	settings := make(map[string]interface{})
+	if err := toml.Unmarshal(data, &settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// viperDecoder decodes a format natively supported by viper.
+func viperDecoder(format string) Decoder {
+	return DecoderFunc(func(data []byte) (map[string]interface{}, error) {
+		v := viper.New()
+		v.SetConfigType(format)
+		if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+			return nil, err
+		}
+		return v.AllSettings(), nil
+	})
+}
+
+func encodeJSON(settings map[string]interface{}) ([]byte, error) {
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error encoding JSON: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+func encodeYAML(settings map[string]interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(settings); err != nil {
+		return nil, fmt.Errorf("error encoding YAML: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeTOML(settings map[string]interface{}) ([]byte, error) {
+	data, err := toml.Marshal(settings)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding TOML: %w", err)
+	}
+	return data, nil
+}
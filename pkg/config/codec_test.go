@@ -0,0 +1,164 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// decodeKV decodes "dotted.key=value" lines into a nested settings map.
+func decodeKV(data []byte) (map[string]interface{}, error) {
+	settings := make(map[string]interface{})
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if ok {
+			setPath(settings, strings.Split(key, "."), value)
+		}
+	}
+	return settings, scanner.Err()
+}
+
+func TestCodecRegistry(t *testing.T) {
+	RegisterDecoder(".kv", DecoderFunc(decodeKV))
+	RegisterDecoder("application/x-kv", DecoderFunc(decodeKV))
+	logger, _ := zap.NewDevelopment()
+
+	t.Run("Custom Decoder For Local File", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "config.kv")
+		require.NoError(t, os.WriteFile(configPath, []byte("server.port=8080\nserver.host=localhost\n"), 0644))
+
+		cfg := New(configPath, logger)
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+		assert.Equal(t, "localhost", cfg.GetString("server.host"))
+	})
+
+	t.Run("Custom Decoder For Remote Document", func(t *testing.T) {
+		remote := useFakeRemote(t)
+		remote.set("app/config", []byte("server.port=9090\n"))
+
+		cfg := New("", logger, WithRemoteProvider(&RemoteProvider{
+			Type:     "consul",
+			Endpoint: "localhost:8500",
+			Path:     "app/config",
+			Format:   "application/x-kv; charset=utf-8",
+		}))
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 9090, cfg.GetInt("server.port"))
+	})
+
+	t.Run("Built-in Formats", func(t *testing.T) {
+		dir := t.TempDir()
+		files := map[string]string{
+			"config.toml": "[server]\nport = 8080\n",
+			"config.ini":  "[server]\nport = 8080\n",
+		}
+		for name, content := range files {
+			configPath := filepath.Join(dir, name)
+			require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+
+			cfg := New(configPath, logger)
+			require.NoError(t, cfg.Load(), name)
+			assert.Equal(t, 8080, cfg.GetInt("server.port"), name)
+		}
+	})
+
+	t.Run("Unsupported Format", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "config.unknown")
+		require.NoError(t, os.WriteFile(configPath, []byte("x"), 0644))
+
+		cfg := New(configPath, logger)
+		var unsupported viper.UnsupportedConfigError
+		assert.ErrorAs(t, cfg.Load(), &unsupported)
+	})
+
+	t.Run("Encoders", func(t *testing.T) {
+		RegisterEncoder("kv", EncoderFunc(func(map[string]interface{}) ([]byte, error) {
+			return []byte("ok"), nil
+		}))
+		assert.Contains(t, EncoderFormats(), "kv")
+		assert.Contains(t, DecoderFormats(), "application/x-kv")
+
+		enc, err := lookupEncoder("application/yaml")
+		require.NoError(t, err)
+		out, err := enc.Encode(map[string]interface{}{"server": map[string]interface{}{"port": 8080}})
+		require.NoError(t, err)
+		assert.Equal(t, "server:\n  port: 8080\n", string(out))
+
+		_, err = lookupEncoder("xml")
+		assert.Error(t, err)
+	})
+}
+
+func TestYAMLDocuments(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	load := func(t *testing.T, content string) *ConfigManager {
+		configPath := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+		cfg := New(configPath, logger)
+		require.NoError(t, cfg.Load())
+		return cfg
+	}
+
+	t.Run("Multiple Documents Merge In Order", func(t *testing.T) {
+		cfg := load(t, `
+server:
+  port: 8080
+  host: localhost
+---
+server:
+  port: 9090
+database:
+  name: testdb
+---
+`)
+		assert.Equal(t, 9090, cfg.GetInt("server.port"))
+		assert.Equal(t, "localhost", cfg.GetString("server.host"))
+		assert.Equal(t, "testdb", cfg.GetString("database.name"))
+	})
+
+	t.Run("Anchors And Merge Keys", func(t *testing.T) {
+		cfg := load(t, `
+defaults: &defaults
+  timeout: 30s
+  retries: 3
+services:
+  search:
+    <<: *defaults
+    retries: 5
+  billing:
+    <<: *defaults
+    endpoints: &endpoints [a, b]
+  audit:
+    endpoints: *endpoints
+`)
+		assert.Equal(t, "30s", cfg.GetString("services.search.timeout"))
+		assert.Equal(t, 5, cfg.GetInt("services.search.retries"))
+		assert.Equal(t, 3, cfg.GetInt("services.billing.retries"))
+		assert.Equal(t, []string{"a", "b"}, cfg.GetStringSlice("services.audit.endpoints"))
+		assert.False(t, cfg.IsSet("services.search.<<"))
+	})
+
+	t.Run("Merge Keys Across Documents", func(t *testing.T) {
+		cfg := load(t, `
+base: &base
+  level: info
+logging:
+  <<: *base
+---
+logging:
+  output: stdout
+`)
+		assert.Equal(t, "info", cfg.GetString("logging.level"))
+		assert.Equal(t, "stdout", cfg.GetString("logging.output"))
+	})
+}
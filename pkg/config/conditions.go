@@ -0,0 +1,276 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// WhenKey is the key that makes a block of a config file conditional, e.g.
+//
+//	tracing:
+//	  when: env == "prod" && ${TRACING} != "off"
+//	  sampler: 0.1
+//
+// A block whose expression is false is removed when the file is loaded, as
+// is a list element; the key itself is always removed. A top-level
+// expression makes the whole file conditional. Expressions compare
+// operands with == and !=, combine comparisons with &&, ||, ! and
+// parentheses, and a lone operand is true when it is not empty. Operands
+// are quoted strings, ${NAME} for the environment variable NAME (empty if
+// unset), and env or profile for the WithProfile profile. The value may
+// also be a plain boolean.
+const WhenKey = "when"
+
+// applyConditions evaluates the WhenKey expressions in settings, the
+// decoded content of the file at path, and returns the settings that remain.
+func applyConditions(path string, settings map[string]interface{}, profile string) (map[string]interface{}, error) {
+	keep, err := evalWhen(settings, profile)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if !keep {
+		return make(map[string]interface{}), nil
+	}
+	if err := filterConditions(settings, profile); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return settings, nil
+}
+
+// filterConditions removes the blocks of m whose condition is false, and
+// the condition keys of the others.
+func filterConditions(m map[string]interface{}, profile string) error {
+	for key, value := range m {
+		kept, keep, err := filterValue(value, profile)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		if keep {
+			m[key] = kept
+		} else {
+			delete(m, key)
+		}
+	}
+	return nil
+}
+
+// filterValue applies the conditions in value and reports whether it is
+// kept.
+func filterValue(value interface{}, profile string) (interface{}, bool, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keep, err := evalWhen(v, profile)
+		if err != nil || !keep {
+			return nil, false, err
+		}
+		return v, true, filterConditions(v, profile)
+	case []interface{}:
+		kept := make([]interface{}, 0, len(v))
+		for i, elem := range v {
+			elem, keep, err := filterValue(elem, profile)
+			if err != nil {
+				return nil, false, fmt.Errorf("[%d]: %w", i, err)
+			}
+			if keep {
+				kept = append(kept, elem)
+			}
+		}
+		return kept, true, nil
+	}
+	return value, true, nil
+}
+
+// evalWhen evaluates and removes the condition of the block m, if any.
+func evalWhen(m map[string]interface{}, profile string) (bool, error) {
+	var raw interface{}
+	for k, v := range m {
+		if strings.EqualFold(k, WhenKey) {
+			raw = v
+			delete(m, k)
+		}
+	}
+	switch v := raw.(type) {
+	case nil:
+		return true, nil
+	case bool:
+		return v, nil
+	case string:
+		keep, err := evalCondition(v, profile)
+		if err != nil {
+			return false, fmt.Errorf("invalid %s expression %q: %w", WhenKey, v, err)
+		}
+		return keep, nil
+	}
+	return false, fmt.Errorf("invalid %s: expected a string or boolean, got %T", WhenKey, raw)
+}
+
+// evalCondition evaluates a condition expression.
+func evalCondition(expr, profile string) (bool, error) {
+	tokens, err := tokenizeCondition(expr)
+	if err != nil {
+		return false, err
+	}
+	p := &conditionParser{tokens: tokens, profile: profile}
+	result, err := p.or()
+	if err != nil {
+		return false, err
+	}
+	if p.pos < len(p.tokens) {
+		return false, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	return result, nil
+}
+
+// conditionToken is a lexical token of a condition expression.
+type conditionToken struct {
+	kind conditionTokenKind
+	text string
+}
+
+type conditionTokenKind int
+
+const (
+	tokenOp conditionTokenKind = iota
+	tokenString
+	tokenVar
+	tokenIdent
+)
+
+// tokenizeCondition splits expr into tokens.
+func tokenizeCondition(expr string) ([]conditionToken, error) {
+	var tokens []conditionToken
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case strings.HasPrefix(expr[i:], "==") || strings.HasPrefix(expr[i:], "!=") ||
+			strings.HasPrefix(expr[i:], "&&") || strings.HasPrefix(expr[i:], "||"):
+			tokens = append(tokens, conditionToken{kind: tokenOp, text: expr[i : i+2]})
+			i += 2
+		case c == '!' || c == '(' || c == ')':
+			tokens = append(tokens, conditionToken{kind: tokenOp, text: string(c)})
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(expr[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string at offset %d", i)
+			}
+			tokens = append(tokens, conditionToken{kind: tokenString, text: expr[i+1 : i+1+end]})
+			i += end + 2
+		case strings.HasPrefix(expr[i:], "${"):
+			end := strings.IndexByte(expr[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated ${ at offset %d", i)
+			}
+			tokens = append(tokens, conditionToken{kind: tokenVar, text: expr[i+2 : i+end]})
+			i += end + 1
+		case isIdentByte(c):
+			start := i
+			for i < len(expr) && isIdentByte(expr[i]) {
+				i++
+			}
+			tokens = append(tokens, conditionToken{kind: tokenIdent, text: expr[start:i]})
+		default:
+			return nil, fmt.Errorf("unexpected %q at offset %d", c, i)
+		}
+	}
+	return tokens, nil
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+// conditionParser is a recursive descent parser that evaluates a condition
+// as it parses it.
+type conditionParser struct {
+	tokens  []conditionToken
+	pos     int
+	profile string
+}
+
+// accept consumes the next token if it is the operator op.
+func (p *conditionParser) accept(op string) bool {
+	if p.pos < len(p.tokens) && p.tokens[p.pos].kind == tokenOp && p.tokens[p.pos].text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// or parses and(|| and)*.
+func (p *conditionParser) or() (bool, error) {
+	result, err := p.and()
+	for err == nil && p.accept("||") {
+		var next bool
+		next, err = p.and()
+		result = result || next
+	}
+	return result, err
+}
+
+// and parses not(&& not)*.
+func (p *conditionParser) and() (bool, error) {
+	result, err := p.not()
+	for err == nil && p.accept("&&") {
+		var next bool
+		next, err = p.not()
+		result = result && next
+	}
+	return result, err
+}
+
+// not parses !not, (or), or a comparison.
+func (p *conditionParser) not() (bool, error) {
+	if p.accept("!") {
+		result, err := p.not()
+		return !result, err
+	}
+	if p.accept("(") {
+		result, err := p.or()
+		if err != nil {
+			return false, err
+		}
+		if !p.accept(")") {
+			return false, fmt.Errorf("missing )")
+		}
+		return result, nil
+	}
+
+	left, err := p.operand()
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case p.accept("=="):
+		right, err := p.operand()
+		return left == right, err
+	case p.accept("!="):
+		right, err := p.operand()
+		return left != right, err
+	}
+	return left != "", nil
+}
+
+// operand parses a string, variable, or identifier and returns its value.
+func (p *conditionParser) operand() (string, error) {
+	if p.pos >= len(p.tokens) {
+		return "", fmt.Errorf("unexpected end of expression")
+	}
+	tok := p.tokens[p.pos]
+	p.pos++
+	switch tok.kind {
+	case tokenString:
+		return tok.text, nil
+	case tokenVar:
+		return os.Getenv(tok.text), nil
+	case tokenIdent:
+		if tok.text == "env" || tok.text == "profile" {
+			return p.profile, nil
+		}
+		return "", fmt.Errorf("unknown identifier %q", tok.text)
+	}
+	return "", fmt.Errorf("unexpected %q", tok.text)
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestEvalCondition(t *testing.T) {
+	t.Setenv("FEATURE_X", "on")
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`env == "prod"`, true},
+		{`profile != 'prod'`, false},
+		{`${FEATURE_X} == "on"`, true},
+		{`${UNSET_FEATURE} == ""`, true},
+		{`${FEATURE_X}`, true},
+		{`!${UNSET_FEATURE}`, true},
+		{`env == "dev" || ${FEATURE_X} == "on"`, true},
+		{`env == "prod" && ${FEATURE_X} == "off"`, false},
+		{`!(env == "dev" || env == "staging")`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := evalCondition(tt.expr, "prod")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, expr := range []string{`env ==`, `region == "eu"`, `(env == "prod"`, `"prod`, `env = "prod"`, `env == "prod" "dev"`} {
+		t.Run("Invalid "+expr, func(t *testing.T) {
+			_, err := evalCondition(expr, "prod")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestConditionalSections(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+server:
+  port: 8080
+tracing:
+  when: env == "prod"
+  sampler: 0.1
+debug:
+  when: ${FEATURE_DEBUG} == "on"
+  verbose: true
+listeners:
+  - name: public
+  - name: admin
+    when: env != "prod"
+`), 0644))
+
+	t.Run("Prod", func(t *testing.T) {
+		cfg := New(path, zap.NewNop(), WithProfile("prod"))
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 0.1, cfg.GetFloat64("tracing.sampler"))
+		assert.False(t, cfg.IsSet("tracing.when"))
+		assert.False(t, cfg.IsSet("debug"))
+		assert.Equal(t, []interface{}{map[string]interface{}{"name": "public"}}, cfg.Get("listeners"))
+	})
+
+	t.Run("Dev With Feature", func(t *testing.T) {
+		t.Setenv("FEATURE_DEBUG", "on")
+		cfg := New(path, zap.NewNop(), WithProfile("dev"))
+		require.NoError(t, cfg.Load())
+		assert.False(t, cfg.IsSet("tracing"))
+		assert.True(t, cfg.GetBool("debug.verbose"))
+		assert.Len(t, cfg.Get("listeners"), 2)
+	})
+
+	t.Run("Whole File", func(t *testing.T) {
+		extra := filepath.Join(dir, "config.extra.yaml")
+		require.NoError(t, os.WriteFile(extra, []byte("when: env == \"prod\"\nserver:\n  port: 443\n"), 0644))
+		cfg := New(path, zap.NewNop(), WithOverrideFiles(extra))
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+	})
+
+	t.Run("Invalid Expression", func(t *testing.T) {
+		bad := filepath.Join(dir, "bad.yaml")
+		require.NoError(t, os.WriteFile(bad, []byte("tracing:\n  when: region == \"eu\"\n"), 0644))
+		err := New(bad, zap.NewNop()).Load()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "tracing: invalid when expression")
+	})
+}
@@ -19,19 +19,30 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"text/template"
 	"time"
 
+	"filippo.io/age"
 	"github.com/fsnotify/fsnotify"
 	"github.com/go-playground/validator/v10"
+	"github.com/spf13/cast"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 )
 
 // Config is the unified interface for reading and watching configuration.
+// configmock.ConfigMock implements it for unit tests of consumers.
+//
+//go:generate moq -out configmock/config_mock.go -pkg configmock . Config
 type Config interface {
 	Load() error
 	Get(key string) interface{}
@@ -43,27 +54,81 @@ type Config interface {
 	GetStringMap(key string) map[string]interface{}
 	GetDuration(key string) time.Duration
 	GetTime(key string) time.Time
+	GetInt32(key string) int32
+	GetInt64(key string) int64
+	GetUint(key string) uint
+	GetUint16(key string) uint16
+	GetUint32(key string) uint32
+	GetUint64(key string) uint64
+	GetIntSlice(key string) []int
+	GetStringMapString(key string) map[string]string
+	GetStringMapStringSlice(key string) map[string][]string
+	GetSizeInBytes(key string) uint
 	IsSet(key string) bool
+	Lookup(key string) (interface{}, bool)
+	GetStringOk(key string) (string, bool)
+	GetIntOk(key string) (int, bool)
+	GetFloat64Ok(key string) (float64, bool)
+	GetBoolOk(key string) (bool, bool)
+	GetDurationOk(key string) (time.Duration, bool)
 	GetSchema() interface{}
+	UnmarshalKey(key string, out interface{}) error
 	Watch(ctx context.Context, onChange func()) error
 	AllKeys() []string
 	AllSettings() map[string]interface{}
+	Set(key string, value interface{}) error
+	SetDefault(key string, value interface{}) error
+	Unset(key string) error
 }
 
+var (
+	_ Config = (*ConfigManager)(nil)
+	_ Config = (*SubConfig)(nil)
+	_ Config = (*ScopedConfig)(nil)
+)
+
 // RemoteProvider holds parameters for an external config source.
 // E.g., "etcd", "etcd3", "consul", "firestore", "nats"
 type RemoteProvider struct {
 	Type     string
 	Endpoint string
 	Path     string
+	// Format is the extension or MIME type of the stored document; defaults to "json".
+	Format string
+}
+
+// format returns the document format, defaulting to JSON.
+func (rp *RemoteProvider) format() string {
+	if rp.Format == "" {
+		return "json"
+	}
+	return rp.Format
+}
+
+// endpoint returns Endpoint without its userinfo, for logs, errors, spans,
+// and event sources, so credentials in e.g. "redis://:password@host" are
+// never reported.
+func (rp *RemoteProvider) endpoint() string {
+	if !strings.Contains(rp.Endpoint, "@") {
+		return rp.Endpoint
+	}
+	u, err := url.Parse(rp.Endpoint)
+	if err != nil || u.User == nil {
+		// Unparsable endpoints may still hold a password; keep only the host.
+		return rp.Endpoint[strings.LastIndex(rp.Endpoint, "@")+1:]
+	}
+	u.User = nil
+	return u.String()
 }
 
 // Option is a function that applies a configuration to the ConfigManager.
 type Option func(*ConfigManager)
 
 // ConfigProvider abstracts the configuration-providing responsibility.
+// Load populates the given viper instance from the provider's source; the
+// manager validates the result before it becomes the live configuration.
 type ConfigProvider interface {
-	Load() error
+	Load(v *viper.Viper) error
 }
 
 // ConfigWatcher abstracts the config watching responsibility.
@@ -73,21 +138,94 @@ type ConfigWatcher interface {
 
 // ConfigManager is the main facade that delegates to a provider and watcher.
 type ConfigManager struct {
-	viper          *viper.Viper
-	logger         *zap.Logger
-	provider       ConfigProvider
-	watcher        ConfigWatcher
-	schema         interface{}
-	defaults       map[string]interface{}
-	envPrefix      string
-	remoteProvider *RemoteProvider
-	pollInterval   time.Duration
-	watchEnabled   bool
-	validate       *validator.Validate
-	path           string
-	mu             sync.RWMutex
-	closed         bool
-	done           chan struct{}
+	viper *viper.Viper
+	live  atomic.Pointer[liveConfig]
+	// unresolved holds the live settings before env interpolation, key
+	// references, and secrets were resolved.
+	unresolved       map[string]interface{}
+	logger           logSink
+	provider         ConfigProvider
+	watcher          ConfigWatcher
+	schema           interface{}
+	defaults         map[string]interface{}
+	envPrefix        string
+	envBindings      map[string]string
+	remoteProvider   *RemoteProvider
+	remoteFallback   bool
+	remoteCache      string
+	flags            *pflag.FlagSet
+	pollInterval     time.Duration
+	watchEnabled     bool
+	validate         *validator.Validate
+	translator       *messageTranslator
+	schemaVersion    int
+	jsonnet          *JsonnetOptions
+	templateFuncs    template.FuncMap
+	configType       string
+	highFrequency    bool
+	precedence       []Source
+	runtime          runtimeLayers
+	optionErr        error
+	secretKeys       []string
+	secretResolvers  map[string]SecretResolver
+	ageIdentities    []age.Identity
+	resolvedSecrets  []string
+	rotated          []string
+	leases           map[string]*secretLease
+	deprecations     []Deprecation
+	deprecatedUsed   []Deprecation
+	migrations       map[int]migration
+	warnings         []*ValidationError
+	schemaSnapshot   atomic.Value
+	timeLayouts      []string
+	timeLocation     *time.Location
+	keyDelimiter     string
+	overrideFiles    []string
+	profile          string
+	mergeRules       map[string]mergeRule
+	bindings         []*Binding
+	sections         []section
+	annotations      Annotations
+	origins          map[string]Source
+	files            map[string]string
+	changes          []Change
+	settings         map[string]interface{}
+	leaves           map[string]interface{}
+	debounce         time.Duration
+	historySize      int
+	history          []Snapshot
+	generation       uint64
+	appliedHash      string
+	pin              *historyPin
+	reloadHooks      []func(v *viper.Viper)
+	subscribers      subscriberSet
+	subscriberBudget time.Duration
+	slowCallbacks    atomic.Uint64
+	lifecycle        lifecycleBus
+	onReloadError    func(error)
+	watchTargets     *watchRegistry
+	watchGate        watchGate
+	watchFanout      watchFanout
+	watchState       watchState
+	closeHooks       []func()
+	ttlSeq           uint64
+	freezeOnLoad     bool
+	noInterpolation  bool
+	frozen           atomic.Bool
+	ttlTimers        map[uint64]*time.Timer
+	catalogs         map[string]MessageCatalog
+	validationLocale string
+	path             string
+	mu               sync.RWMutex
+	loaded           bool
+	closed           bool
+	sup              *supervisor
+	telemetry        *telemetry
+	loads            uint64
+	loadErrors       uint64
+	partialLoads     uint64
+	rejectedSections uint64
+	lastLoad         time.Time
 }
 
 var (
@@ -95,63 +233,153 @@ var (
 	ErrClosed  = errors.New("config manager is closed")
 )
 
-// New creates a new ConfigManager using the provided file path, logger, and options.
+// SchemaVersionKey is the document key holding the schema version a remote
+// configuration was generated for.
+const SchemaVersionKey = "schemaVersion"
+
+// SchemaVersionError is returned when a remote configuration was generated for
+// a newer schema than the running binary understands.
+type SchemaVersionError struct {
+	Document  int
+	Supported int
+}
+
+func (e *SchemaVersionError) Error() string {
+	return fmt.Sprintf("config was generated for schema version %d, but this binary supports up to version %d",
+		e.Document, e.Supported)
+}
+
+// New creates a new ConfigManager using the provided file path, logger, and
+// options. A nil logger discards the manager's logs, unless WithSlog is given.
 func New(path string, logger *zap.Logger, opts ...Option) *ConfigManager {
+	if logger == nil {
+		return newManager(path, nopSink, opts...)
+	}
+	return newManager(path, zapSink{logger}, opts...)
+}
+
+// newManager creates a ConfigManager logging to logger, unless an option
+// such as WithSlog replaces it.
+func newManager(path string, logger logSink, opts ...Option) *ConfigManager {
 	cm := &ConfigManager{
-		viper:        viper.New(),
-		logger:       logger,
-		path:         path,
-		defaults:     make(map[string]interface{}),
-		pollInterval: 10 * time.Second, // default poll interval
-		watchEnabled: false,
-		validate:     validator.New(),
-		done:         make(chan struct{}),
+		logger:           logger,
+		path:             path,
+		defaults:         make(map[string]interface{}),
+		pollInterval:     10 * time.Second, // default poll interval
+		precedence:       DefaultPrecedence,
+		historySize:      defaultHistorySize,
+		subscriberBudget: defaultSubscriberBudget,
+		watchEnabled:     false,
+		validate:         validator.New(),
+		catalogs:         make(map[string]MessageCatalog),
+		sup:              newSupervisor(),
+		telemetry:        &telemetry{},
+		watchTargets:     newWatchRegistry(),
+		keyDelimiter:     DefaultKeyDelimiter,
 	}
 
 	// Apply provided options first so that schema, envPrefix, etc. are set.
 	for _, opt := range opts {
 		opt(cm)
 	}
+	logger = cm.logger
+	cm.storeViper(cm.newViper())
+	if cm.validate != nil {
+		registerUnitTypes(cm.validate)
+	}
+	cm.translator = newMessageTranslator(cm.validate, cm.validationLocale, cm.catalogs, logger)
+	// Copy the defaults, so tag defaults never touch the caller's map.
+	defaults := make(map[string]interface{}, len(cm.defaults))
+	for k, v := range cm.defaults {
+		defaults[k] = v
+	}
+	cm.defaults = defaults
+	if cm.schema != nil {
+		cm.addTagDefaults(cm.schema, "")
+	}
 
 	// Now that options have been applied, initialize provider and watcher.
+	cm.watchTargets.addFile(cm.path)
+	cm.resolveProfile()
 	if cm.remoteProvider != nil {
 		cm.provider = &RemoteConfigProvider{
-			viper:     cm.viper,
-			logger:    logger,
-			provider:  cm.remoteProvider,
-			defaults:  cm.defaults,
-			envPrefix: cm.envPrefix,
-			validate:  cm.validate,
-			schema:    cm.schema,
+			logger:      logger,
+			provider:    cm.remoteProvider,
+			path:        cm.path,
+			defaults:    cm.defaults,
+			envPrefix:   cm.envPrefix,
+			envNames:    cm.envBindings,
+			jsonnet:     cm.jsonnet,
+			funcs:       cm.templateFuncs,
+			profile:     cm.profile,
+			configType:  cm.configType,
+			interpolate: !cm.noInterpolation,
+			precedence:  cm.precedence,
+			flags:       cm.flags,
+			delimiter:   cm.keyDelimiter,
+			overrides:   cm.overrideFiles,
+			merge:       newMergePolicy(cm.mergeRules, cm.keyDelimiter),
+			version:     cm.schemaVersion,
+			fallback:    cm.remoteFallback,
+			cache:       cm.remoteCache,
+			tracker:     contentTracker{skipUnchanged: cm.highFrequency},
+			sup:         cm.sup,
+			telemetry:   cm.telemetry,
+			targets:     cm.watchTargets,
 		}
 		if cm.watchEnabled {
 			cm.watcher = &RemoteConfigWatcher{
-				viper:        cm.viper,
-				logger:       logger,
-				pollInterval: cm.pollInterval,
-				provider:     cm.remoteProvider,
+				logger:          logger,
+				pollInterval:    cm.pollInterval,
+				provider:        cm.remoteProvider,
+				sup:             cm.sup,
+				onError:         cm.reportReloadError,
+				appliedRevision: cm.RemoteRevision,
+				status:          &cm.watchState,
+				files: &LocalConfigWatcher{
+					path:     cm.path,
+					logger:   logger,
+					sup:      cm.sup,
+					debounce: cm.debounce,
+					targets:  cm.watchTargets,
+				},
 			}
 		}
 	} else {
 		cm.provider = &LocalConfigProvider{
-			viper:     cm.viper,
-			logger:    logger,
-			path:      cm.path,
-			defaults:  cm.defaults,
-			envPrefix: cm.envPrefix,
-			validate:  cm.validate,
-			schema:    cm.schema,
+			logger:      logger,
+			path:        cm.path,
+			defaults:    cm.defaults,
+			envPrefix:   cm.envPrefix,
+			envNames:    cm.envBindings,
+			jsonnet:     cm.jsonnet,
+			funcs:       cm.templateFuncs,
+			profile:     cm.profile,
+			configType:  cm.configType,
+			interpolate: !cm.noInterpolation,
+			precedence:  cm.precedence,
+			flags:       cm.flags,
+			delimiter:   cm.keyDelimiter,
+			overrides:   cm.overrideFiles,
+			merge:       newMergePolicy(cm.mergeRules, cm.keyDelimiter),
+			tracker:     contentTracker{skipUnchanged: cm.highFrequency},
+			targets:     cm.watchTargets,
 		}
 		cm.watcher = &LocalConfigWatcher{
-			viper:  cm.viper,
-			logger: logger,
+			path:     cm.path,
+			logger:   logger,
+			sup:      cm.sup,
+			debounce: cm.debounce,
+			targets:  cm.watchTargets,
+			status:   &cm.watchState,
 		}
 	}
 
 	return cm
 }
 
-// Close gracefully shuts down the config manager and its watchers
+// Close gracefully shuts down the config manager and waits for all of its
+// background goroutines to return.
 func (cm *ConfigManager) Close() error {
 	cm.mu.Lock()
 	if cm.closed {
@@ -159,314 +387,934 @@ func (cm *ConfigManager) Close() error {
 		return nil
 	}
 	cm.closed = true
-	close(cm.done)
+	for _, hook := range cm.closeHooks {
+		hook()
+	}
 	cm.mu.Unlock()
 
 	// Stop the watcher if it implements cleanup
-	if w, ok := cm.watcher.(*LocalConfigWatcher); ok {
+	if w, ok := cm.watcher.(interface{ Stop() error }); ok {
 		if err := w.Stop(); err != nil {
 			cm.logger.Error("Error stopping watcher", zap.Error(err))
 			return err
 		}
 	}
 
+	if err := cm.sup.stop(shutdownTimeout); err != nil {
+		cm.logger.Error("Error stopping background goroutines", zap.Error(err))
+		return err
+	}
+	cm.emit(LifecycleEvent{Kind: EventClosed, Source: cm.sourceName(), Generation: cm.Generation()})
+	cm.flushLifecycle()
 	return nil
 }
 
 // Load delegates to the underlying config provider.
 func (cm *ConfigManager) Load() error {
+	defer cm.flushLifecycle()
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
 
 	if cm.closed {
 		return ErrClosed
 	}
-	return cm.provider.Load()
+	return cm.load()
+}
+
+// load runs a staged load and records its outcome. The caller must hold cm.mu.
+func (cm *ConfigManager) load() error {
+	if cm.frozen.Load() {
+		return ErrFrozen
+	}
+	cm.loads++
+	first := !cm.loaded
+	start, span := time.Now(), cm.telemetry.startLoad(cm.sourceAttributes()...)
+	err := cm.optionErr
+	if err == nil {
+		err = cm.stage()
+	}
+	// A partial apply has swapped in everything but the rejected sections,
+	// so it counts as applied.
+	var partial *PartialApplyError
+	applied := err == nil || errors.As(err, &partial)
+	if applied {
+		span.SetAttributes(
+			Attribute{Key: "config.hash", Value: cm.appliedHash},
+			Attribute{Key: "config.generation", Value: int64(cm.generation)})
+	}
+	cm.telemetry.endLoad(span, start, err, cm.sourceAttributes()[0])
+	cm.emitLoad(first, err)
+	switch {
+	case !applied:
+		cm.loadErrors++
+	case partial != nil:
+		cm.partialLoads++
+		cm.rejectedSections += uint64(len(partial.Rejected))
+	}
+	if applied && cm.freezeOnLoad {
+		cm.freeze()
+	}
+	return err
+}
+
+// stage reads the provider into a staging viper instance, validates it, and
+// swaps it in as the live configuration.
+func (cm *ConfigManager) stage() error {
+	cm.changes, cm.rotated = nil, nil
+	next := cm.newViper()
+	// A change to a watched file the provider does not hash must reload.
+	if cm.watchTargets.takeDirty() {
+		if c, ok := cm.provider.(committer); ok {
+			c.invalidate()
+		}
+	}
+	if err := cm.provider.Load(next); err != nil {
+		if errors.Is(err, errNotModified) {
+			return nil
+		}
+		return err
+	}
+	next, err := cm.applyMigrations(next)
+	if err != nil {
+		return err
+	}
+	next, deprecated, err := cm.applyDeprecations(next)
+	if err != nil {
+		return err
+	}
+	next, pinTemplates, err := cm.applyPin(next)
+	if err != nil {
+		return err
+	}
+	next, err = cm.applyRuntime(next)
+	if err != nil {
+		return err
+	}
+	unresolved := deepCopyMap(next.AllSettings())
+	if cm.pin != nil {
+		cm.restoreTemplates(unresolved, pinTemplates)
+	} else if t, ok := cm.provider.(templateSource); ok {
+		cm.restoreTemplates(unresolved, t.lastTemplates())
+	}
+	next, err = cm.applyReferences(next)
+	if err != nil {
+		return err
+	}
+	next, secrets, err := cm.applySecrets(next, false)
+	if err != nil {
+		return err
+	}
+
+	next, rejected, err := cm.applySections(next)
+	if err != nil {
+		return err
+	}
+	for _, r := range rejected {
+		path := cm.splitKey(r.Section)
+		if old, ok := lookupPath(cm.unresolved, path); ok {
+			setPath(unresolved, path, deepCopyValue(old))
+		} else {
+			deletePath(unresolved, path)
+		}
+	}
+
+	if cm.schema != nil {
+		if err := cm.decodeSchema(next, "", cm.schema); err != nil {
+			return err
+		}
+		cm.storeSchemaSnapshot()
+	}
+	if err := cm.stageBindings(next); err != nil {
+		return err
+	}
+
+	cm.resolvedSecrets = secrets.keys
+	cm.pruneLeases(secrets.refs)
+	cm.recordChanges(next)
+	cm.recordRotations()
+	cm.recordWarnings()
+	cm.storeViper(next)
+	cm.unresolved = unresolved
+	cm.deprecatedUsed = deprecated
+	cm.applyBindings()
+	cm.loaded = true
+	cm.lastLoad = time.Now()
+	cm.logger.Debug("Configuration loaded",
+		zap.Any("settings", cm.redact(cm.settings)))
+	if c, ok := cm.provider.(committer); ok {
+		c.commit()
+	}
+	cm.recordAnnotations()
+	cm.recordGeneration()
+	cm.recordHistory()
+	for _, hook := range cm.reloadHooks {
+		hook(next)
+	}
+
+	if len(rejected) > 0 {
+		return &PartialApplyError{Rejected: rejected}
+	}
+	return nil
+}
+
+// decodeSchema unmarshals the subtree at key (or the whole document when key
+// is empty) into a fresh copy of target's type and validates it. target is
+// only overwritten once validation has passed.
+func (cm *ConfigManager) decodeSchema(v *viper.Viper, key string, target interface{}) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("schema must be a non-nil pointer, got %T", target)
+	}
+
+	staged := reflect.New(rv.Elem().Type())
+	attrs := []Attribute{{Key: "config.key", Value: key}, {Key: "config.schema", Value: rv.Elem().Type().String()}}
+	span := cm.telemetry.startSpan(SpanUnmarshal, attrs...)
+	var err error
+	if key == "" {
+		err = v.Unmarshal(staged.Interface(), cm.decodeHook())
+	} else {
+		err = v.UnmarshalKey(key, staged.Interface(), cm.decodeHook())
+	}
+	endSpan(span, err)
+	if err != nil {
+		return err
+	}
+
+	span = cm.telemetry.startSpan(SpanValidate, attrs...)
+	err = cm.validateSchemaAt(staged.Interface(), key)
+	endSpan(span, err)
+	if err != nil {
+		return err
+	}
+
+	rv.Elem().Set(staged.Elem())
+	return nil
+}
+
+// validateSchema runs struct validation against schema decoded from the
+// root of the configuration.
+func (cm *ConfigManager) validateSchema(schema interface{}) error {
+	return cm.validateSchemaAt(schema, "")
+}
+
+// validateSchemaAt runs struct validation against schema decoded from the
+// subtree at key. Every failed rule is reported as a *ValidationError in a
+// ValidationErrors, with the field named by its config key. Once the tags
+// pass, a schema implementing SchemaValidator checks its own invariants.
+func (cm *ConfigManager) validateSchemaAt(schema interface{}, key string) error {
+	if err := cm.validateTags(schema, key); err != nil {
+		return err
+	}
+	if sv, ok := schema.(SchemaValidator); ok {
+		if err := sv.Validate(); err != nil {
+			var verr *ValidationError
+			if errors.As(err, &verr) {
+				return err
+			}
+			return fmt.Errorf("validation failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateTags runs the validate tags of schema.
+func (cm *ConfigManager) validateTags(schema interface{}, key string) error {
+	if cm.validate == nil {
+		return nil
+	}
+
+	err := cm.validate.StructCtx(context.Background(), schema)
+	if err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			root := reflect.TypeOf(schema)
+			secrets := cm.secretPatterns()
+			errs := make(ValidationErrors, len(validationErrors))
+			for i, e := range validationErrors {
+				path := configKeyPath(root, e.StructNamespace(), cm.keyDelimiter)
+				if key != "" {
+					path = key + cm.keyDelimiter + path
+				}
+				value := e.Value()
+				if isSecret(path, secrets, cm.keyDelimiter) {
+					value = RedactedValue
+				}
+				errs[i] = &ValidationError{
+					Key:     path,
+					Value:   value,
+					Rule:    e.Tag(),
+					Param:   e.Param(),
+					Message: cm.translator.translate(e),
+				}
+			}
+			return errs
+		}
+		return err
+	}
+	return nil
 }
 
 // Get returns a value for the given key.
 func (cm *ConfigManager) Get(key string) interface{} {
-	cm.mu.RLock()
-	defer cm.mu.RUnlock()
-	return cm.viper.Get(key)
+	return cm.current().Get(key)
 }
 
 // GetString returns a string value for the given key.
 func (cm *ConfigManager) GetString(key string) string {
-	cm.mu.RLock()
-	defer cm.mu.RUnlock()
-	return cm.viper.GetString(key)
+	return cachedValue(cm, stringValue, key, (*viper.Viper).GetString)
 }
 
 // GetInt returns an integer value for the given key.
 func (cm *ConfigManager) GetInt(key string) int {
-	cm.mu.RLock()
-	defer cm.mu.RUnlock()
-	return cm.viper.GetInt(key)
+	return cachedValue(cm, intValue, key, (*viper.Viper).GetInt)
 }
 
 // GetFloat64 returns a float64 value for the given key.
 func (cm *ConfigManager) GetFloat64(key string) float64 {
-	cm.mu.RLock()
-	defer cm.mu.RUnlock()
-	return cm.viper.GetFloat64(key)
+	return cachedValue(cm, float64Value, key, (*viper.Viper).GetFloat64)
 }
 
 // GetBool returns a boolean value for the given key.
 func (cm *ConfigManager) GetBool(key string) bool {
-	cm.mu.RLock()
-	defer cm.mu.RUnlock()
-	return cm.viper.GetBool(key)
+	return cachedValue(cm, boolValue, key, (*viper.Viper).GetBool)
 }
 
 // GetStringSlice returns a string slice value for the given key.
 func (cm *ConfigManager) GetStringSlice(key string) []string {
-	cm.mu.RLock()
-	defer cm.mu.RUnlock()
-	return cm.viper.GetStringSlice(key)
+	return slices.Clone(cachedValue(cm, stringSliceValue, key, (*viper.Viper).GetStringSlice))
 }
 
 // GetStringMap returns a map[string]interface{} value for the given key.
 func (cm *ConfigManager) GetStringMap(key string) map[string]interface{} {
-	cm.mu.RLock()
-	defer cm.mu.RUnlock()
-	return cm.viper.GetStringMap(key)
+	return cm.current().GetStringMap(key)
 }
 
 // GetDuration returns a duration value for the given key.
 func (cm *ConfigManager) GetDuration(key string) time.Duration {
-	cm.mu.RLock()
-	defer cm.mu.RUnlock()
-	return cm.viper.GetDuration(key)
+	return cachedValue(cm, durationValue, key, (*viper.Viper).GetDuration)
 }
 
-// GetTime returns a time.Time value for the given key.
+// GetTime returns a time.Time value for the given key, parsed with the
+// WithTimeLayouts layouts and WithTimeLocation location if set.
 func (cm *ConfigManager) GetTime(key string) time.Time {
-	cm.mu.RLock()
-	defer cm.mu.RUnlock()
-	return cm.viper.GetTime(key)
+	return cachedValue(cm, timeValue, key, func(v *viper.Viper, key string) time.Time {
+		t, _ := cm.parseTime(v.Get(key))
+		return t
+	})
+}
+
+// GetInt32 returns an int32 value for the given key.
+func (cm *ConfigManager) GetInt32(key string) int32 {
+	return cachedValue(cm, int32Value, key, (*viper.Viper).GetInt32)
+}
+
+// GetInt64 returns an int64 value for the given key.
+func (cm *ConfigManager) GetInt64(key string) int64 {
+	return cachedValue(cm, int64Value, key, (*viper.Viper).GetInt64)
+}
+
+// GetUint returns a uint value for the given key.
+func (cm *ConfigManager) GetUint(key string) uint {
+	return cachedValue(cm, uintValue, key, (*viper.Viper).GetUint)
+}
+
+// GetUint16 returns a uint16 value for the given key.
+func (cm *ConfigManager) GetUint16(key string) uint16 {
+	return cachedValue(cm, uint16Value, key, (*viper.Viper).GetUint16)
+}
+
+// GetUint32 returns a uint32 value for the given key.
+func (cm *ConfigManager) GetUint32(key string) uint32 {
+	return cachedValue(cm, uint32Value, key, (*viper.Viper).GetUint32)
+}
+
+// GetUint64 returns a uint64 value for the given key.
+func (cm *ConfigManager) GetUint64(key string) uint64 {
+	return cachedValue(cm, uint64Value, key, (*viper.Viper).GetUint64)
+}
+
+// GetIntSlice returns an int slice value for the given key.
+func (cm *ConfigManager) GetIntSlice(key string) []int {
+	return slices.Clone(cachedValue(cm, intSliceValue, key, (*viper.Viper).GetIntSlice))
+}
+
+// GetStringMapString returns a string map value for the given key.
+func (cm *ConfigManager) GetStringMapString(key string) map[string]string {
+	return cm.current().GetStringMapString(key)
+}
+
+// GetStringMapStringSlice returns a string slice map value for the given key.
+func (cm *ConfigManager) GetStringMapStringSlice(key string) map[string][]string {
+	return cm.current().GetStringMapStringSlice(key)
+}
+
+// GetSizeInBytes returns the value for the given key as a number of bytes, parsing
+// sizes such as "10mb" the way viper does.
+func (cm *ConfigManager) GetSizeInBytes(key string) uint {
+	return cachedValue(cm, sizeInBytesValue, key, (*viper.Viper).GetSizeInBytes)
 }
 
 // IsSet returns true if the key is set in the configuration.
 func (cm *ConfigManager) IsSet(key string) bool {
-	cm.mu.RLock()
-	defer cm.mu.RUnlock()
-	return cm.viper.IsSet(key)
+	return cm.current().IsSet(key)
 }
 
 // GetSchema returns the configured schema (if any).
 func (cm *ConfigManager) GetSchema() interface{} {
-	cm.mu.RLock()
-	defer cm.mu.RUnlock()
+	defer cm.rlock()()
 	return cm.schema
 }
 
-// Watch delegates to the underlying config watcher.
-func (cm *ConfigManager) Watch(ctx context.Context, onChange func()) error {
-	if cm.watcher != nil {
-		return cm.watcher.Watch(ctx, func() {
-			if err := cm.provider.Load(); err != nil {
-				cm.logger.Error("Failed to reload configuration", zap.Error(err))
-			}
-			onChange()
-		})
+// UnmarshalKey decodes the subtree at key into out, a pointer to a struct,
+// map, or slice, so a module can bind only its own settings without
+// depending on the application schema. Decoding works as for the schema,
+// including mapstructure tags, Size, and Duration.
+func (cm *ConfigManager) UnmarshalKey(key string, out interface{}) error {
+	if err := cm.current().UnmarshalKey(key, out, cm.decodeHook()); err != nil {
+		return fmt.Errorf("error decoding config key '%s': %w", key, err)
 	}
 	return nil
 }
 
+// Watch reloads the configuration whenever the underlying config watcher
+// detects a change, and calls onChange after each reload until ctx is
+// done. Watch may be called any number of times, also alongside
+// WatchEvents and Subscribe: all of them share one run of the watcher.
+func (cm *ConfigManager) Watch(ctx context.Context, onChange func()) error {
+	return cm.watchReloads(ctx, func(ChangeEvent) { onChange() })
+}
+
 // AllKeys returns all keys holding a value in the configuration.
 func (cm *ConfigManager) AllKeys() []string {
-	cm.mu.RLock()
-	defer cm.mu.RUnlock()
-	return cm.viper.AllKeys()
+	return cm.current().AllKeys()
 }
 
-// AllSettings returns all settings in the configuration.
+// AllSettings returns all settings in the configuration as a nested map.
+// The map is a deep copy: nested maps and slices can be modified freely
+// without affecting later reads.
 func (cm *ConfigManager) AllSettings() map[string]interface{} {
-	cm.mu.RLock()
-	defer cm.mu.RUnlock()
-	return cm.viper.AllSettings()
+	return deepCopyMap(cm.current().AllSettings())
 }
 
 // LocalConfigProvider implements ConfigProvider for file-based + ENV configs.
 type LocalConfigProvider struct {
-	viper     *viper.Viper
-	logger    *zap.Logger
-	path      string
-	defaults  map[string]interface{}
-	envPrefix string
-	validate  *validator.Validate
-	schema    interface{}
+	logger      logSink
+	path        string
+	defaults    map[string]interface{}
+	envPrefix   string
+	envNames    map[string]string
+	jsonnet     *JsonnetOptions
+	funcs       template.FuncMap
+	profile     string
+	configType  string
+	interpolate bool
+	precedence  []Source
+	flags       *pflag.FlagSet
+	delimiter   string
+	overrides   []string
+	merge       *mergePolicy
+	tracker     contentTracker
+	origins     map[string]Source
+	files       map[string]string
+	trace       *fileTrace
+	targets     *watchRegistry
+	// data, if set, is read as the content of the config file at path.
+	data []byte
+	// templates records the values env interpolation changed.
+	templates map[string]interpolation
 }
 
-func (l *LocalConfigProvider) Load() error {
-	// Clear all settings but keep the instance
-	l.viper.AllSettings()
-	l.viper.AllKeys()
-	for _, key := range l.viper.AllKeys() {
-		l.viper.Set(key, nil)
-	}
+// commit marks the last read file content as applied.
+func (l *LocalConfigProvider) commit() {
+	l.tracker.commit()
+}
 
-	// Set defaults
-	for key, value := range l.defaults {
-		l.viper.SetDefault(key, value)
-		l.logger.Debug("Setting default value",
-			zap.String("key", key),
-			zap.Any("value", value))
-	}
+// lastOrigins returns the source of each key in the last loaded configuration.
+func (l *LocalConfigProvider) lastOrigins() map[string]Source {
+	return l.origins
+}
 
-	// Configure environment variables
-	if l.envPrefix != "" {
-		l.viper.SetEnvPrefix(l.envPrefix)
-		l.viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-		l.viper.AutomaticEnv()
-	}
+// lastFiles returns the file each key taken from File was read from.
+func (l *LocalConfigProvider) lastFiles() map[string]string {
+	return l.files
+}
+
+// lastTemplates returns the values env interpolation changed in the last
+// load.
+func (l *LocalConfigProvider) lastTemplates() map[string]interpolation {
+	return l.templates
+}
+
+// sourceHash returns the hash of the last read file content.
+func (l *LocalConfigProvider) sourceHash() string {
+	return l.tracker.pending
+}
 
-	// Set config type based on file extension
-	if ext := filepath.Ext(l.path); ext != "" {
-		l.viper.SetConfigType(strings.TrimPrefix(ext, "."))
+// invalidate forces the next load to re-read the file.
+func (l *LocalConfigProvider) invalidate() {
+	l.tracker.invalidate()
+}
+
+func (l *LocalConfigProvider) Load(v *viper.Viper) error {
+	sources := layers{}
+	l.trace = &fileTrace{}
+
+	// Set defaults
+	for key := range l.defaults {
+		l.logger.Debug("Setting default value", zap.String("key", key))
 	}
+	sources.setDefaults(l.defaults, l.delimiter)
 
-	// Load the config file if it exists
-	if _, err := os.Stat(l.path); err == nil {
-		l.viper.SetConfigFile(l.path)
-		if err := l.viper.ReadInConfig(); err != nil {
+	// Load the config file and override files if they exist
+	if _, err := os.Stat(l.path); err == nil || l.data != nil {
+		v.SetConfigFile(l.path)
+		if err := l.readConfig(sources, true); err != nil {
+			if errors.Is(err, errNotModified) {
+				return err
+			}
 			return fmt.Errorf("error reading config file: %w", err)
 		}
-	} else if os.IsNotExist(err) && len(l.defaults) == 0 {
+	} else if os.IsNotExist(err) && len(l.defaults) == 0 && len(l.envNames) == 0 {
 		return fmt.Errorf("no configuration file found at %s and no defaults provided", l.path)
 	} else if !os.IsNotExist(err) {
 		return fmt.Errorf("error checking config file: %w", err)
+	} else if err := l.readConfig(sources, false); err != nil {
+		if errors.Is(err, errNotModified) {
+			return err
+		}
+		return fmt.Errorf("error reading config file: %w", err)
+	}
+
+	// Merge the sources, including environment variables, by precedence
+	sources.setFlags(l.flags, l.delimiter)
+	origins, err := sources.apply(v, l.precedence, l.envPrefix, l.envNames, l.delimiter, l.merge)
+	if err != nil {
+		return err
 	}
+	bindFlags(v, l.flags, l.precedence, l.envPrefix)
+	l.origins = origins
+	l.files = l.trace.locate(origins, l.delimiter)
 
-	// Log loaded configuration for debugging
-	l.logger.Debug("Configuration loaded",
-		zap.Any("settings", l.viper.AllSettings()))
+	return nil
+}
 
-	// Unmarshal into schema if provided
-	if l.schema != nil {
-		if err := l.viper.Unmarshal(l.schema); err != nil {
+// readConfig reads the config file into the file layer, unless its content
+// is unchanged and may be skipped.
+func (l *LocalConfigProvider) readConfig(sources layers, exists bool) error {
+	data := l.data
+	if exists && data == nil {
+		var err error
+		if data, err = os.ReadFile(l.path); err != nil {
 			return err
 		}
-		// Validate the loaded configuration
-		if l.validate != nil {
-			if err := l.validateConfig(); err != nil {
-				return err
-			}
-		}
+	}
+	overrides, err := readOverrideFiles(l.overrides)
+	if err != nil {
+		return err
+	}
+	if err := l.tracker.observe(hashContent(append([][]byte{data}, overrideContent(overrides)...)...)); err != nil {
+		return err
+	}
+	if !exists && len(l.overrides) == 0 {
+		return nil
 	}
 
+	settings := make(map[string]interface{})
+	if exists {
+		if settings, err = decodeConfigFile(l.logger, l.path, data, l.configType, l.delimiter, l.jsonnet, l.funcs, l.profile, l.targets, l.trace); err != nil {
+			return err
+		}
+	}
+	if settings, err = mergeOverrideFiles(l.logger, settings, overrides, l.configType, l.delimiter, l.jsonnet, l.funcs, l.profile, l.targets, l.merge, l.trace); err != nil {
+		return err
+	}
+	l.templates = make(map[string]interpolation)
+	if l.interpolate {
+		if err := interpolateEnv(settings, l.delimiter, l.templates); err != nil {
+			return err
+		}
+	}
+	sources.set(File, settings)
 	return nil
 }
 
-func (l *LocalConfigProvider) validateConfig() error {
-	if l.schema == nil || l.validate == nil {
-		return nil
+// decodeConfigFile decodes a config file, evaluates its WhenKey conditions
+// for profile, and merges the files it includes through IncludeKey,
+// recording each file in trace.
+func decodeConfigFile(logger logSink, path string, data []byte, configType, delimiter string, jsonnet *JsonnetOptions,
+	funcs template.FuncMap, profile string, targets *watchRegistry, trace *fileTrace) (map[string]interface{}, error) {
+	settings, err := decodeFile(logger, path, data, configType, delimiter, jsonnet, funcs, targets)
+	if err != nil {
+		return nil, err
 	}
+	return decodeIncludes(logger, path, settings, delimiter, jsonnet, funcs, profile, targets, trace, nil)
+}
 
-	err := l.validate.StructCtx(context.Background(), l.schema)
+// decodeFile decodes a config file with the codec registered for its
+// format: the explicit config type if set, otherwise the file extension, or
+// a guess from the content for files without one. With templating, the file
+// is rendered first. Jsonnet files are then evaluated to JSON, since their
+// imports resolve relative to the file; the imported files are added to
+// targets so the watcher covers them. CUE files are compiled under their
+// own name, and their errors name keys joined with delimiter.
+func decodeFile(logger logSink, path string, data []byte, configType, delimiter string, jsonnet *JsonnetOptions,
+	funcs template.FuncMap, targets *watchRegistry) (map[string]interface{}, error) {
+	data, err := renderTemplate(path, data, funcs)
 	if err != nil {
-		var validationErrors validator.ValidationErrors
-		if errors.As(err, &validationErrors) {
-			for _, e := range validationErrors {
-				l.viper.Get(e.Namespace()) // Log the actual value
-				return fmt.Errorf("validation failed for field '%s': %s",
-					e.Namespace(), e.Tag())
-			}
+		return nil, err
+	}
+	format := configType
+	if format == "" {
+		format = strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+	}
+	if format == "" {
+		format = sniffFormat(data)
+		logger.Debug("Detected config format from content",
+			zap.String("path", path),
+			zap.String("format", format))
+	}
+	if format == "jsonnet" {
+		evaluated, imports, err := evaluateJsonnet(path, data, jsonnet)
+		if err != nil {
+			return nil, err
 		}
-		return err
+		for _, imported := range imports {
+			targets.addFile(imported)
+		}
+		data, format = evaluated, "json"
 	}
-	return nil
+	if format == "cue" {
+		return decodeCUE(path, data, delimiter)
+	}
+
+	dec, err := lookupDecoder(format)
+	if err != nil {
+		return nil, err
+	}
+	return dec.Decode(data)
 }
 
 // RemoteConfigProvider implements ConfigProvider for remote configs.
 type RemoteConfigProvider struct {
-	viper     *viper.Viper
-	logger    *zap.Logger
-	provider  *RemoteProvider
-	defaults  map[string]interface{}
-	envPrefix string
-	validate  *validator.Validate
-	schema    interface{}
+	logger      logSink
+	provider    *RemoteProvider
+	path        string
+	defaults    map[string]interface{}
+	envPrefix   string
+	envNames    map[string]string
+	jsonnet     *JsonnetOptions
+	funcs       template.FuncMap
+	profile     string
+	configType  string
+	interpolate bool
+	precedence  []Source
+	flags       *pflag.FlagSet
+	delimiter   string
+	overrides   []string
+	merge       *mergePolicy
+	version     int
+	annotations Annotations
+	origins     map[string]Source
+	files       map[string]string
+	templates   map[string]interpolation
+	tracker     contentTracker
+	sup         *supervisor
+	telemetry   *telemetry
+	targets     *watchRegistry
+	// fallback and cache configure WithRemoteFallback.
+	fallback bool
+	cache    string
+	// revision is the hash of the last applied remote document.
+	revision        string
+	pendingRevision string
+	// stale reports that the applied document came from the fallback.
+	stale        bool
+	pendingStale bool
+	pendingData  []byte
+}
+
+// remoteResult carries the outcome of a remote fetch.
+type remoteResult struct {
+	annotations Annotations
+	origins     map[string]Source
+	files       map[string]string
+	templates   map[string]interpolation
+	hash        string
+	revision    string
+	// data is the fetched remote document, to be cached once applied.
+	data  []byte
+	stale bool
+	err   error
 }
 
-func (r *RemoteConfigProvider) Load() error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func (r *RemoteConfigProvider) Load(v *viper.Viper) error {
+	ctx, cancel := context.WithTimeout(r.sup.ctx, 30*time.Second)
 	defer cancel()
 
-	errCh := make(chan error, 1)
-	go func() {
-		// Configure the remote provider.
-		if err := r.viper.AddRemoteProvider(
-			r.provider.Type,
-			r.provider.Endpoint,
-			r.provider.Path,
-		); err != nil {
-			r.logger.Error("Failed to add remote provider",
-				zap.String("type", r.provider.Type),
-				zap.String("endpoint", r.provider.Endpoint),
-				zap.Error(err))
-			errCh <- err
-			return
+	applied := r.tracker.applied
+	fallback := r.fallback && r.revision == ""
+	tel := r.telemetry.snapshot()
+	resultCh := make(chan remoteResult, 1)
+	started := r.sup.Go("remote-fetch", func(context.Context) {
+		fail := func(err error) {
+			resultCh <- remoteResult{err: err}
 		}
 
-		// Set the configuration type (e.g., JSON).
-		r.viper.SetConfigType("json")
+		sources := layers{}
+		sources.setDefaults(r.defaults, r.delimiter)
+		trace := &fileTrace{}
 
-		// Set defaults and environment prefix.
-		for key, value := range r.defaults {
-			r.viper.SetDefault(key, value)
+		// Read the local file, if any, as a fallback layer.
+		fileData, err := r.readFallbackFile()
+		if err != nil {
+			fail(fmt.Errorf("error reading config file: %w", err))
+			return
 		}
-		if r.envPrefix != "" {
-			r.viper.SetEnvPrefix(r.envPrefix)
-			r.viper.AutomaticEnv()
+		overrides, err := readOverrideFiles(r.overrides)
+		if err != nil {
+			fail(err)
+			return
 		}
 
 		// Read remote configuration.
-		if err := r.viper.ReadRemoteConfig(); err != nil {
+		start, span := time.Now(), tel.startSpan(SpanRemoteFetch,
+			Attribute{Key: "config.remote.type", Value: r.provider.Type},
+			Attribute{Key: "config.remote.endpoint", Value: r.provider.endpoint()},
+			Attribute{Key: "config.remote.path", Value: r.provider.Path})
+		data, err := fetchRemote(ctx, r.provider)
+		tel.recordDuration(MetricRemoteFetchDuration, start, err,
+			Attribute{Key: "config.remote.type", Value: r.provider.Type})
+		endSpan(span, err)
+		stale := false
+		if err != nil {
 			r.logger.Error("Failed to read remote config",
-				zap.String("endpoint", r.provider.Endpoint),
+				zap.String("type", r.provider.Type),
+				zap.String("endpoint", r.provider.endpoint()),
 				zap.Error(err))
-			errCh <- err
+			if !fallback {
+				fail(err)
+				return
+			}
+			data, err = r.fallbackDocument(fileData, err)
+			if err != nil {
+				fail(err)
+				return
+			}
+			stale = true
+		}
+
+		// Skip decoding content that is already live.
+		hash := hashContent(append([][]byte{fileData, data}, overrideContent(overrides)...)...)
+		if r.tracker.skipUnchanged && hash == applied {
+			resultCh <- remoteResult{hash: hash}
 			return
 		}
 
-		// Unmarshal into the schema if provided.
-		if r.schema != nil {
-			if err := r.viper.Unmarshal(r.schema); err != nil {
-				r.logger.Error("Failed to unmarshal remote config", zap.Error(err))
-				errCh <- err
+		var annotations Annotations
+		templates := make(map[string]interpolation)
+		if data != nil {
+			dec, err := lookupDecoder(r.provider.format())
+			if err != nil {
+				fail(err)
+				return
+			}
+			rendered, err := renderTemplate(r.provider.Path, data, r.funcs)
+			if err != nil {
+				fail(err)
+				return
+			}
+			var settings map[string]interface{}
+			if r.provider.format() == "cue" {
+				settings, err = decodeCUE(r.provider.Path, rendered, r.delimiter)
+			} else {
+				settings, err = dec.Decode(rendered)
+			}
+			if err != nil {
+				r.logger.Error("Failed to decode remote config",
+					zap.String("endpoint", r.provider.endpoint()),
+					zap.Error(err))
+				fail(err)
+				return
+			}
+
+			// Strip the writer's annotations from the envelope.
+			annotations, err = extractAnnotations(settings)
+			if err != nil {
+				fail(err)
+				return
+			}
+			if r.interpolate {
+				if err := interpolateEnv(settings, r.delimiter, templates); err != nil {
+					fail(err)
+					return
+				}
+			}
+			sources.set(Remote, settings)
+		}
+		if fileData != nil || len(r.overrides) > 0 {
+			fileSettings := make(map[string]interface{})
+			if fileData != nil {
+				fileSettings, err = decodeConfigFile(r.logger, r.path, fileData, r.configType, r.delimiter, r.jsonnet, r.funcs, r.profile, r.targets, trace)
+				if err != nil {
+					fail(fmt.Errorf("error reading config file: %w", err))
+					return
+				}
+			}
+			fileSettings, err = mergeOverrideFiles(r.logger, fileSettings, overrides, r.configType, r.delimiter, r.jsonnet, r.funcs, r.profile, r.targets, r.merge, trace)
+			if err != nil {
+				fail(fmt.Errorf("error reading config file: %w", err))
 				return
 			}
-			// Validate the configuration.
-			if r.validate != nil {
-				if err := r.validate.Struct(r.schema); err != nil {
-					r.logger.Error("Remote config validation failed", zap.Error(err))
-					errCh <- err
+			if r.interpolate {
+				if err := interpolateEnv(fileSettings, r.delimiter, templates); err != nil {
+					fail(err)
 					return
 				}
 			}
+			sources.set(File, fileSettings)
+		}
+		sources.setFlags(r.flags, r.delimiter)
+		origins, err := sources.apply(v, r.precedence, r.envPrefix, r.envNames, r.delimiter, r.merge)
+		if err != nil {
+			fail(err)
+			return
+		}
+		bindFlags(v, r.flags, r.precedence, r.envPrefix)
+
+		// Refuse documents generated for a newer schema than we understand.
+		if err := checkSchemaVersion(v, r.version); err != nil {
+			r.logger.Error("Remote config schema version mismatch",
+				zap.String("endpoint", r.provider.endpoint()),
+				zap.Error(err))
+			fail(err)
+			return
 		}
 
 		r.logger.Debug("Successfully loaded remote configuration",
-			zap.String("endpoint", r.provider.Endpoint))
-		errCh <- nil
-	}()
+			zap.String("endpoint", r.provider.endpoint()))
+		res := remoteResult{annotations: annotations, origins: origins, files: trace.locate(origins, r.delimiter), templates: templates, hash: hash}
+		if stale {
+			res.stale = true
+		} else {
+			res.revision, res.data = documentRevision(data), data
+		}
+		resultCh <- res
+	})
+	if !started {
+		return ErrClosed
+	}
 
 	select {
-	case err := <-errCh:
-		return err
+	case res := <-resultCh:
+		if res.err != nil {
+			return res.err
+		}
+		if err := r.tracker.observe(res.hash); err != nil {
+			return err
+		}
+		r.annotations = res.annotations
+		r.origins = res.origins
+		r.files = res.files
+		r.templates = res.templates
+		r.pendingRevision = res.revision
+		r.pendingStale = res.stale
+		r.pendingData = res.data
+		return nil
 	case <-ctx.Done():
 		r.logger.Error("Remote config operation timed out",
-			zap.String("endpoint", r.provider.Endpoint))
+			zap.String("endpoint", r.provider.endpoint()))
 		return ErrTimeout
 	}
 }
 
-// LocalConfigWatcher implements ConfigWatcher using Viper's file-watching.
+// readFallbackFile returns the content of the local config file, or nil if
+// no path is set or the file does not exist.
+func (r *RemoteConfigProvider) readFallbackFile() ([]byte, error) {
+	if r.path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(r.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// commit marks the last fetched document as applied.
+func (r *RemoteConfigProvider) commit() {
+	r.tracker.commit()
+	if r.pendingRevision != "" {
+		r.revision = r.pendingRevision
+	}
+	if r.pendingRevision != "" || r.pendingStale {
+		r.stale = r.pendingStale
+	}
+	if r.pendingData != nil {
+		r.writeCache(r.pendingData)
+		r.pendingData = nil
+	}
+}
+
+// sourceHash returns the hash of the last fetched content.
+func (r *RemoteConfigProvider) sourceHash() string {
+	return r.tracker.pending
+}
+
+// invalidate forces the next load to decode the remote document.
+func (r *RemoteConfigProvider) invalidate() {
+	r.tracker.invalidate()
+}
+
+// lastOrigins returns the source of each key in the last loaded configuration.
+func (r *RemoteConfigProvider) lastOrigins() map[string]Source {
+	return r.origins
+}
+
+// lastFiles returns the file each key taken from File was read from.
+func (r *RemoteConfigProvider) lastFiles() map[string]string {
+	return r.files
+}
+
+// lastTemplates returns the values env interpolation changed in the last
+// load.
+func (r *RemoteConfigProvider) lastTemplates() map[string]interpolation {
+	return r.templates
+}
+
+// lastAnnotations returns the annotations of the last successfully loaded document.
+func (r *RemoteConfigProvider) lastAnnotations() Annotations {
+	return r.annotations
+}
+
+// checkSchemaVersion verifies that the schema version embedded in the document
+// does not exceed the supported version. A zero supported version disables the check.
+func checkSchemaVersion(v *viper.Viper, supported int) error {
+	if supported <= 0 || !v.IsSet(SchemaVersionKey) {
+		return nil
+	}
+	version, err := cast.ToIntE(v.Get(SchemaVersionKey))
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", SchemaVersionKey, err)
+	}
+	if version > supported {
+		return &SchemaVersionError{Document: version, Supported: supported}
+	}
+	return nil
+}
+
+// LocalConfigWatcher implements ConfigWatcher using fsnotify. It watches the
+// file's directory, so atomic replacements and symlink swaps (as done by
+// editors and Kubernetes ConfigMaps) are picked up.
 type LocalConfigWatcher struct {
-	viper     *viper.Viper
-	logger    *zap.Logger
+	path   string
+	logger logSink
+	sup    *supervisor
+	// debounce coalesces events arriving within the window into one change.
+	debounce time.Duration
+	// targets lists the files and directories watched besides path.
+	targets *watchRegistry
+	// status records the watcher's health, if non-nil.
+	status    *watchState
 	mu        sync.Mutex
 	watching  bool
 	stopCh    chan struct{}
@@ -484,43 +1332,129 @@ func (w *LocalConfigWatcher) Watch(ctx context.Context, onChange func()) error {
 		return errors.New("watcher is already running")
 	}
 
+	if w.targets == nil {
+		w.targets = newWatchRegistry()
+		w.targets.addFile(w.path)
+	}
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		w.mu.Unlock()
+		return fmt.Errorf("error creating file watcher: %w", err)
+	}
+	configFile := filepath.Clean(w.path)
+	for _, dir := range w.targets.watchDirs() {
+		if err := fw.Add(dir); err != nil {
+			if dir == filepath.Dir(configFile) {
+				fw.Close()
+				w.mu.Unlock()
+				return fmt.Errorf("error watching %s: %w", configFile, err)
+			}
+			w.logger.Warn("Skipping watch path", zap.String("dir", dir), zap.Error(err))
+		}
+	}
+	matcher := newTargetMatcher(w.targets)
+	// Watch the directories of targets registered from now on.
+	w.targets.mu.Lock()
+	w.targets.added = func(path string, isDir bool) {
+		if !isDir {
+			path = filepath.Dir(path)
+		}
+		if err := fw.Add(path); err != nil {
+			w.logger.Warn("Skipping watch path", zap.String("dir", path), zap.Error(err))
+		}
+	}
+	w.targets.mu.Unlock()
+
 	// Initialize stop channel
 	w.stopCh = make(chan struct{})
 	w.watching = true
 	w.mu.Unlock()
 
-	// Start the cleanup goroutine
 	w.cleanupWg.Add(1)
-	go func() {
+	w.status.started("file")
+	started := w.sup.Go("file-watch", func(supCtx context.Context) {
 		defer w.cleanupWg.Done()
 		defer func() {
+			w.status.stopped()
+			w.targets.mu.Lock()
+			w.targets.added = nil
+			w.targets.mu.Unlock()
+			fw.Close()
 			w.mu.Lock()
 			w.watching = false
 			w.mu.Unlock()
 		}()
 
-		// Setup the watcher
-		w.viper.OnConfigChange(func(e fsnotify.Event) {
+		// settled fires once no event has arrived for the debounce window.
+		var timer *time.Timer
+		var settled <-chan time.Time
+		defer func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}()
+
+		for {
 			select {
-			case <-w.stopCh:
-				return
 			case <-ctx.Done():
+				w.logger.Debug("Context cancelled, stopping watcher")
 				return
-			default:
-				w.logger.Info("Local configuration changed", zap.String("file", e.Name))
+			case <-supCtx.Done():
+				w.logger.Debug("Config manager closed, stopping watcher")
+				return
+			case <-w.stopCh:
+				w.logger.Debug("Watcher stopped explicitly")
+				return
+			case event, ok := <-fw.Events:
+				if !ok {
+					return
+				}
+				// React to writes of a target and to symlink targets changing.
+				changed := matcher.match(event)
+				if len(changed) == 0 {
+					continue
+				}
+				w.status.succeeded()
+				if !slices.Contains(changed, configFile) {
+					w.targets.markDirty()
+				}
+				w.logger.Info("Local configuration changed",
+					zap.String("file", event.Name),
+					zap.Strings("targets", changed))
+				if w.debounce <= 0 {
+					onChange()
+					continue
+				}
+				if timer == nil {
+					timer = time.NewTimer(w.debounce)
+				} else {
+					timer.Reset(w.debounce)
+				}
+				settled = timer.C
+			case <-settled:
+				settled = nil
 				onChange()
+			case err, ok := <-fw.Errors:
+				if !ok {
+					return
+				}
+				w.logger.Error("File watcher error", zap.Error(err))
+				w.status.failed(err)
 			}
-		})
-		w.viper.WatchConfig()
-
-		// Wait for cancellation
-		select {
-		case <-ctx.Done():
-			w.logger.Debug("Context cancelled, stopping watcher")
-		case <-w.stopCh:
-			w.logger.Debug("Watcher stopped explicitly")
 		}
-	}()
+	})
+	if !started {
+		w.status.stopped()
+		w.cleanupWg.Done()
+		w.targets.mu.Lock()
+		w.targets.added = nil
+		w.targets.mu.Unlock()
+		fw.Close()
+		w.mu.Lock()
+		w.watching = false
+		w.mu.Unlock()
+		return ErrClosed
+	}
 
 	return nil
 }
@@ -552,10 +1486,18 @@ func (w *LocalConfigWatcher) Stop() error {
 
 // RemoteConfigWatcher implements ConfigWatcher by polling the remote source.
 type RemoteConfigWatcher struct {
-	viper        *viper.Viper
-	logger       *zap.Logger
+	logger       logSink
 	pollInterval time.Duration
 	provider     *RemoteProvider
+	sup          *supervisor
+	// onError is called when polling the remote source fails.
+	onError func(error)
+	// files watches the fallback file and other registered targets.
+	files *LocalConfigWatcher
+	// appliedRevision returns the revision of the last applied document.
+	appliedRevision func() string
+	// status records the poller's health, if non-nil.
+	status *watchState
 }
 
 func (w *RemoteConfigWatcher) Watch(ctx context.Context, onChange func()) error {
@@ -563,29 +1505,68 @@ func (w *RemoteConfigWatcher) Watch(ctx context.Context, onChange func()) error
 		return errors.New("context cannot be nil")
 	}
 
-	go func() {
+	if _, err := lookupRemoteBackend(w.provider.Type); err != nil {
+		return err
+	}
+	if w.files != nil {
+		if err := w.files.Watch(ctx, onChange); err != nil {
+			return err
+		}
+	}
+
+	w.status.started("remote-poll")
+	w.status.scheduled(time.Now().Add(w.pollInterval))
+	started := w.sup.Go("remote-poll", func(supCtx context.Context) {
 		ticker := time.NewTicker(w.pollInterval)
 		defer ticker.Stop()
+		defer w.status.stopped()
 
 		for {
 			select {
 			case <-ctx.Done():
 				return
+			case <-supCtx.Done():
+				return
 			case <-ticker.C:
-				if err := w.viper.WatchRemoteConfig(); err != nil {
+				data, err := fetchRemote(supCtx, w.provider)
+				w.status.scheduled(time.Now().Add(w.pollInterval))
+				if err != nil {
+					w.status.failed(err)
 					w.logger.Error("Error watching remote config",
 						zap.Error(err),
 						zap.Duration("backoff", w.pollInterval))
+					if w.onError != nil {
+						w.onError(err)
+					}
+					continue
+				}
+				w.status.succeeded()
+				// Only propagate documents that differ from the applied one.
+				if w.appliedRevision != nil && documentRevision(data) == w.appliedRevision() {
+					w.logger.Debug("Remote configuration unchanged")
 					continue
 				}
-				w.logger.Debug("Remote configuration check completed")
+				w.logger.Debug("Remote configuration changed")
 				onChange()
 			}
 		}
-	}()
+	})
+	if !started {
+		w.status.stopped()
+		return ErrClosed
+	}
 	return nil
 }
 
+// Stop stops watching the fallback file and other registered targets. The
+// poller itself stops when the manager is closed or the Watch context is done.
+func (w *RemoteConfigWatcher) Stop() error {
+	if w.files == nil {
+		return nil
+	}
+	return w.files.Stop()
+}
+
 // Option functions for configuring the ConfigManager.
 
 func WithWatcher() Option {
@@ -623,3 +1604,47 @@ func WithPollInterval(interval time.Duration) Option {
 		cm.pollInterval = interval
 	}
 }
+
+// WithDebounce coalesces file events arriving within d of each other, such
+// as the several writes of one editor save, into a single reload and
+// notification, fired once the file has been quiet for d.
+func WithDebounce(d time.Duration) Option {
+	return func(cm *ConfigManager) {
+		cm.debounce = d
+	}
+}
+
+// WithOnReloadError registers fn to be called when a reload triggered by a
+// watcher fails, including failed polls of a remote source, so applications
+// can alert or fall back instead of silently running on stale config. The
+// previous configuration stays live.
+func WithOnReloadError(fn func(error)) Option {
+	return func(cm *ConfigManager) {
+		cm.onReloadError = fn
+	}
+}
+
+// WithConfigType forces the format of the config file (e.g. "yaml"),
+// overriding detection from the file extension or content.
+func WithConfigType(t string) Option {
+	return func(cm *ConfigManager) {
+		cm.configType = t
+	}
+}
+
+// WithJsonnet sets the import paths and external variables used when
+// evaluating .jsonnet config files.
+func WithJsonnet(opts *JsonnetOptions) Option {
+	return func(cm *ConfigManager) {
+		cm.jsonnet = opts
+	}
+}
+
+// WithSchemaVersion sets the highest schema version this binary understands.
+// Remote documents declaring a newer SchemaVersionKey are rejected, and
+// older documents are brought up to it by RegisterMigration functions.
+func WithSchemaVersion(version int) Option {
+	return func(cm *ConfigManager) {
+		cm.schemaVersion = version
+	}
+}
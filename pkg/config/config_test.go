@@ -2,6 +2,7 @@ package config
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -9,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
@@ -466,3 +468,207 @@ func TestConcurrentModification(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestSchemaVersionCheck(t *testing.T) {
+	newViper := func(version interface{}) *viper.Viper {
+		v := viper.New()
+		if version != nil {
+			v.Set(SchemaVersionKey, version)
+		}
+		return v
+	}
+
+	t.Run("Compatible Version", func(t *testing.T) {
+		assert.NoError(t, checkSchemaVersion(newViper(2), 2))
+		assert.NoError(t, checkSchemaVersion(newViper("1"), 2))
+	})
+
+	t.Run("Missing Version", func(t *testing.T) {
+		assert.NoError(t, checkSchemaVersion(newViper(nil), 2))
+	})
+
+	t.Run("Check Disabled", func(t *testing.T) {
+		assert.NoError(t, checkSchemaVersion(newViper(5), 0))
+	})
+
+	t.Run("Newer Version", func(t *testing.T) {
+		err := checkSchemaVersion(newViper(3), 2)
+		var versionErr *SchemaVersionError
+		require.ErrorAs(t, err, &versionErr)
+		assert.Equal(t, 3, versionErr.Document)
+		assert.Equal(t, 2, versionErr.Supported)
+	})
+
+	t.Run("Invalid Version", func(t *testing.T) {
+		assert.Error(t, checkSchemaVersion(newViper("abc"), 2))
+	})
+}
+
+// TestConfigMapSymlinkSwap reproduces how the kubelet updates a mounted
+// ConfigMap: the file is a symlink through ..data, which is atomically
+// repointed at a new timestamped directory.
+func TestConfigMapSymlinkSwap(t *testing.T) {
+	dir := t.TempDir()
+	writeVersion := func(name string, port int) {
+		require.NoError(t, os.Mkdir(filepath.Join(dir, name), 0755))
+		content := fmt.Sprintf("server:\n  port: %d\n", port)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name, "config.yaml"), []byte(content), 0644))
+	}
+	writeVersion("..2024_01_01", 8080)
+	require.NoError(t, os.Symlink("..2024_01_01", filepath.Join(dir, "..data")))
+	configPath := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.Symlink(filepath.Join("..data", "config.yaml"), configPath))
+
+	cfg := New(configPath, zap.NewNop())
+	defer cfg.Close()
+	require.NoError(t, cfg.Load())
+
+	changes := make(chan struct{}, 10)
+	require.NoError(t, cfg.Watch(context.Background(), func() { changes <- struct{}{} }))
+
+	writeVersion("..2024_01_02", 9090)
+	require.NoError(t, os.Symlink("..2024_01_02", filepath.Join(dir, "..data_tmp")))
+	require.NoError(t, os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")))
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "..2024_01_01")))
+
+	select {
+	case <-changes:
+		assert.Equal(t, 9090, cfg.GetInt("server.port"))
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for ConfigMap update")
+	}
+}
+
+func TestUnmarshalKey(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	cfg := New(configPath, zap.NewNop())
+	require.NoError(t, cfg.Load())
+
+	var db struct {
+		Host     string `mapstructure:"host"`
+		Port     int    `mapstructure:"port"`
+		MaxConns int    `mapstructure:"maxConns"`
+	}
+	require.NoError(t, cfg.UnmarshalKey("database", &db))
+	assert.Equal(t, "127.0.0.1", db.Host)
+	assert.Equal(t, 5432, db.Port)
+	assert.Equal(t, 10, db.MaxConns)
+
+	var server struct {
+		Timeout time.Duration `mapstructure:"timeout"`
+	}
+	require.NoError(t, cfg.UnmarshalKey("server", &server))
+	assert.Equal(t, 30*time.Second, server.Timeout)
+
+	var bad struct {
+		Port []string `mapstructure:"port"`
+	}
+	assert.Error(t, cfg.UnmarshalKey("server.host", &bad))
+}
+
+func TestAdditionalGetters(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := `
+limits:
+  requests: 5000000000
+  workers: 8
+  ports: [80, 443]
+  body: 10mb
+  labels:
+    team: core
+  routes:
+    api: [a, b]
+`
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+	cfg := New(configPath, zap.NewNop())
+	require.NoError(t, cfg.Load())
+
+	for name, c := range map[string]Config{
+		"Manager": cfg,
+		"Scoped":  cfg.ScopedView("limits"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, int64(5000000000), c.GetInt64("limits.requests"))
+			assert.Equal(t, int32(8), c.GetInt32("limits.workers"))
+			assert.Equal(t, uint(8), c.GetUint("limits.workers"))
+			assert.Equal(t, uint16(8), c.GetUint16("limits.workers"))
+			assert.Equal(t, uint32(8), c.GetUint32("limits.workers"))
+			assert.Equal(t, uint64(5000000000), c.GetUint64("limits.requests"))
+			assert.Equal(t, []int{80, 443}, c.GetIntSlice("limits.ports"))
+			assert.Equal(t, map[string]string{"team": "core"}, c.GetStringMapString("limits.labels"))
+			assert.Equal(t, map[string][]string{"api": {"a", "b"}}, c.GetStringMapStringSlice("limits.routes"))
+			assert.Equal(t, uint(10<<20), c.GetSizeInBytes("limits.body"))
+		})
+	}
+
+	t.Run("Sub", func(t *testing.T) {
+		var c Config = cfg.Sub("limits")
+		assert.Equal(t, int64(5000000000), c.GetInt64("requests"))
+		assert.Equal(t, []int{80, 443}, c.GetIntSlice("ports"))
+		assert.Equal(t, uint(10<<20), c.GetSizeInBytes("body"))
+	})
+
+	t.Run("Outside Scope", func(t *testing.T) {
+		c := cfg.ScopedView("other")
+		assert.Zero(t, c.GetInt64("limits.requests"))
+		assert.Nil(t, c.GetIntSlice("limits.ports"))
+		assert.Nil(t, c.GetStringMapString("limits.labels"))
+	})
+}
+
+func TestAllSettingsCopy(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := `
+crawler:
+  seeds: [a.example.com, b.example.com]
+  limits:
+    depth: 3
+  rules:
+    - host: a.example.com
+      paths: [/docs]
+`
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+	cfg := New(configPath, zap.NewNop(), WithSecretKeys("crawler.limits.depth"))
+	require.NoError(t, cfg.Load())
+
+	mutate := func(settings map[string]interface{}) {
+		crawler := settings["crawler"].(map[string]interface{})
+		crawler["seeds"].([]interface{})[0] = "evil.example.com"
+		limits := crawler["limits"].(map[string]interface{})
+		limits["depth"] = 99
+		limits["extra"] = true
+		rule := crawler["rules"].([]interface{})[0].(map[string]interface{})
+		rule["host"] = "evil.example.com"
+		rule["paths"].([]interface{})[0] = "/admin"
+	}
+	check := func(t *testing.T) {
+		assert.Equal(t, []string{"a.example.com", "b.example.com"}, cfg.GetStringSlice("crawler.seeds"))
+		assert.Equal(t, 3, cfg.GetInt("crawler.limits.depth"))
+		assert.False(t, cfg.IsSet("crawler.limits.extra"))
+		rule := cfg.Get("crawler.rules").([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "a.example.com", rule["host"])
+		assert.Equal(t, []interface{}{"/docs"}, rule["paths"])
+	}
+
+	t.Run("Nested Maps And Slices", func(t *testing.T) {
+		mutate(cfg.AllSettings())
+		check(t)
+	})
+
+	t.Run("Redacted", func(t *testing.T) {
+		settings := cfg.AllSettingsRedacted()
+		settings["crawler"].(map[string]interface{})["seeds"].([]interface{})[0] = "evil.example.com"
+		check(t)
+	})
+
+	t.Run("Views", func(t *testing.T) {
+		sub := cfg.Sub("crawler").AllSettings()
+		sub["seeds"].([]interface{})[0] = "evil.example.com"
+		sub["rules"].([]interface{})[0].(map[string]interface{})["host"] = "evil.example.com"
+		scoped := cfg.ScopedView("crawler").AllSettings()
+		scoped["crawler"].(map[string]interface{})["seeds"].([]interface{})[1] = "evil.example.com"
+		check(t)
+	})
+}
@@ -0,0 +1,123 @@
+// Package configotel adapts OpenTelemetry tracer and meter providers to the
+// Tracer and Meter of package config, so config loads are traced and metered
+// without the config package depending on OpenTelemetry:
+//
+//	cfg := config.New("config.yaml", logger,
+//		configotel.WithTracerProvider(otel.GetTracerProvider()),
+//		configotel.WithMeterProvider(otel.GetMeterProvider()),
+//	)
+package configotel
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hugomatus/gobits/pkg/config"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of the tracer and meter.
+const ScopeName = "github.com/hugomatus/gobits/pkg/config"
+
+// WithTracerProvider traces config loads with a tracer of tp; see
+// config.WithTracer.
+func WithTracerProvider(tp trace.TracerProvider) config.Option {
+	return config.WithTracer(NewTracer(tp))
+}
+
+// WithMeterProvider records config metrics with a meter of mp; see
+// config.WithMeter.
+func WithMeterProvider(mp metric.MeterProvider) config.Option {
+	return config.WithMeter(NewMeter(mp))
+}
+
+// NewTracer returns a config.Tracer starting spans with a tracer of tp.
+func NewTracer(tp trace.TracerProvider) config.Tracer {
+	return tracer{t: tp.Tracer(ScopeName)}
+}
+
+type tracer struct {
+	t trace.Tracer
+}
+
+func (t tracer) Start(ctx context.Context, name string) (context.Context, config.Span) {
+	ctx, s := t.t.Start(ctx, name)
+	return ctx, span{s: s}
+}
+
+type span struct {
+	s trace.Span
+}
+
+func (s span) SetAttributes(attrs ...config.Attribute) {
+	s.s.SetAttributes(keyValues(attrs)...)
+}
+
+func (s span) SetError(err error) {
+	s.s.RecordError(err)
+	s.s.SetStatus(codes.Error, err.Error())
+}
+
+func (s span) End() {
+	s.s.End()
+}
+
+// NewMeter returns a config.Meter recording durations, in seconds, in
+// Float64Histograms and counts in Int64Counters of a meter of mp. Each
+// instrument is created on first use.
+func NewMeter(mp metric.MeterProvider) config.Meter {
+	return &meter{m: mp.Meter(ScopeName)}
+}
+
+type meter struct {
+	m          metric.Meter
+	histograms sync.Map // name -> metric.Float64Histogram
+	counters   sync.Map // name -> metric.Int64Counter
+}
+
+func (m *meter) RecordDuration(ctx context.Context, name string, d time.Duration, attrs ...config.Attribute) {
+	h, ok := m.histograms.Load(name)
+	if !ok {
+		created, err := m.m.Float64Histogram(name, metric.WithUnit("s"))
+		if err != nil {
+			return
+		}
+		h, _ = m.histograms.LoadOrStore(name, created)
+	}
+	h.(metric.Float64Histogram).Record(ctx, d.Seconds(), metric.WithAttributes(keyValues(attrs)...))
+}
+
+func (m *meter) Add(ctx context.Context, name string, n int64, attrs ...config.Attribute) {
+	c, ok := m.counters.Load(name)
+	if !ok {
+		created, err := m.m.Int64Counter(name)
+		if err != nil {
+			return
+		}
+		c, _ = m.counters.LoadOrStore(name, created)
+	}
+	c.(metric.Int64Counter).Add(ctx, n, metric.WithAttributes(keyValues(attrs)...))
+}
+
+// keyValues converts attrs to OpenTelemetry attributes, dropping values of
+// unsupported types.
+func keyValues(attrs []config.Attribute) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		switch v := a.Value.(type) {
+		case string:
+			kvs = append(kvs, attribute.String(a.Key, v))
+		case int64:
+			kvs = append(kvs, attribute.Int64(a.Key, v))
+		case int:
+			kvs = append(kvs, attribute.Int(a.Key, v))
+		case bool:
+			kvs = append(kvs, attribute.Bool(a.Key, v))
+		}
+	}
+	return kvs
+}
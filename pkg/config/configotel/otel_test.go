@@ -0,0 +1,183 @@
+package configotel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hugomatus/gobits/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+)
+
+type testConfig struct {
+	Server struct {
+		Port int `mapstructure:"port" validate:"required,min=1"`
+	} `mapstructure:"server"`
+}
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestProviders(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	path := writeConfig(t, "server:\n  port: 8080\n")
+	cfg := config.New(path, zap.NewNop(),
+		WithTracerProvider(tp),
+		WithMeterProvider(mp),
+		config.WithSchema(&testConfig{}))
+	require.NoError(t, cfg.Load())
+	require.NoError(t, os.WriteFile(path, []byte("server:\n  port: 0\n"), 0644))
+	require.Error(t, cfg.Load())
+
+	t.Run("Spans", func(t *testing.T) {
+		spans := exporter.GetSpans()
+		var loads []tracetest.SpanStub
+		for _, s := range spans {
+			assert.Equal(t, ScopeName, s.InstrumentationScope.Name)
+			if s.Name == config.SpanLoad {
+				loads = append(loads, s)
+			}
+		}
+		require.Len(t, loads, 2)
+
+		applied := attributes(loads[0].Attributes)
+		assert.Equal(t, "file", applied["config.source"].AsString())
+		assert.Equal(t, path, applied["config.path"].AsString())
+		assert.Equal(t, int64(1), applied["config.generation"].AsInt64())
+		assert.Len(t, applied["config.hash"].AsString(), 64)
+		assert.Equal(t, codes.Unset, loads[0].Status.Code)
+
+		assert.Equal(t, codes.Error, loads[1].Status.Code)
+		require.NotEmpty(t, loads[1].Events)
+		assert.Equal(t, "exception", loads[1].Events[0].Name)
+
+		var children []string
+		for _, s := range spans {
+			if s.Parent.SpanID() == loads[0].SpanContext.SpanID() {
+				assert.Equal(t, loads[0].SpanContext.TraceID(), s.SpanContext.TraceID())
+				children = append(children, s.Name)
+			}
+		}
+		assert.Contains(t, children, config.SpanUnmarshal)
+		assert.Contains(t, children, config.SpanValidate)
+	})
+
+	t.Run("Metrics", func(t *testing.T) {
+		var rm metricdata.ResourceMetrics
+		require.NoError(t, reader.Collect(context.Background(), &rm))
+		require.Len(t, rm.ScopeMetrics, 1)
+		assert.Equal(t, ScopeName, rm.ScopeMetrics[0].Scope.Name)
+		metrics := make(map[string]metricdata.Metrics)
+		for _, m := range rm.ScopeMetrics[0].Metrics {
+			metrics[m.Name] = m
+		}
+
+		loads, ok := metrics[config.MetricLoads].Data.(metricdata.Sum[int64])
+		require.True(t, ok)
+		results := make(map[string]int64)
+		for _, dp := range loads.DataPoints {
+			result, _ := dp.Attributes.Value("config.result")
+			results[result.AsString()] += dp.Value
+		}
+		assert.Equal(t, map[string]int64{"ok": 1, "error": 1}, results)
+
+		durations, ok := metrics[config.MetricLoadDuration].Data.(metricdata.Histogram[float64])
+		require.True(t, ok)
+		assert.Equal(t, "s", metrics[config.MetricLoadDuration].Unit)
+		var count uint64
+		for _, dp := range durations.DataPoints {
+			source, _ := dp.Attributes.Value("config.source")
+			assert.Equal(t, "file", source.AsString())
+			count += dp.Count
+		}
+		assert.Equal(t, uint64(2), count)
+	})
+}
+
+func recordPort(config.ChangeEvent) {}
+
+func TestSubscriberHistograms(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	path := writeConfig(t, "server:\n  port: 8080\n")
+	cfg := config.New(path, zap.NewNop(), WithMeterProvider(mp))
+	defer cfg.Close()
+	require.NoError(t, cfg.Load())
+	cfg.Subscribe(recordPort)
+	cfg.Subscribe(recordPort)
+
+	require.NoError(t, os.WriteFile(path, []byte("server:\n  port: 9090\n"), 0644))
+	handler := cfg.Handler(config.WithAdminAuth(func(*http.Request) error { return nil }))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, config.ConfigzPath+"/reload", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	histograms := make(map[string]metricdata.Histogram[float64])
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		if h, ok := m.Data.(metricdata.Histogram[float64]); ok {
+			histograms[m.Name] = h
+		}
+	}
+
+	// Both subscribers share one series, named after the callback.
+	subscribers := histograms[config.MetricSubscriberDuration].DataPoints
+	require.Len(t, subscribers, 1)
+	assert.Equal(t, uint64(2), subscribers[0].Count)
+	callback, ok := subscribers[0].Attributes.Value("config.callback")
+	require.True(t, ok)
+	assert.Contains(t, callback.AsString(), "configotel.recordPort")
+	_, ok = subscribers[0].Attributes.Value("config.subscriber")
+	assert.False(t, ok)
+
+	latency := histograms[config.MetricReloadLatency].DataPoints
+	require.Len(t, latency, 1)
+	assert.Equal(t, uint64(1), latency[0].Count)
+	result, _ := latency[0].Attributes.Value("config.result")
+	assert.Equal(t, "ok", result.AsString())
+}
+
+func TestKeyValues(t *testing.T) {
+	kvs := keyValues([]config.Attribute{
+		{Key: "s", Value: "v"},
+		{Key: "i64", Value: int64(2)},
+		{Key: "i", Value: 3},
+		{Key: "b", Value: true},
+		{Key: "err", Value: errors.New("unsupported")},
+	})
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("s", "v"),
+		attribute.Int64("i64", 2),
+		attribute.Int("i", 3),
+		attribute.Bool("b", true),
+	}, kvs)
+}
+
+func attributes(kvs []attribute.KeyValue) map[string]attribute.Value {
+	m := make(map[string]attribute.Value, len(kvs))
+	for _, kv := range kvs {
+		m[string(kv.Key)] = kv.Value
+	}
+	return m
+}
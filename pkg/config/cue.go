@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	cueerrors "cuelang.org/go/cue/errors"
+)
+
+// decodeCUE evaluates a CUE document and returns the resulting config tree.
+// Constraint violations and non-concrete values are reported the same way as
+// failed validator tags, keyed by their path joined with delimiter; filename
+// names the document in positions of CUE errors.
+func decodeCUE(filename string, data []byte, delimiter string) (map[string]interface{}, error) {
+	val := cuecontext.New().CompileBytes(data, cue.Filename(filename))
+	if err := val.Validate(cue.Concrete(true)); err != nil {
+		return nil, cueValidationError(err, delimiter)
+	}
+
+	var settings map[string]interface{}
+	if err := val.Decode(&settings); err != nil {
+		return nil, fmt.Errorf("error decoding CUE value: %w", err)
+	}
+	return settings, nil
+}
+
+// cueValidationError maps CUE evaluation errors onto the field validation
+// error format, joining all of them. Errors without a path (e.g. syntax
+// errors) are returned as evaluation errors, with their file position.
+func cueValidationError(err error, delimiter string) error {
+	var errs ValidationErrors
+	for _, e := range cueerrors.Errors(err) {
+		path := e.Path()
+		if len(path) == 0 {
+			if pos := e.Position(); pos.IsValid() {
+				return fmt.Errorf("error evaluating CUE at %s: %w", pos, err)
+			}
+			return fmt.Errorf("error evaluating CUE: %w", err)
+		}
+		format, args := e.Msg()
+		errs = append(errs, &ValidationError{
+			Key:     strings.Join(path, delimiter),
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+	if len(errs) == 0 {
+		return fmt.Errorf("error evaluating CUE: %w", err)
+	}
+	return errs
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCUEConfigFile(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+
+	writeCUE := func(t *testing.T, content string) string {
+		configPath := filepath.Join(t.TempDir(), "config.cue")
+		require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+		return configPath
+	}
+
+	t.Run("Evaluated Config Tree", func(t *testing.T) {
+		configPath := writeCUE(t, `
+#Server: {
+	port: int & >0 & <=65535
+	host: string | *"localhost"
+}
+server: #Server & {port: 8080}
+database: maxConns: 2 * 5
+`)
+		cfg := New(configPath, logger)
+		require.NoError(t, cfg.Load())
+
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+		assert.Equal(t, "localhost", cfg.GetString("server.host"))
+		assert.Equal(t, 10, cfg.GetInt("database.maxConns"))
+	})
+
+	t.Run("Constraint Violation", func(t *testing.T) {
+		configPath := writeCUE(t, `
+server: {
+	port: int & <=65535
+	port: 70000
+}
+`)
+		cfg := New(configPath, logger)
+		err := cfg.Load()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed for field 'server.port'")
+	})
+
+	t.Run("Incomplete Value", func(t *testing.T) {
+		configPath := writeCUE(t, `server: port: int`)
+		cfg := New(configPath, logger)
+		err := cfg.Load()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed for field 'server.port'")
+	})
+
+	t.Run("Syntax Error", func(t *testing.T) {
+		configPath := writeCUE(t, `server: {`)
+		cfg := New(configPath, logger)
+		err := cfg.Load()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "error evaluating CUE at "+configPath+":1:")
+	})
+
+	t.Run("Custom Key Delimiter", func(t *testing.T) {
+		configPath := writeCUE(t, `server: port: int & <=65535
+server: port: 70000
+`)
+		cfg := New(configPath, logger, WithKeyDelimiter("::"))
+		err := cfg.Load()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed for field 'server::port'")
+	})
+}
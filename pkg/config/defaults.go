@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// tagDefaults collects the default:"..." tags of struct type t into out as
+// dotted keys, named the way viper decodes them. Nested structs are walked
+// and squashed embedded structs share their parent's prefix. Durations are
+// parsed with time.ParseDuration and slice defaults are comma-separated.
+func tagDefaults(t reflect.Type, prefix, delim string, out map[string]interface{}) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("mapstructure"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && ft != reflect.TypeOf(time.Time{}) {
+			childPrefix := prefix + strings.ToLower(name) + delim
+			if f.Anonymous && strings.Contains(opts, "squash") {
+				childPrefix = prefix
+			}
+			if err := tagDefaults(ft, childPrefix, delim, out); err != nil {
+				return err
+			}
+			continue
+		}
+
+		def, ok := f.Tag.Lookup("default")
+		if !ok || def == "" {
+			continue
+		}
+		key := prefix + strings.ToLower(name)
+		value, err := scaffoldValue(ft, def)
+		if err != nil {
+			return fmt.Errorf("field '%s': invalid default %q: %w", key, def, err)
+		}
+		out[key] = value
+	}
+	return nil
+}
+
+// addTagDefaults adds the default tags of schema to the manager's defaults.
+// Keys already given a default through WithDefaults keep it. The caller must
+// hold cm.mu or own cm exclusively.
+func (cm *ConfigManager) addTagDefaults(schema interface{}, prefix string) {
+	tagged := make(map[string]interface{})
+	if err := tagDefaults(reflect.TypeOf(schema), prefix, cm.keyDelimiter, tagged); err != nil {
+		cm.logger.Error("Ignoring schema default tags", zap.Error(err))
+		return
+	}
+
+	explicit := layers{}
+	explicit.setDefaults(cm.defaults, cm.keyDelimiter)
+	for key, value := range tagged {
+		if _, ok := lookupPath(explicit[Defaults], cm.splitKey(key)); ok {
+			continue
+		}
+		cm.defaults[key] = value
+	}
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type taggedDefaultsConfig struct {
+	Server struct {
+		Port    int           `mapstructure:"port" default:"8080"`
+		Host    string        `mapstructure:"host" default:"0.0.0.0"`
+		Timeout time.Duration `mapstructure:"timeout" default:"30s"`
+		TLS     struct {
+			Enabled bool `mapstructure:"enabled" default:"true"`
+		} `mapstructure:"tls"`
+	} `mapstructure:"server"`
+	Peers    []string `mapstructure:"peers" default:"a:1, b:2"`
+	Untagged string   `mapstructure:"untagged"`
+}
+
+func TestTagDefaults(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("server:\n  host: example.com\n"), 0644))
+
+	t.Run("Fill Unset Keys", func(t *testing.T) {
+		schema := &taggedDefaultsConfig{}
+		cfg := New(configPath, zap.NewNop(), WithSchema(schema))
+		require.NoError(t, cfg.Load())
+
+		assert.Equal(t, 8080, schema.Server.Port)
+		assert.Equal(t, "example.com", schema.Server.Host)
+		assert.Equal(t, 30*time.Second, schema.Server.Timeout)
+		assert.True(t, schema.Server.TLS.Enabled)
+		assert.Equal(t, []string{"a:1", "b:2"}, schema.Peers)
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+		assert.False(t, cfg.IsSet("untagged"))
+	})
+
+	t.Run("WithDefaults Wins", func(t *testing.T) {
+		explicit := map[string]interface{}{
+			"server.port":    9090,
+			"server.timeout": "5s",
+		}
+		schema := &taggedDefaultsConfig{}
+		cfg := New(configPath, zap.NewNop(), WithSchema(schema), WithDefaults(explicit))
+		require.NoError(t, cfg.Load())
+
+		assert.Equal(t, 9090, schema.Server.Port)
+		assert.Equal(t, 5*time.Second, schema.Server.Timeout)
+		assert.Len(t, explicit, 2, "caller's map is not modified")
+	})
+
+	t.Run("Sections", func(t *testing.T) {
+		type cacheSection struct {
+			TTL time.Duration `mapstructure:"ttl" default:"1m"`
+		}
+		section := &cacheSection{}
+		cfg := New(configPath, zap.NewNop())
+		cfg.RegisterSection("cache", section)
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, time.Minute, section.TTL)
+	})
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// Deprecation describes a renamed key, registered with WithDeprecatedKey.
+type Deprecation struct {
+	Old     string
+	New     string
+	Message string
+}
+
+// WithDeprecatedKey renames old to new: a value set under old in any source
+// is moved to new on every load, so configurations written for an earlier
+// release keep working while readers and schemas use only the new name. If
+// new is also set in the document, it wins. Each load that uses old logs a
+// warning with msg, and Deprecations reports the renames the live
+// configuration still relies on.
+func WithDeprecatedKey(old, new string, msg string) Option {
+	return func(cm *ConfigManager) {
+		cm.deprecations = append(cm.deprecations, Deprecation{
+			Old:     strings.ToLower(old),
+			New:     strings.ToLower(new),
+			Message: msg,
+		})
+	}
+}
+
+// Deprecations returns the deprecated keys used by the live configuration.
+func (cm *ConfigManager) Deprecations() []Deprecation {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return append([]Deprecation(nil), cm.deprecatedUsed...)
+}
+
+// applyDeprecations moves the values of deprecated keys in v to their new
+// names and returns the renames it applied. The caller must hold cm.mu.
+func (cm *ConfigManager) applyDeprecations(v *viper.Viper) (*viper.Viper, []Deprecation, error) {
+	var used []Deprecation
+	for _, d := range cm.deprecations {
+		if v.IsSet(d.Old) {
+			used = append(used, d)
+		}
+	}
+	if len(used) == 0 {
+		return v, nil, nil
+	}
+
+	settings := v.AllSettings()
+	for _, d := range used {
+		oldPath, newPath := cm.splitKey(d.Old), cm.splitKey(d.New)
+		value, ok := lookupPath(settings, oldPath)
+		if !ok {
+			continue
+		}
+		deletePath(settings, oldPath)
+		if v.InConfig(d.New) {
+			cm.logger.Warn("Deprecated config key ignored, its replacement is set",
+				zap.String("key", d.Old),
+				zap.String("replacement", d.New),
+				zap.String("message", d.Message))
+			continue
+		}
+		setPath(settings, newPath, value)
+		cm.logger.Warn("Deprecated config key used",
+			zap.String("key", d.Old),
+			zap.String("replacement", d.New),
+			zap.String("message", d.Message))
+	}
+
+	rebuilt := cm.newViper()
+	if err := rebuilt.MergeConfigMap(settings); err != nil {
+		return nil, nil, err
+	}
+	return rebuilt, used, nil
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestDeprecatedKey(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(content string) {
+		require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+	}
+
+	type dbConfig struct {
+		Database struct {
+			Password string `mapstructure:"password" validate:"required"`
+		} `mapstructure:"database"`
+	}
+
+	newManager := func() (*ConfigManager, *observer.ObservedLogs) {
+		core, logs := observer.New(zap.WarnLevel)
+		cfg := New(configPath, zap.New(core),
+			WithSchema(&dbConfig{}),
+			WithDeprecatedKey("db.pass", "database.password", "renamed in v2"),
+		)
+		return cfg, logs
+	}
+
+	t.Run("Old Key Is Moved", func(t *testing.T) {
+		write("db:\n  pass: secret\n")
+		cfg, logs := newManager()
+		require.NoError(t, cfg.Load())
+
+		assert.Equal(t, "secret", cfg.GetString("database.password"))
+		assert.Equal(t, "secret", cfg.GetSchema().(*dbConfig).Database.Password)
+		assert.False(t, cfg.IsSet("db.pass"))
+		assert.Equal(t, []Deprecation{{Old: "db.pass", New: "database.password", Message: "renamed in v2"}}, cfg.Deprecations())
+
+		entries := logs.FilterMessage("Deprecated config key used").All()
+		require.Len(t, entries, 1)
+		assert.Equal(t, "db.pass", entries[0].ContextMap()["key"])
+		assert.Equal(t, "database.password", entries[0].ContextMap()["replacement"])
+	})
+
+	t.Run("New Key Wins", func(t *testing.T) {
+		write("db:\n  pass: old\ndatabase:\n  password: new\n")
+		cfg, logs := newManager()
+		require.NoError(t, cfg.Load())
+
+		assert.Equal(t, "new", cfg.GetString("database.password"))
+		assert.Equal(t, 1, logs.FilterMessage("Deprecated config key ignored, its replacement is set").Len())
+	})
+
+	t.Run("Migrated Config", func(t *testing.T) {
+		write("database:\n  password: new\n")
+		cfg, logs := newManager()
+		require.NoError(t, cfg.Load())
+
+		assert.Empty(t, cfg.Deprecations())
+		assert.Zero(t, logs.Len())
+	})
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// KeyInfo describes one leaf key of the effective configuration.
+type KeyInfo struct {
+	// Key is the flattened key, e.g. "database.port".
+	Key string
+	// Type is the Go type of the current value, e.g. "int" or "[]interface {}".
+	Type string
+	// Source is the layer the current value was taken from.
+	Source Source
+	// Origin names the file, environment variable, or remote document the
+	// value was taken from, as returned by OriginName.
+	Origin string
+	// Secret reports whether the key is masked in redacted output.
+	Secret bool
+}
+
+// Describe lists every key of the effective configuration, sorted, with the
+// type and source of its value, for debug endpoints and tooling. It never
+// includes values, so it is safe to expose wherever keys may be seen.
+func (cm *ConfigManager) Describe() []KeyInfo {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	keys := cm.viper.AllKeys()
+	sort.Strings(keys)
+	secrets := cm.secretPatterns()
+	infos := make([]KeyInfo, len(keys))
+	for i, key := range keys {
+		infos[i] = KeyInfo{
+			Key:    key,
+			Type:   fmt.Sprintf("%T", cm.viper.Get(key)),
+			Source: cm.origin(key),
+			Origin: cm.originName(key),
+			Secret: isSecret(key, secrets, cm.keyDelimiter),
+		}
+	}
+	return infos
+}
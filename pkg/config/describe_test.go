@@ -0,0 +1,37 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDescribe(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	t.Setenv("APP_DATABASE_MAXCONNS", "20")
+	cfg := New(configPath, zap.NewNop(),
+		WithEnvPrefix("APP"),
+		WithDefaults(map[string]interface{}{"cache.ttl": "1m"}),
+		WithSecretKeys("database.name"))
+	require.NoError(t, cfg.Load())
+	require.NoError(t, cfg.Set("server.host", "0.0.0.0"))
+
+	infos := cfg.Describe()
+	byKey := make(map[string]KeyInfo, len(infos))
+	keys := make([]string, len(infos))
+	for i, info := range infos {
+		byKey[info.Key] = info
+		keys[i] = info.Key
+	}
+
+	assert.IsIncreasing(t, keys)
+	assert.Equal(t, KeyInfo{Key: "cache.ttl", Type: "string", Source: Defaults}, byKey["cache.ttl"])
+	assert.Equal(t, KeyInfo{Key: "server.port", Type: "int", Source: File, Origin: configPath}, byKey["server.port"])
+	assert.Equal(t, KeyInfo{Key: "database.maxconns", Type: "string", Source: Env, Origin: "APP_DATABASE_MAXCONNS"}, byKey["database.maxconns"])
+	assert.Equal(t, KeyInfo{Key: "server.host", Type: "string", Source: Override}, byKey["server.host"])
+	assert.Equal(t, KeyInfo{Key: "database.name", Type: "string", Source: File, Origin: configPath, Secret: true}, byKey["database.name"])
+}
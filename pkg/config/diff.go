@@ -0,0 +1,337 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Change describes a key whose effective value changed between loads.
+type Change struct {
+	Key string
+	// Old is nil when the key was added, New is nil when it was removed.
+	Old interface{}
+	New interface{}
+	// Source is the layer the new value came from, or for a removed key,
+	// the layer that held the old one.
+	Source Source
+	// Secret reports whether the key is masked in redacted output. Old and
+	// New hold the real values; Change masks them when logged with zap.
+	Secret bool
+}
+
+// MarshalLogObject logs the change, with secret values masked.
+func (c Change) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("key", c.Key)
+	enc.AddString("source", c.Source.String())
+	if c.Secret {
+		if c.Old != nil {
+			enc.AddString("old", RedactedValue)
+		}
+		if c.New != nil {
+			enc.AddString("new", RedactedValue)
+		}
+		return nil
+	}
+	if c.Old != nil {
+		if err := enc.AddReflected("old", c.Old); err != nil {
+			return err
+		}
+	}
+	if c.New != nil {
+		return enc.AddReflected("new", c.New)
+	}
+	return nil
+}
+
+// originSource is implemented by providers that record which layer each
+// key was taken from, and which file for keys taken from File.
+type originSource interface {
+	lastOrigins() map[string]Source
+	lastFiles() map[string]string
+}
+
+// Diff returns the changes applied by the most recent load, sorted by key.
+// It is empty if that load failed or found nothing new.
+func (cm *ConfigManager) Diff() []Change {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return append([]Change(nil), cm.changes...)
+}
+
+// ChangeEvent describes one reload triggered by a watcher.
+type ChangeEvent struct {
+	// Source is the config file path, or the remote provider as
+	// type://endpoint/path, that triggered the reload.
+	Source string
+	Time   time.Time
+	// Changes lists the changed keys, sorted, with their old and new values.
+	Changes []Change
+	// Rotated lists the changed keys holding a resolved secret; see
+	// OnSecretRotated.
+	Rotated []string
+	// Annotations attributes the applied version, if its writer set any.
+	Annotations Annotations
+	// Err is the reload error. A rejected reload changes nothing.
+	Err error
+}
+
+// Keys returns the changed key paths.
+func (e ChangeEvent) Keys() []string {
+	keys := make([]string, len(e.Changes))
+	for i, c := range e.Changes {
+		keys[i] = c.Key
+	}
+	return keys
+}
+
+// WatchEvents is like Watch, but passes each reload to onChange as a
+// ChangeEvent. Reloads that succeed without changing anything are skipped;
+// failed reloads are delivered with Err set.
+func (cm *ConfigManager) WatchEvents(ctx context.Context, onChange func(ChangeEvent)) error {
+	return cm.watchReloads(ctx, func(event ChangeEvent) {
+		if event.Err != nil || len(event.Changes) > 0 {
+			onChange(event)
+		}
+	})
+}
+
+// watchFanout shares one run of the manager's watcher between every Watch,
+// WatchEvents, and Subscribe call, since a watcher runs at most once at a
+// time. Each watcher trigger reloads once and passes the outcome to every
+// listener.
+type watchFanout struct {
+	mu        sync.Mutex
+	nextID    uint64
+	listeners map[uint64]func(ChangeEvent)
+	// stop cancels the watcher's context; it is nil while the watcher is
+	// not running.
+	stop context.CancelFunc
+}
+
+// watchReloads calls onReload with the outcome of every watcher-triggered
+// reload until ctx is done. The first listener starts the watcher, and the
+// watcher stops once the last one has left.
+func (cm *ConfigManager) watchReloads(ctx context.Context, onReload func(ChangeEvent)) error {
+	if ctx == nil {
+		return errors.New("context cannot be nil")
+	}
+	if cm.watcher == nil {
+		return nil
+	}
+	cm.mu.RLock()
+	closed := cm.closed
+	cm.mu.RUnlock()
+	if closed {
+		return ErrClosed
+	}
+
+	f := &cm.watchFanout
+	f.mu.Lock()
+	if f.listeners == nil {
+		f.listeners = make(map[uint64]func(ChangeEvent))
+	}
+	f.nextID++
+	id := f.nextID
+	f.listeners[id] = onReload
+	started := f.stop == nil
+	if started {
+		watchCtx, stop := context.WithCancel(context.Background())
+		if err := cm.watcher.Watch(watchCtx, cm.gate(cm.fanOutReload)); err != nil {
+			stop()
+			delete(f.listeners, id)
+			f.mu.Unlock()
+			return err
+		}
+		f.stop = stop
+	}
+	f.mu.Unlock()
+
+	context.AfterFunc(ctx, func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		delete(f.listeners, id)
+		if len(f.listeners) == 0 && f.stop != nil {
+			f.stop()
+			f.stop = nil
+		}
+	})
+	if started {
+		return cm.watchStarted(nil)
+	}
+	return nil
+}
+
+// fanOutReload reloads after a watcher trigger and passes the outcome to
+// every listener, in registration order.
+func (cm *ConfigManager) fanOutReload() {
+	event := cm.loadEvent()
+	if event.Err != nil {
+		cm.logger.Error("Failed to reload configuration", zap.Error(event.Err))
+		cm.reportReloadError(event.Err)
+	}
+
+	f := &cm.watchFanout
+	f.mu.Lock()
+	ids := make([]uint64, 0, len(f.listeners))
+	for id := range f.listeners {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	listeners := make([]func(ChangeEvent), len(ids))
+	for i, id := range ids {
+		listeners[i] = f.listeners[id]
+	}
+	f.mu.Unlock()
+
+	for _, onReload := range listeners {
+		onReload(event)
+	}
+}
+
+// reportReloadError passes a failed watcher-triggered reload to the
+// WithOnReloadError hook, recovering from a panic in it.
+func (cm *ConfigManager) reportReloadError(err error) {
+	if cm.onReloadError == nil {
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			cm.logger.Error("Reload error hook panicked", zap.Any("panic", r), zap.Stack("stack"))
+		}
+	}()
+	cm.onReloadError(err)
+}
+
+// WatchChanges is like Watch, but passes the changes of each reload to
+// onChange and skips reloads that failed or changed nothing.
+func (cm *ConfigManager) WatchChanges(ctx context.Context, onChange func([]Change)) error {
+	return cm.WatchEvents(ctx, func(event ChangeEvent) {
+		if len(event.Changes) > 0 {
+			onChange(event.Changes)
+		}
+	})
+}
+
+// loadEvent loads and describes the outcome.
+func (cm *ConfigManager) loadEvent() ChangeEvent {
+	defer cm.flushLifecycle()
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	event := ChangeEvent{Source: cm.sourceName()}
+	if cm.closed {
+		event.Time, event.Err = time.Now(), ErrClosed
+		return event
+	}
+	event.Err = cm.load()
+	event.Time = time.Now()
+	if event.Err == nil {
+		event.Time = cm.lastLoad
+	}
+	event.Changes = append([]Change(nil), cm.changes...)
+	event.Rotated = append([]string(nil), cm.rotated...)
+	event.Annotations = cm.annotations
+	return event
+}
+
+// sourceName names the configuration source for change events.
+func (cm *ConfigManager) sourceName() string {
+	if rp := cm.remoteProvider; rp != nil {
+		return rp.Type + "://" + rp.endpoint() + "/" + strings.TrimLeft(rp.Path, "/")
+	}
+	return cm.path
+}
+
+// recordChanges computes the changes between the settings applied by the
+// previous load and next, and picks up the provider's key origins. The live
+// viper cannot be diffed directly, since automatic env lookups read the
+// environment at call time. The caller must hold cm.mu.
+func (cm *ConfigManager) recordChanges(next *viper.Viper) {
+	oldOrigins := cm.origins
+	if src, ok := cm.provider.(originSource); ok {
+		cm.origins = src.lastOrigins()
+		cm.files = src.lastFiles()
+	}
+
+	before, oldLeaves := cm.settings, cm.leaves
+	after := next.AllSettings()
+	newLeaves := leafPool.Get().(map[string]interface{})
+	flattenLeaves(after, "", cm.keyDelimiter, newLeaves)
+	cm.settings, cm.leaves = after, newLeaves
+
+	// The previous version was flattened when it was applied, so each
+	// version is walked once; a key that is a leaf on one side only is
+	// looked up in the nested settings of the other.
+	secrets := cm.secretPatterns()
+	var changes []Change
+	record := func(key string, oldValue, newValue interface{}, hadOld, hasNew bool) {
+		if hadOld && hasNew && reflect.DeepEqual(oldValue, newValue) {
+			return
+		}
+		change := Change{Key: key, Old: oldValue, New: newValue, Secret: isSecret(key, secrets, cm.keyDelimiter)}
+		if hasNew {
+			change.Source = cm.originOf(key, cm.origins)
+		} else {
+			change.Source = cm.originOf(key, oldOrigins)
+		}
+		changes = append(changes, change)
+	}
+	for key, newValue := range newLeaves {
+		oldValue, hadOld := oldLeaves[key]
+		if !hadOld {
+			oldValue, hadOld = lookupPath(before, cm.splitKey(key))
+		}
+		record(key, oldValue, newValue, hadOld, true)
+	}
+	for key, oldValue := range oldLeaves {
+		if _, ok := newLeaves[key]; ok {
+			continue
+		}
+		newValue, hasNew := lookupPath(after, cm.splitKey(key))
+		record(key, oldValue, newValue, true, hasNew)
+	}
+	if oldLeaves != nil {
+		clear(oldLeaves)
+		leafPool.Put(oldLeaves)
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	cm.changes = changes
+}
+
+// leafPool recycles the flattened settings recordChanges diffs against, so
+// a source reloaded every few seconds does not allocate a new index of its
+// keys each time.
+var leafPool = sync.Pool{New: func() interface{} { return make(map[string]interface{}) }}
+
+// flattenLeaves adds every non-map value in m to leaves, keyed by its path
+// joined with delim.
+func flattenLeaves(m map[string]interface{}, prefix, delim string, leaves map[string]interface{}) {
+	for k, v := range m {
+		if child, ok := v.(map[string]interface{}); ok {
+			flattenLeaves(child, prefix+k+delim, delim, leaves)
+			continue
+		}
+		leaves[prefix+k] = v
+	}
+}
+
+// originOf returns the layer key was taken from. Keys not recorded by the
+// provider come from the runtime defaults.
+func (cm *ConfigManager) originOf(key string, origins map[string]Source) Source {
+	if _, ok := cm.runtime.overrides[key]; ok {
+		return Override
+	}
+	if src, ok := origins[key]; ok {
+		return src
+	}
+	return Defaults
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDiff(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	logger, _ := zap.NewDevelopment()
+
+	t.Run("File Changes", func(t *testing.T) {
+		cfg := New(configPath, logger)
+		require.NoError(t, cfg.Load())
+
+		require.NoError(t, os.WriteFile(configPath, []byte(`
+server:
+  port: 9090
+  host: "localhost"
+  timeout: "30s"
+  tls: true
+database:
+  host: "127.0.0.1"
+  port: 5432
+  name: "testdb"
+`), 0644))
+		require.NoError(t, cfg.Load())
+
+		assert.Equal(t, []Change{
+			{Key: "database.maxconns", Old: 10, Source: File},
+			{Key: "server.port", Old: 8080, New: 9090, Source: File},
+			{Key: "server.tls", New: true, Source: File},
+		}, cfg.Diff())
+
+		require.NoError(t, cfg.Load())
+		assert.Empty(t, cfg.Diff(), "unchanged reload")
+	})
+
+	t.Run("Sources", func(t *testing.T) {
+		configPath, cleanup := setupTestConfig(t)
+		defer cleanup()
+
+		cfg := New(configPath, logger, WithEnvPrefix("APP"))
+		require.NoError(t, cfg.Load())
+
+		t.Setenv("APP_SERVER_HOST", "example.com")
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, []Change{{Key: "server.host", Old: "localhost", New: "example.com", Source: Env}}, cfg.Diff())
+
+		require.NoError(t, cfg.Set("database.name", "other"))
+		assert.Equal(t, []Change{{Key: "database.name", Old: "testdb", New: "other", Source: Override}}, cfg.Diff())
+	})
+}
+
+func TestWatchChanges(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	cfg := New(configPath, zap.NewNop())
+	defer cfg.Close()
+	require.NoError(t, cfg.Load())
+
+	received := make(chan []Change, 10)
+	require.NoError(t, cfg.WatchChanges(context.Background(), func(changes []Change) {
+		received <- changes
+	}))
+
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	require.NoError(t, writeFileAtomic(configPath, append(content, []byte("extra: true\n")...)))
+
+	select {
+	case changes := <-received:
+		assert.Equal(t, []Change{{Key: "extra", New: true, Source: File}}, changes)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for change notification")
+	}
+}
+
+func TestWatchEvents(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	cfg := New(configPath, zap.NewNop(), WithSchema(&TestConfig{}))
+	defer cfg.Close()
+	require.NoError(t, cfg.Load())
+
+	received := make(chan ChangeEvent, 10)
+	require.NoError(t, cfg.WatchEvents(context.Background(), func(event ChangeEvent) {
+		received <- event
+	}))
+	next := func() ChangeEvent {
+		select {
+		case event := <-received:
+			return event
+		case <-time.After(2 * time.Second):
+			t.Fatal("timeout waiting for change event")
+			return ChangeEvent{}
+		}
+	}
+
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	start := time.Now()
+	// Write atomically: a truncated file would be delivered as a rejected reload.
+	require.NoError(t, writeFileAtomic(configPath, append(content, []byte("extra: true\n")...)))
+
+	event := next()
+	require.NoError(t, event.Err)
+	assert.Equal(t, configPath, event.Source)
+	assert.False(t, event.Time.Before(start))
+	assert.Equal(t, []string{"extra"}, event.Keys())
+	assert.Equal(t, true, event.Changes[0].New)
+
+	require.NoError(t, writeFileAtomic(configPath, []byte("server:\n  port: -1\n")))
+	event = next()
+	require.Error(t, event.Err)
+	assert.Empty(t, event.Changes)
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Document writes a Markdown table of every key of schema: its type,
+// default:"..." tag, validate tag, and the environment variable that sets
+// it under envPrefix (none when envPrefix is empty, as env lookups are then
+// disabled). Keys are named and ordered as Scaffold writes them, so the
+// table can be regenerated in CI to keep per-service docs in sync.
+func Document(schema interface{}, envPrefix string, w io.Writer) error {
+	t := reflect.TypeOf(schema)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return fmt.Errorf("schema must be a struct or pointer to struct, got %T", schema)
+	}
+	root, err := scaffoldFields(t, "")
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString("| Key | Type | Default | Validation | Environment |\n")
+	bw.WriteString("|-----|------|---------|------------|-------------|\n")
+	writeDocRows(bw, root, "", envPrefix)
+	return bw.Flush()
+}
+
+func writeDocRows(w *bufio.Writer, nodes []*scaffoldNode, prefix, envPrefix string) {
+	for _, n := range nodes {
+		key := prefix + n.key
+		if n.table {
+			writeDocRows(w, n.children, key+".", envPrefix)
+			continue
+		}
+
+		def := n.def
+		if def != "" {
+			def = "`" + def + "`"
+		}
+		if n.secret {
+			def = "secret"
+		}
+		rules := n.rules
+		if rules != "" {
+			rules = "`" + rules + "`"
+		}
+		env := ""
+		if envPrefix != "" {
+			env = "`" + strings.ToUpper(envPrefix+"_"+strings.ReplaceAll(key, ".", "_")) + "`"
+		}
+		fmt.Fprintf(w, "| `%s` | %s | %s | %s | %s |\n",
+			key, markdownCell(n.typ.String()), markdownCell(def), markdownCell(rules), env)
+	}
+}
+
+// markdownCell escapes the pipes in a table cell.
+func markdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
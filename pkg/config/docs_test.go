@@ -0,0 +1,40 @@
+package config
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocument(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Document(&scaffoldConfig{}, "app", &buf))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2+7)
+	assert.Equal(t, "| Key | Type | Default | Validation | Environment |", lines[0])
+	assert.Equal(t, "| `server.host` | string | `localhost` | `required,hostname` | `APP_SERVER_HOST` |", lines[2])
+	assert.Equal(t, "| `server.timeout` | time.Duration | `30s` |  | `APP_SERVER_TIMEOUT` |", lines[4])
+	assert.Equal(t, "| `database.password` | string | secret |  | `APP_DATABASE_PASSWORD` |", lines[6])
+	assert.Equal(t, "| `debug` | bool |  |  | `APP_DEBUG` |", lines[8])
+
+	t.Run("Without Prefix", func(t *testing.T) {
+		buf.Reset()
+		require.NoError(t, Document(scaffoldConfig{}, "", &buf))
+		assert.NotContains(t, buf.String(), "APP_")
+	})
+
+	t.Run("Escapes Pipes", func(t *testing.T) {
+		type cfg struct {
+			Mode string `mapstructure:"mode" validate:"oneof=a b|eq=c"`
+		}
+		buf.Reset()
+		require.NoError(t, Document(&cfg{}, "", &buf))
+		assert.Contains(t, buf.String(), "`oneof=a b\\|eq=c`")
+	})
+
+	assert.Error(t, Document("nope", "", &buf))
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Validate reads the configuration source again and checks it the way Load
+// would, with the same defaults, environment, runtime changes, sections,
+// and schema, without applying it. Deployment pipelines and health checks
+// can use it to find out whether the current source would be accepted.
+func (cm *ConfigManager) Validate() error {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	if cm.closed {
+		return ErrClosed
+	}
+	return cm.checkCandidate(cm.candidateProvider())
+}
+
+// DryRun checks the config file at path as if it replaced the manager's
+// config file, without applying it or touching the live configuration, so
+// a candidate file can be gated on before a restart or rollout.
+func (cm *ConfigManager) DryRun(path string) error {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	if cm.closed {
+		return ErrClosed
+	}
+	return cm.checkCandidate(cm.fileCandidate(path, nil))
+}
+
+// dryRunData checks data as if it were the content of the manager's config
+// file, like DryRun.
+func (cm *ConfigManager) dryRunData(data []byte) error {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	if cm.closed {
+		return ErrClosed
+	}
+	return cm.checkCandidate(cm.fileCandidate(cm.path, data))
+}
+
+// fileCandidate returns a provider reading the config file at path, or data
+// in its place if it is not nil, with the manager's other sources. The
+// caller must hold cm.mu.
+func (cm *ConfigManager) fileCandidate(path string, data []byte) ConfigProvider {
+	return &LocalConfigProvider{
+		logger:      cm.logger,
+		path:        path,
+		data:        data,
+		defaults:    cm.defaults,
+		envPrefix:   cm.envPrefix,
+		envNames:    cm.envBindings,
+		jsonnet:     cm.jsonnet,
+		funcs:       cm.templateFuncs,
+		profile:     cm.profile,
+		interpolate: !cm.noInterpolation,
+		configType:  cm.configType,
+		precedence:  cm.precedence,
+		flags:       cm.flags,
+		delimiter:   cm.keyDelimiter,
+		overrides:   cm.overrideFiles,
+		merge:       newMergePolicy(cm.mergeRules, cm.keyDelimiter),
+	}
+}
+
+// candidateProvider returns a provider reading the same source as the
+// manager's without sharing its change tracking or watch registrations.
+// The caller must hold cm.mu.
+func (cm *ConfigManager) candidateProvider() ConfigProvider {
+	switch p := cm.provider.(type) {
+	case *LocalConfigProvider:
+		c := *p
+		c.tracker, c.targets = contentTracker{}, nil
+		return &c
+	case *RemoteConfigProvider:
+		c := *p
+		c.tracker, c.targets = contentTracker{}, nil
+		return &c
+	default:
+		return cm.provider
+	}
+}
+
+// checkCandidate loads provider into a scratch instance and decodes and
+// validates it into fresh copies of the schema, sections, and bindings. The caller
+// must hold cm.mu.
+func (cm *ConfigManager) checkCandidate(provider ConfigProvider) error {
+	next := cm.newViper()
+	if err := provider.Load(next); err != nil {
+		return err
+	}
+	next, err := cm.applyMigrations(next)
+	if err != nil {
+		return err
+	}
+	next, _, err = cm.applyDeprecations(next)
+	if err != nil {
+		return err
+	}
+	next, err = cm.applyRuntime(next)
+	if err != nil {
+		return err
+	}
+	next, err = cm.applyReferences(next)
+	if err != nil {
+		return err
+	}
+	next, _, err = cm.applySecrets(next, true)
+	if err != nil {
+		return err
+	}
+
+	for _, sec := range cm.sections {
+		if err := cm.decodeSchema(next, sec.key, scratchCopy(sec.schema)); err != nil {
+			return &SectionError{Section: sec.key, Err: err}
+		}
+	}
+	for _, b := range cm.bindings {
+		if err := cm.decodeSchema(next, "", scratchCopy(b.ptr)); err != nil {
+			return fmt.Errorf("bound %T: %w", b.ptr, err)
+		}
+	}
+	if cm.schema != nil {
+		return cm.decodeSchema(next, "", scratchCopy(cm.schema))
+	}
+	return nil
+}
+
+// scratchCopy returns a new zero value of the type schema points to.
+func scratchCopy(schema interface{}) interface{} {
+	t := reflect.TypeOf(schema)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return schema
+	}
+	return reflect.New(t.Elem()).Interface()
+}
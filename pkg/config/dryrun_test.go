@@ -0,0 +1,56 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDryRun(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	schema := &TestConfig{}
+	cfg := New(configPath, zap.NewNop(), WithSchema(schema), WithHighFrequencyReload())
+	require.NoError(t, cfg.Load())
+	generation := cfg.Generation()
+
+	t.Run("Validate Current Source", func(t *testing.T) {
+		require.NoError(t, cfg.Validate())
+
+		content, err := os.ReadFile(configPath)
+		require.NoError(t, err)
+		defer os.WriteFile(configPath, content, 0644)
+		require.NoError(t, os.WriteFile(configPath, []byte("server:\n  port: 0\n"), 0644))
+
+		err = cfg.Validate()
+		var verrs ValidationErrors
+		require.True(t, errors.As(err, &verrs))
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+		assert.Equal(t, 8080, schema.Server.Port)
+	})
+
+	t.Run("Candidate File", func(t *testing.T) {
+		candidate := filepath.Join(t.TempDir(), "candidate.yaml")
+		require.NoError(t, os.WriteFile(candidate, []byte("server:\n  port: 9090\n  host: localhost\n  timeout: 5s\ndatabase:\n  host: db\n  port: 5432\n  name: app\n  maxConns: 5\n"), 0644))
+		require.NoError(t, cfg.DryRun(candidate))
+
+		require.NoError(t, os.WriteFile(candidate, []byte("server:\n  port: 70000\n"), 0644))
+		assert.Error(t, cfg.DryRun(candidate))
+		assert.Error(t, cfg.DryRun(filepath.Join(t.TempDir(), "missing.yaml")))
+	})
+
+	// Nothing was applied, and the next load still sees the source as unchanged.
+	assert.Equal(t, generation, cfg.Generation())
+	assert.Equal(t, 8080, schema.Server.Port)
+	require.NoError(t, cfg.Load())
+	assert.Equal(t, generation, cfg.Generation())
+
+	require.NoError(t, cfg.Close())
+	assert.ErrorIs(t, cfg.Validate(), ErrClosed)
+}
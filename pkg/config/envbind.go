@@ -0,0 +1,41 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// WithEnvBindings maps config keys to environment variables with names of
+// their own, e.g. {"server.port": "PORT"}, for platforms that dictate the
+// variable names. Bound variables belong to the Env source and work without
+// WithEnvPrefix; a bound key is set whenever its variable is, even if no
+// other source knows it or there is no config file. With WithEnvPrefix, the
+// PREFIX_KEY variable still applies to a bound key and wins when both are
+// set.
+func WithEnvBindings(bindings map[string]string) Option {
+	return func(cm *ConfigManager) {
+		if cm.envBindings == nil {
+			cm.envBindings = make(map[string]string, len(bindings))
+		}
+		for key, name := range bindings {
+			cm.envBindings[strings.ToLower(key)] = name
+		}
+	}
+}
+
+// lookupEnv returns the value of the environment variable for key, PREFIX_KEY
+// or the variable bound to key, and its name.
+func lookupEnv(key, prefix, delim string, bindings map[string]string) (value, name string, ok bool) {
+	if prefix != "" {
+		name = strings.ToUpper(prefix + "_" + strings.ReplaceAll(key, delim, "_"))
+		if value, ok = os.LookupEnv(name); ok {
+			return value, name, true
+		}
+	}
+	if bound, isBound := bindings[key]; isBound {
+		if value, ok = os.LookupEnv(bound); ok {
+			return value, bound, true
+		}
+	}
+	return "", name, false
+}
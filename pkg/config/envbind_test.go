@@ -0,0 +1,60 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestEnvBindings(t *testing.T) {
+	bindings := map[string]string{"server.port": "PORT", "database.url": "DATABASE_URL"}
+
+	t.Run("Without Config File", func(t *testing.T) {
+		t.Setenv("PORT", "9090")
+		t.Setenv("DATABASE_URL", "postgres://db/app")
+		cfg := New(filepath.Join(t.TempDir(), "missing.yaml"), zap.NewNop(), WithEnvBindings(bindings))
+		require.NoError(t, cfg.Load())
+
+		assert.ElementsMatch(t, []string{"server.port", "database.url"}, cfg.AllKeys())
+		assert.Equal(t, 9090, cfg.GetInt("server.port"))
+		assert.Equal(t, Env, cfg.Origin("database.url"))
+		assert.Equal(t, "DATABASE_URL", cfg.OriginName("database.url"))
+	})
+
+	t.Run("Unset Variable", func(t *testing.T) {
+		configPath, cleanup := setupTestConfig(t)
+		defer cleanup()
+		cfg := New(configPath, zap.NewNop(), WithEnvBindings(bindings))
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+		assert.False(t, cfg.IsSet("database.url"))
+	})
+
+	t.Run("With Prefix", func(t *testing.T) {
+		configPath, cleanup := setupTestConfig(t)
+		defer cleanup()
+		t.Setenv("PORT", "9090")
+		t.Setenv("APP_DATABASE_MAXCONNS", "20")
+		cfg := New(configPath, zap.NewNop(), WithEnvPrefix("APP"), WithEnvBindings(bindings))
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 9090, cfg.GetInt("server.port"))
+		assert.Equal(t, 20, cfg.GetInt("database.maxconns"))
+
+		t.Setenv("APP_SERVER_PORT", "7070")
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 7070, cfg.GetInt("server.port"))
+		assert.Equal(t, "APP_SERVER_PORT", cfg.OriginName("server.port"))
+	})
+
+	t.Run("Below File", func(t *testing.T) {
+		configPath, cleanup := setupTestConfig(t)
+		defer cleanup()
+		t.Setenv("PORT", "9090")
+		cfg := New(configPath, zap.NewNop(), WithEnvBindings(bindings), WithPrecedence(File, Env))
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+	})
+}
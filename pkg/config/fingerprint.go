@@ -0,0 +1,60 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Fingerprint returns the hex SHA-256 of the effective settings, with
+// secret values masked as in AllSettingsRedacted, or "" before the first
+// load. Unlike SourceHash, it covers environment variables and runtime
+// changes, and it is the same on every replica running with the same
+// non-secret configuration, so deployment tooling can check that replicas
+// converged.
+func (cm *ConfigManager) Fingerprint() string {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.fingerprint()
+}
+
+// fingerprint computes Fingerprint. The caller must hold cm.mu.
+func (cm *ConfigManager) fingerprint() string {
+	if !cm.loaded {
+		return ""
+	}
+	settings := cm.redact(deepCopyMap(cm.viper.AllSettings()))
+	// Both encodings sort map keys, so equal settings encode equally.
+	data, err := json.Marshal(settings)
+	if err != nil {
+		data = []byte(fmt.Sprintf("%#v", settings))
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// FingerprintChecker is a health check for readiness endpoints, with the
+// Check(ctx) error method healthcheck packages call. It fails until Config
+// is loaded and, if Expected is set, while the fingerprint of Config
+// differs from it, e.g. from the fingerprint of the config version being
+// rolled out.
+type FingerprintChecker struct {
+	Config   *ConfigManager
+	Expected string
+}
+
+// Check reports whether the configuration is loaded and has the expected
+// fingerprint.
+func (c FingerprintChecker) Check(context.Context) error {
+	fp := c.Config.Fingerprint()
+	if fp == "" {
+		return errors.New("configuration not loaded")
+	}
+	if c.Expected != "" && fp != c.Expected {
+		return fmt.Errorf("config fingerprint %s, want %s", fp, c.Expected)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestFingerprint(t *testing.T) {
+	content := `
+server:
+  port: 8080
+  hosts: [a, b]
+database:
+  password: hunter2
+`
+	newConfig := func(content string) *ConfigManager {
+		cfg := New(writeInterpolateConfig(t, content), zap.NewNop(), WithSecretKeys("*.password"))
+		require.NoError(t, cfg.Load())
+		return cfg
+	}
+
+	t.Run("Stable Across Replicas", func(t *testing.T) {
+		a, b := newConfig(content), newConfig(content)
+		assert.Len(t, a.Fingerprint(), 64)
+		assert.Equal(t, a.Fingerprint(), b.Fingerprint())
+		assert.Equal(t, a.Fingerprint(), a.Fingerprint())
+	})
+
+	t.Run("Ignores Secret Values", func(t *testing.T) {
+		a := newConfig(content)
+		b := newConfig(`
+server:
+  port: 8080
+  hosts: [a, b]
+database:
+  password: rotated
+`)
+		assert.Equal(t, a.Fingerprint(), b.Fingerprint())
+	})
+
+	t.Run("Tracks Effective Settings", func(t *testing.T) {
+		cfg := newConfig(content)
+		before := cfg.Fingerprint()
+		require.NoError(t, cfg.Set("server.port", 9090))
+		assert.NotEqual(t, before, cfg.Fingerprint())
+	})
+
+	t.Run("Checker", func(t *testing.T) {
+		cfg := New(writeInterpolateConfig(t, content), zap.NewNop())
+		assert.ErrorContains(t, FingerprintChecker{Config: cfg}.Check(context.Background()), "not loaded")
+
+		require.NoError(t, cfg.Load())
+		assert.NoError(t, FingerprintChecker{Config: cfg}.Check(context.Background()))
+		assert.NoError(t, FingerprintChecker{Config: cfg, Expected: cfg.Fingerprint()}.Check(context.Background()))
+		assert.ErrorContains(t, FingerprintChecker{Config: cfg, Expected: "abc"}.Check(context.Background()), "want abc")
+	})
+}
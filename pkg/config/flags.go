@@ -0,0 +1,68 @@
+package config
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/spf13/cast"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// WithFlags adds the flags of fs as the Flags source. Only flags given on
+// the command line are read, so a flag's default does not hide the value
+// of a lower source, and each flag sets the key it is named after, e.g.
+// --server.port sets server.port. By default flags have the highest
+// precedence; use WithPrecedence to move them.
+func WithFlags(fs *pflag.FlagSet) Option {
+	return func(cm *ConfigManager) {
+		cm.flags = fs
+	}
+}
+
+// setFlags stores the flags changed in fs as the flags layer.
+func (l layers) setFlags(fs *pflag.FlagSet, delim string) {
+	if fs == nil {
+		return
+	}
+	settings := make(map[string]interface{})
+	fs.Visit(func(f *pflag.Flag) {
+		setPath(settings, strings.Split(strings.ToLower(f.Name), delim), flagValue(f))
+	})
+	l[Flags] = settings
+}
+
+// bindFlags binds the flags changed in fs to v when apply enabled viper's
+// automatic env lookup, which would otherwise take precedence over them.
+func bindFlags(v *viper.Viper, fs *pflag.FlagSet, precedence []Source, envPrefix string) {
+	if fs == nil || envPrefix == "" || !automaticEnv(precedence) || !slices.Contains(precedence, Flags) {
+		return
+	}
+	fs.Visit(func(f *pflag.Flag) {
+		_ = v.BindPFlag(f.Name, f)
+	})
+}
+
+// flagValue returns the value of f with the type of the flag.
+func flagValue(f *pflag.Flag) interface{} {
+	if s, ok := f.Value.(pflag.SliceValue); ok {
+		values := s.GetSlice()
+		out := make([]interface{}, len(values))
+		for i, v := range values {
+			out[i] = v
+		}
+		return out
+	}
+	value := f.Value.String()
+	switch f.Value.Type() {
+	case "bool":
+		return cast.ToBool(value)
+	case "int", "int8", "int16", "int32", "int64", "count":
+		return cast.ToInt64(value)
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		return cast.ToUint64(value)
+	case "float32", "float64":
+		return cast.ToFloat64(value)
+	}
+	return value
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestFlags(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	newFlags := func(args ...string) *pflag.FlagSet {
+		fs := pflag.NewFlagSet("app", pflag.ContinueOnError)
+		fs.Int("server.port", 1234, "")
+		fs.String("server.host", "flag-default", "")
+		fs.StringSlice("cache.tags", nil, "")
+		fs.Bool("only.flag", false, "")
+		require.NoError(t, fs.Parse(args))
+		return fs
+	}
+
+	t.Run("Above Env By Default", func(t *testing.T) {
+		t.Setenv("APP_SERVER_PORT", "9090")
+		t.Setenv("APP_ONLY_FLAG", "false")
+		fs := newFlags("--server.port=7070", "--cache.tags=a,b", "--only.flag")
+		cfg := New(configPath, zap.NewNop(), WithEnvPrefix("APP"), WithFlags(fs))
+		require.NoError(t, cfg.Load())
+
+		assert.Equal(t, 7070, cfg.GetInt("server.port"))
+		assert.Equal(t, "localhost", cfg.GetString("server.host"))
+		assert.Equal(t, []string{"a", "b"}, cfg.GetStringSlice("cache.tags"))
+		assert.True(t, cfg.GetBool("only.flag"))
+		assert.Equal(t, Flags, cfg.Origin("server.port"))
+		assert.Equal(t, "--server.port", cfg.OriginName("server.port"))
+	})
+
+	t.Run("Reordered", func(t *testing.T) {
+		t.Setenv("APP_SERVER_PORT", "9090")
+		fs := newFlags("--server.port=7070", "--server.host=flag-host")
+		cfg := New(configPath, zap.NewNop(), WithEnvPrefix("APP"), WithFlags(fs),
+			WithPrecedence(File, Env, Flags, Defaults))
+		require.NoError(t, cfg.Load())
+
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+		assert.Equal(t, "localhost", cfg.GetString("server.host"))
+		assert.Equal(t, File, cfg.Origin("server.port"))
+	})
+
+	t.Run("Left Out", func(t *testing.T) {
+		fs := newFlags("--server.port=7070")
+		cfg := New(configPath, zap.NewNop(), WithFlags(fs), WithPrecedence(Env, File))
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+	})
+}
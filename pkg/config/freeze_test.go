@@ -0,0 +1,66 @@
+package config
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestFreeze(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	t.Run("Before Load", func(t *testing.T) {
+		cfg := New(configPath, zap.NewNop())
+		assert.Error(t, cfg.Freeze())
+		assert.False(t, cfg.Frozen())
+	})
+
+	t.Run("Rejects Changes", func(t *testing.T) {
+		cfg := New(configPath, zap.NewNop())
+		require.NoError(t, cfg.Load())
+		require.NoError(t, cfg.SetWithTTL("server.port", 9090, 20*time.Millisecond))
+		require.NoError(t, cfg.Freeze())
+		assert.True(t, cfg.Frozen())
+
+		require.NoError(t, os.WriteFile(configPath, []byte("server:\n  port: 1\n"), 0644))
+		assert.ErrorIs(t, cfg.Load(), ErrFrozen)
+		assert.ErrorIs(t, cfg.Set("server.host", "other"), ErrFrozen)
+		assert.ErrorIs(t, cfg.Unset("server.port"), ErrFrozen)
+		assert.ErrorIs(t, cfg.Update(func(tx *Tx) error { return nil }), ErrFrozen)
+
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, 9090, cfg.GetInt("server.port"))
+		assert.Equal(t, "localhost", cfg.GetString("server.host"))
+	})
+
+	t.Run("WithFrozen", func(t *testing.T) {
+		cfg := New(configPath, zap.NewNop(), WithFrozen())
+		assert.False(t, cfg.Frozen())
+		require.NoError(t, cfg.Load())
+		assert.True(t, cfg.Frozen())
+		assert.ErrorIs(t, cfg.Load(), ErrFrozen)
+	})
+
+	t.Run("Concurrent Reads", func(t *testing.T) {
+		cfg := New(configPath, zap.NewNop(), WithFrozen())
+		require.NoError(t, cfg.Load())
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					_ = cfg.GetInt("server.port")
+					_ = cfg.AllSettings()
+				}
+			}()
+		}
+		wg.Wait()
+	})
+}
@@ -0,0 +1,280 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// ConfigzPath is the path Handler serves the effective configuration at.
+const ConfigzPath = "/configz"
+
+// maxConfigzBody bounds the size of a document posted to /configz/validate.
+const maxConfigzBody = 4 << 20
+
+// HandlerOption configures a Handler.
+type HandlerOption func(*handlerOptions)
+
+type handlerOptions struct {
+	auth func(*http.Request) error
+}
+
+// WithAdminAuth enables the admin endpoints of a Handler, POST
+// /configz/reload and POST /configz/validate. Requests for which auth
+// returns an error, e.g. for a missing bearer token, are refused with 403
+// Forbidden.
+func WithAdminAuth(auth func(r *http.Request) error) HandlerOption {
+	return func(o *handlerOptions) {
+		o.auth = auth
+	}
+}
+
+// Handler returns an http.Handler serving GET /configz, a JSON document with
+// the redacted effective settings, the source and origin of each key, the
+// generation, source hash, and fingerprint, the annotations of the live
+// version, and the watcher status, in the manner of the Kubernetes *z
+// endpoints. Mount it on a debug mux:
+//
+//	debugMux.Handle(config.ConfigzPath, cfg.Handler())
+//
+// Values of secret keys are masked as in AllSettingsRedacted, but keys are
+// listed, so only expose it where config keys may be seen.
+//
+// With WithAdminAuth, mounted on ConfigzPath+"/" as well, it also serves
+// POST /configz/reload, which reloads the configuration even if its source
+// is unchanged and responds with the changes, and POST /configz/validate,
+// which checks the posted document as DryRun would check a config file
+// holding it, without applying it. Both respond with 422 Unprocessable
+// Entity and the error, including each failed validation rule, when the
+// configuration is rejected. Without WithAdminAuth they are refused.
+func (cm *ConfigManager) Handler(opts ...HandlerOption) http.Handler {
+	var o handlerOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+ConfigzPath, cm.serveConfigz)
+	mux.HandleFunc("POST "+ConfigzPath+"/reload", o.admin(cm.serveReload))
+	mux.HandleFunc("POST "+ConfigzPath+"/validate", o.admin(cm.serveValidate))
+	return mux
+}
+
+// admin wraps an admin endpoint with the auth hook.
+func (o handlerOptions) admin(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if o.auth == nil {
+			writeJSON(w, http.StatusForbidden, adminResult{Error: "admin endpoints are disabled"})
+			return
+		}
+		if err := o.auth(r); err != nil {
+			writeJSON(w, http.StatusForbidden, adminResult{Error: err.Error()})
+			return
+		}
+		h(w, r)
+	}
+}
+
+// configz is the document served at /configz.
+type configz struct {
+	Settings       map[string]interface{} `json:"settings"`
+	Origins        map[string]keyOrigin   `json:"origins"`
+	Generation     uint64                 `json:"generation"`
+	SourceHash     string                 `json:"sourceHash,omitempty"`
+	Fingerprint    string                 `json:"fingerprint,omitempty"`
+	LastLoaded     *time.Time             `json:"lastLoaded,omitempty"`
+	Profile        string                 `json:"profile,omitempty"`
+	RemoteRevision string                 `json:"remoteRevision,omitempty"`
+	Annotations    *Annotations           `json:"annotations,omitempty"`
+	Stale          bool                   `json:"stale"`
+	Frozen         bool                   `json:"frozen"`
+	Watch          watchz                 `json:"watch"`
+}
+
+// keyOrigin is where the value of a key in configz came from.
+type keyOrigin struct {
+	Source string `json:"source"`
+	Origin string `json:"origin,omitempty"`
+	Secret bool   `json:"secret,omitempty"`
+}
+
+// watchz is the WatchStatus in configz.
+type watchz struct {
+	Active              bool       `json:"active"`
+	Paused              bool       `json:"paused"`
+	Mode                string     `json:"mode,omitempty"`
+	LastSuccess         *time.Time `json:"lastSuccess,omitempty"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	LastError           string     `json:"lastError,omitempty"`
+	NextPoll            *time.Time `json:"nextPoll,omitempty"`
+}
+
+func (cm *ConfigManager) serveConfigz(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, cm.configz())
+}
+
+// configz returns the current configz document.
+func (cm *ConfigManager) configz() configz {
+	cm.mu.RLock()
+	keys := cm.viper.AllKeys()
+	sort.Strings(keys)
+	secrets := cm.secretPatterns()
+	doc := configz{
+		Settings:    cm.redact(deepCopyMap(cm.viper.AllSettings())),
+		Origins:     make(map[string]keyOrigin, len(keys)),
+		Generation:  cm.generation,
+		SourceHash:  cm.appliedHash,
+		Fingerprint: cm.fingerprint(),
+		LastLoaded:  optionalTime(cm.lastLoad),
+		Profile:     cm.profile,
+		Frozen:      cm.frozen.Load(),
+	}
+	if !cm.annotations.IsZero() {
+		annotations := cm.annotations
+		doc.Annotations = &annotations
+	}
+	for _, key := range keys {
+		doc.Origins[key] = keyOrigin{
+			Source: cm.origin(key).String(),
+			Origin: cm.originName(key),
+			Secret: isSecret(key, secrets, cm.keyDelimiter),
+		}
+	}
+	if r, ok := cm.provider.(*RemoteConfigProvider); ok {
+		doc.RemoteRevision, doc.Stale = r.revision, r.stale
+	}
+	cm.mu.RUnlock()
+
+	status := cm.WatchStatus()
+	doc.Watch = watchz{
+		Active:              status.Active,
+		Paused:              status.Paused,
+		Mode:                status.Mode,
+		LastSuccess:         optionalTime(status.LastSuccess),
+		ConsecutiveFailures: status.ConsecutiveFailures,
+		NextPoll:            optionalTime(status.NextPoll),
+	}
+	if status.LastError != nil {
+		doc.Watch.LastError = status.LastError.Error()
+	}
+	return doc
+}
+
+// adminResult is the response of the admin endpoints.
+type adminResult struct {
+	Generation       uint64        `json:"generation,omitempty"`
+	Changes          []changez     `json:"changes,omitempty"`
+	Error            string        `json:"error,omitempty"`
+	ValidationErrors []validationz `json:"validationErrors,omitempty"`
+}
+
+// changez is a Change in an adminResult, with secret values masked.
+type changez struct {
+	Key    string      `json:"key"`
+	Old    interface{} `json:"old,omitempty"`
+	New    interface{} `json:"new,omitempty"`
+	Source string      `json:"source"`
+	Secret bool        `json:"secret,omitempty"`
+}
+
+// validationz is a ValidationError in an adminResult.
+type validationz struct {
+	Key     string      `json:"key"`
+	Value   interface{} `json:"value,omitempty"`
+	Rule    string      `json:"rule,omitempty"`
+	Param   string      `json:"param,omitempty"`
+	Message string      `json:"message"`
+}
+
+func (cm *ConfigManager) serveReload(w http.ResponseWriter, _ *http.Request) {
+	event := cm.forceReload()
+	res := adminResult{Generation: cm.Generation()}
+	for _, c := range event.Changes {
+		change := changez{Key: c.Key, Old: c.Old, New: c.New, Source: c.Source.String(), Secret: c.Secret}
+		if c.Secret {
+			change.Old, change.New = maskValue(c.Old), maskValue(c.New)
+		}
+		res.Changes = append(res.Changes, change)
+	}
+	writeJSON(w, adminStatus(event.Err, &res), res)
+}
+
+func (cm *ConfigManager) serveValidate(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxConfigzBody))
+	if err != nil {
+		writeJSON(w, http.StatusRequestEntityTooLarge, adminResult{Error: err.Error()})
+		return
+	}
+	var res adminResult
+	writeJSON(w, adminStatus(cm.dryRunData(data), &res), res)
+}
+
+// forceReload reloads the configuration even if its source is unchanged
+// and delivers the outcome to subscribers.
+func (cm *ConfigManager) forceReload() ChangeEvent {
+	cm.mu.Lock()
+	if c, ok := cm.provider.(committer); ok {
+		c.invalidate()
+	}
+	cm.mu.Unlock()
+
+	event := cm.loadEvent()
+	if event.Err != nil || len(event.Changes) > 0 {
+		cm.publish(event)
+	}
+	return event
+}
+
+// adminStatus records err in res and returns the status code of the
+// response.
+func adminStatus(err error, res *adminResult) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	res.Error = err.Error()
+	var errs ValidationErrors
+	var verr *ValidationError
+	if errors.As(err, &errs) {
+		for _, e := range errs {
+			res.ValidationErrors = append(res.ValidationErrors, validationz(*e))
+		}
+	} else if errors.As(err, &verr) {
+		res.ValidationErrors = append(res.ValidationErrors, validationz(*verr))
+	}
+	switch {
+	case errors.Is(err, ErrClosed):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, ErrFrozen):
+		return http.StatusConflict
+	default:
+		return http.StatusUnprocessableEntity
+	}
+}
+
+// maskValue returns RedactedValue for a set value and nil otherwise.
+func maskValue(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	return RedactedValue
+}
+
+// writeJSON writes v as an indented JSON response with status code.
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+// optionalTime returns nil for the zero time, so it is omitted from JSON.
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,187 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHandler(t *testing.T) {
+	configPath := writeInterpolateConfig(t, `
+server:
+  port: 8080
+database:
+  password: hunter2
+`)
+	cfg := New(configPath, zap.NewNop(), WithSecretKeys("*.password"))
+	require.NoError(t, cfg.Load())
+	mux := http.NewServeMux()
+	mux.Handle(ConfigzPath, cfg.Handler())
+
+	t.Run("Serves Redacted Config", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/configz", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.NotContains(t, rec.Body.String(), "hunter2")
+
+		var doc configz
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+		assert.Equal(t, RedactedValue, doc.Settings["database"].(map[string]interface{})["password"])
+		assert.EqualValues(t, 8080, doc.Settings["server"].(map[string]interface{})["port"])
+		assert.Equal(t, keyOrigin{Source: "file", Origin: configPath}, doc.Origins["server.port"])
+		assert.True(t, doc.Origins["database.password"].Secret)
+		assert.Equal(t, uint64(1), doc.Generation)
+		assert.Equal(t, cfg.SourceHash(), doc.SourceHash)
+		assert.NotNil(t, doc.LastLoaded)
+		assert.False(t, doc.Watch.Active)
+		assert.Nil(t, doc.Watch.NextPoll)
+	})
+
+	t.Run("Serves Annotations", func(t *testing.T) {
+		remote := useFakeRemote(t)
+		remote.set("app/config", []byte(`{
+			"_annotations": {"author": "jdoe", "ticket": "OPS-42", "message": "raise port"},
+			"server": {"port": 9090}
+		}`))
+		remoteCfg := newRemoteTestConfig()
+		defer remoteCfg.Close()
+		require.NoError(t, remoteCfg.Load())
+
+		rec := httptest.NewRecorder()
+		remoteCfg.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/configz", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var doc map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+		assert.Equal(t, map[string]interface{}{"author": "jdoe", "ticket": "OPS-42", "message": "raise port"},
+			doc["annotations"])
+		assert.NotContains(t, doc["settings"], AnnotationsKey)
+
+		// Unattributed versions omit them.
+		rec = httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/configz", nil))
+		var plain map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &plain))
+		assert.NotContains(t, plain, "annotations")
+	})
+
+	t.Run("Rejects Other Methods", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/configz", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+}
+
+func TestHandlerAdmin(t *testing.T) {
+	configPath := writeInterpolateConfig(t, `
+server:
+  port: 8080
+  host: localhost
+  timeout: 30s
+database:
+  host: 127.0.0.1
+  port: 5432
+  name: testdb
+  maxConns: 10
+  password: hunter2
+`)
+	var schema TestConfig
+	cfg := New(configPath, zap.NewNop(), WithSchema(&schema), WithSecretKeys("*.password"))
+	require.NoError(t, cfg.Load())
+	auth := WithAdminAuth(func(r *http.Request) error {
+		if r.Header.Get("Authorization") != "Bearer ops" {
+			return errors.New("invalid token")
+		}
+		return nil
+	})
+	mux := http.NewServeMux()
+	mux.Handle(ConfigzPath, cfg.Handler(auth))
+	mux.Handle(ConfigzPath+"/", cfg.Handler(auth))
+
+	post := func(path, body string) (*httptest.ResponseRecorder, adminResult) {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer ops")
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		var res adminResult
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		return rec, res
+	}
+
+	t.Run("Requires Auth", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/configz/reload", nil))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Contains(t, rec.Body.String(), "invalid token")
+
+		rec = httptest.NewRecorder()
+		cfg.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/configz/reload", nil))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Contains(t, rec.Body.String(), "disabled")
+	})
+
+	t.Run("Reload Returns Changes", func(t *testing.T) {
+		content, err := os.ReadFile(configPath)
+		require.NoError(t, err)
+		updated := strings.Replace(strings.Replace(string(content), "8080", "9090", 1), "hunter2", "hunter3", 1)
+		require.NoError(t, os.WriteFile(configPath, []byte(updated), 0600))
+
+		rec, res := post("/configz/reload", "")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.NotContains(t, rec.Body.String(), "hunter")
+		assert.Equal(t, cfg.Generation(), res.Generation)
+		assert.Contains(t, res.Changes, changez{Key: "server.port", Old: float64(8080), New: float64(9090), Source: "file"})
+		assert.Contains(t, res.Changes, changez{Key: "database.password", Old: RedactedValue, New: RedactedValue, Source: "file", Secret: true})
+		assert.Equal(t, 9090, cfg.GetInt("server.port"))
+
+		_, res = post("/configz/reload", "")
+		assert.Empty(t, res.Changes)
+	})
+
+	t.Run("Reload Rejected", func(t *testing.T) {
+		content, err := os.ReadFile(configPath)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(configPath, []byte(strings.Replace(string(content), "9090", "0", 1)), 0600))
+		defer os.WriteFile(configPath, content, 0600)
+
+		rec, res := post("/configz/reload", "")
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		require.Len(t, res.ValidationErrors, 1)
+		assert.Equal(t, "server.port", res.ValidationErrors[0].Key)
+		assert.Equal(t, "required", res.ValidationErrors[0].Rule)
+		assert.Equal(t, 9090, cfg.GetInt("server.port"))
+	})
+
+	t.Run("Validate Does Not Apply", func(t *testing.T) {
+		rec, res := post("/configz/validate", `
+server:
+  port: 7070
+  host: localhost
+  timeout: 30s
+database:
+  host: 127.0.0.1
+  port: 5432
+  name: testdb
+  maxConns: 10
+`)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Empty(t, res.Error)
+		assert.Equal(t, 9090, cfg.GetInt("server.port"))
+
+		rec, res = post("/configz/validate", `
+server:
+  port: 70000
+`)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.NotEmpty(t, res.ValidationErrors)
+		assert.Equal(t, 9090, cfg.GetInt("server.port"))
+	})
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"context"
+	"fmt"
+)
+
+// CheckHealth reports whether the remote provider is reachable and its path
+// holds a document, by fetching it without applying it: the settings, the
+// cached fallback, and the remote revision are left alone. It returns nil
+// for managers without a remote provider and ErrClosed once closed. Its
+// signature matches the Check(ctx) error method of healthcheck packages, so
+// a readiness probe can keep a pod out of rotation while its config backend
+// is unreachable:
+//
+//	health.Register("config", cfg.CheckHealth)
+func (cm *ConfigManager) CheckHealth(ctx context.Context) error {
+	cm.mu.RLock()
+	closed := cm.closed
+	cm.mu.RUnlock()
+	if closed {
+		return ErrClosed
+	}
+	rp := cm.remoteProvider
+	if rp == nil {
+		return nil
+	}
+
+	// Backends such as viper's may not honor ctx, so wait for it here. The
+	// fetch runs under the supervisor, so Close cancels it and waits for it.
+	errCh := make(chan error, 1)
+	started := cm.sup.Go("health-check", func(supCtx context.Context) {
+		fetchCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		defer context.AfterFunc(supCtx, cancel)()
+		_, err := fetchRemote(fetchCtx, rp)
+		errCh <- err
+	})
+	if !started {
+		return ErrClosed
+	}
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return fmt.Errorf("remote config '%s' on %s %s: %w", rp.Path, rp.Type, rp.endpoint(), err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("remote config '%s' on %s %s: %w", rp.Path, rp.Type, rp.endpoint(), ctx.Err())
+	}
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCheckHealth(t *testing.T) {
+	t.Run("Local", func(t *testing.T) {
+		configPath, cleanup := setupTestConfig(t)
+		defer cleanup()
+		cfg := New(configPath, zap.NewNop())
+		assert.NoError(t, cfg.CheckHealth(context.Background()))
+	})
+
+	t.Run("Remote", func(t *testing.T) {
+		remote := useFakeRemote(t)
+		remote.set("app/config", []byte(`{"server": {"port": 8080}}`))
+		cfg := newRemoteTestConfig()
+		defer cfg.Close()
+		require.NoError(t, cfg.Load())
+		revision := cfg.RemoteRevision()
+		assert.NoError(t, cfg.CheckHealth(context.Background()))
+
+		// Checking neither applies nor records the new document.
+		remote.set("app/config", []byte(`{"server": {"port": 9090}}`))
+		assert.NoError(t, cfg.CheckHealth(context.Background()))
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+		assert.Equal(t, revision, cfg.RemoteRevision())
+		assert.Equal(t, uint64(1), cfg.Generation())
+
+		remote.remove("app/config")
+		err := cfg.CheckHealth(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "app/config")
+		assert.Contains(t, err.Error(), "key not found")
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+
+		require.NoError(t, cfg.Close())
+		assert.ErrorIs(t, cfg.CheckHealth(context.Background()), ErrClosed)
+	})
+
+	t.Run("Endpoint Credentials", func(t *testing.T) {
+		RegisterRemoteProvider("failing", RemoteBackendFunc(func(context.Context, *RemoteProvider) (io.Reader, error) {
+			return nil, io.ErrUnexpectedEOF
+		}))
+		t.Cleanup(func() {
+			remoteMu.Lock()
+			delete(remoteBackends, "failing")
+			remoteMu.Unlock()
+		})
+		rp := &RemoteProvider{Type: "failing", Endpoint: "redis://:hunter2@cache:6379/0", Path: "app/config"}
+		cfg := New("", zap.NewNop(), WithRemoteProvider(rp))
+		defer cfg.Close()
+
+		err := cfg.CheckHealth(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "redis://cache:6379/0")
+		assert.NotContains(t, err.Error(), "hunter2")
+		assert.Equal(t, "failing://redis://cache:6379/0/app/config", cfg.sourceName())
+		assert.Equal(t, "cache:6379", (&RemoteProvider{Endpoint: "admin:hunter2@cache:6379"}).endpoint())
+	})
+
+	t.Run("Unresponsive Backend", func(t *testing.T) {
+		release := make(chan struct{})
+		RegisterRemoteProvider("unresponsive", RemoteBackendFunc(func(context.Context, *RemoteProvider) (io.Reader, error) {
+			<-release
+			return nil, io.EOF
+		}))
+		t.Cleanup(func() {
+			remoteMu.Lock()
+			delete(remoteBackends, "unresponsive")
+			remoteMu.Unlock()
+		})
+		cfg := New("", zap.NewNop(), WithRemoteProvider(&RemoteProvider{Type: "unresponsive", Path: "app/config"}))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, cfg.CheckHealth(ctx), context.DeadlineExceeded)
+
+		// The abandoned fetch is supervised, so Close waits for it.
+		assert.Equal(t, 1, cfg.Stats().Tasks["health-check"])
+		close(release)
+		require.NoError(t, cfg.Close())
+		assert.Zero(t, cfg.Goroutines())
+	})
+
+	t.Run("Cancelled On Close", func(t *testing.T) {
+		RegisterRemoteProvider("blocking", RemoteBackendFunc(func(ctx context.Context, _ *RemoteProvider) (io.Reader, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}))
+		t.Cleanup(func() {
+			remoteMu.Lock()
+			delete(remoteBackends, "blocking")
+			remoteMu.Unlock()
+		})
+		cfg := New("", zap.NewNop(), WithRemoteProvider(&RemoteProvider{Type: "blocking", Path: "app/config"}))
+
+		errCh := make(chan error, 1)
+		go func() { errCh <- cfg.CheckHealth(context.Background()) }()
+		require.Eventually(t, func() bool { return cfg.Goroutines() == 1 }, time.Second, time.Millisecond)
+		require.NoError(t, cfg.Close())
+		assert.ErrorIs(t, <-errCh, context.Canceled)
+		assert.Zero(t, cfg.Goroutines())
+	})
+}
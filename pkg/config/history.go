@@ -0,0 +1,191 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// defaultHistorySize is the number of snapshots kept without WithHistory.
+const defaultHistorySize = 10
+
+// Snapshot is an effective configuration applied by a load.
+type Snapshot struct {
+	// Version is the Generation the snapshot was applied as.
+	Version uint64
+	Time    time.Time
+	// SourceHash is the hash of the raw source content the load read.
+	SourceHash  string
+	Annotations Annotations
+	// Settings is the configuration as Save writes it: env placeholders,
+	// key references, and secret references are kept unresolved, so the
+	// history never holds resolved secrets.
+	Settings map[string]interface{}
+}
+
+// sourceHasher is implemented by providers that hash their raw content.
+type sourceHasher interface {
+	sourceHash() string
+}
+
+// historyPin holds a rolled-back configuration in place of the source
+// content that was live when Rollback was called. settings are unresolved,
+// and resolved again by every load.
+type historyPin struct {
+	sourceHash string
+	settings   map[string]interface{}
+}
+
+// WithHistory keeps the last n effective configurations for History and
+// Rollback. The default is 10; zero or less disables history.
+func WithHistory(n int) Option {
+	return func(cm *ConfigManager) {
+		cm.historySize = n
+	}
+}
+
+// History returns the retained snapshots, newest first. History()[0] is the
+// live configuration and History()[n] is what Rollback(n) restores.
+func (cm *ConfigManager) History() []Snapshot {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	out := make([]Snapshot, len(cm.history))
+	for i, s := range cm.history {
+		s.Settings = deepCopyMap(s.Settings)
+		out[len(cm.history)-1-i] = s
+	}
+	return out
+}
+
+// Rollback restores the configuration applied n versions ago. Its env
+// placeholders, key references, and secrets are resolved again, as the
+// current environment and secret stores have them, and the result is
+// validated like any other load. The restored configuration stays live
+// until the source content changes, so a bad remote push can be reverted
+// in-process while the source is fixed; runtime changes still apply on top.
+// The rollback itself is recorded as a new version.
+func (cm *ConfigManager) Rollback(n int) error {
+	defer cm.flushLifecycle()
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	if cm.closed {
+		return ErrClosed
+	}
+	if n < 1 || n >= len(cm.history) {
+		return fmt.Errorf("cannot roll back %d versions, history holds %d", n, len(cm.history)-1)
+	}
+	target := cm.history[len(cm.history)-1-n]
+
+	prev := cm.pin
+	cm.pin = &historyPin{sourceHash: cm.sourceHash(), settings: target.Settings}
+	if c, ok := cm.provider.(committer); ok {
+		c.invalidate()
+	}
+	if err := cm.load(); err != nil {
+		cm.pin = prev
+		cm.logger.Error("Failed to roll back configuration",
+			zap.Uint64("version", target.Version),
+			zap.Error(err))
+		return err
+	}
+	cm.logger.Warn("Rolled back configuration",
+		zap.Uint64("version", target.Version),
+		zap.Time("appliedAt", target.Time))
+	return nil
+}
+
+// Generation returns the version of the live configuration. It starts at 1
+// with the first load and is incremented by every load that changes the
+// effective configuration, so callers can cheaply detect staleness or log
+// which version handled a request. It is 0 before the first load.
+func (cm *ConfigManager) Generation() uint64 {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.generation
+}
+
+// LastLoaded returns the time of the last successfully applied load.
+func (cm *ConfigManager) LastLoaded() time.Time {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.lastLoad
+}
+
+// SourceHash returns the hex SHA-256 of the raw source content behind the
+// live configuration, for health endpoints and logs. Environment variables
+// and runtime changes are not part of it.
+func (cm *ConfigManager) SourceHash() string {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.appliedHash
+}
+
+// recordGeneration advances the generation if the load just swapped in
+// changed the configuration, and records its source hash. The caller must
+// hold cm.mu.
+func (cm *ConfigManager) recordGeneration() {
+	if cm.generation == 0 || len(cm.changes) > 0 {
+		cm.generation++
+	}
+	cm.appliedHash = cm.sourceHash()
+}
+
+// sourceHash returns the hash of the source content the provider last
+// read. The caller must hold cm.mu.
+func (cm *ConfigManager) sourceHash() string {
+	if h, ok := cm.provider.(sourceHasher); ok {
+		return h.sourceHash()
+	}
+	return ""
+}
+
+// applyPin replaces v with the rolled-back configuration while the source
+// content is the one that was live at Rollback, and releases the pin once
+// it changes. Env placeholders of the pinned settings are interpolated as
+// the provider does for its sources; the returned templates record them.
+// The caller must hold cm.mu.
+func (cm *ConfigManager) applyPin(v *viper.Viper) (*viper.Viper, map[string]interpolation, error) {
+	if cm.pin == nil {
+		return v, nil, nil
+	}
+	if cm.sourceHash() != cm.pin.sourceHash {
+		cm.logger.Info("Source changed, releasing rolled-back configuration")
+		cm.pin = nil
+		return v, nil, nil
+	}
+	settings := deepCopyMap(cm.pin.settings)
+	templates := make(map[string]interpolation)
+	if !cm.noInterpolation {
+		if err := interpolateEnv(settings, cm.keyDelimiter, templates); err != nil {
+			return nil, nil, err
+		}
+	}
+	pinned := cm.newViper()
+	if err := pinned.MergeConfigMap(settings); err != nil {
+		return nil, nil, err
+	}
+	return pinned, templates, nil
+}
+
+// recordHistory appends the configuration just applied to the history ring.
+// Loads that changed nothing are not recorded, so polling an unchanged
+// source does not push older versions out. The caller must hold cm.mu.
+func (cm *ConfigManager) recordHistory() {
+	if cm.historySize <= 0 || (len(cm.history) > 0 && cm.history[len(cm.history)-1].Version == cm.generation) {
+		return
+	}
+	cm.history = append(cm.history, Snapshot{
+		Version:     cm.generation,
+		Time:        cm.lastLoad,
+		SourceHash:  cm.appliedHash,
+		Annotations: cm.annotations,
+		Settings:    cm.unresolved,
+	})
+	if over := len(cm.history) - cm.historySize; over > 0 {
+		cm.history = append(cm.history[:0:0], cm.history[over:]...)
+	}
+}
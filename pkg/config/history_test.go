@@ -0,0 +1,130 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHistory(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	writePort := func(port int) {
+		content := "server:\n  port: " + strconv.Itoa(port) + "\n  host: \"localhost\"\n"
+		require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+	}
+
+	logger, _ := zap.NewDevelopment()
+
+	t.Run("Rollback Holds Until Source Changes", func(t *testing.T) {
+		writePort(8080)
+		cfg := New(configPath, logger)
+		require.NoError(t, cfg.Load())
+		writePort(9999)
+		require.NoError(t, cfg.Load())
+
+		history := cfg.History()
+		require.Len(t, history, 2)
+		assert.Equal(t, 9999, history[0].Settings["server"].(map[string]interface{})["port"])
+		assert.NotEqual(t, history[0].SourceHash, history[1].SourceHash)
+
+		require.NoError(t, cfg.Rollback(1))
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+		assert.Len(t, cfg.History(), 3)
+
+		// Reloading the bad source keeps the rolled-back version.
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+
+		writePort(8081)
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 8081, cfg.GetInt("server.port"))
+	})
+
+	t.Run("Secrets Kept Unresolved", func(t *testing.T) {
+		t.Setenv("HISTORY_DB_PW", "hunter2")
+		t.Setenv("HISTORY_DB_USER", "app")
+		write := func(port int) {
+			content := "server:\n  port: " + strconv.Itoa(port) + "\n" +
+				"database:\n  password: env://HISTORY_DB_PW\n  user: ${HISTORY_DB_USER}\n"
+			require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+		}
+		write(8080)
+		cfg := New(configPath, logger, WithSecretResolver("env", EnvSecretResolver{}))
+		require.NoError(t, cfg.Load())
+		write(9999)
+		require.NoError(t, cfg.Load())
+
+		for _, snapshot := range cfg.History() {
+			database := snapshot.Settings["database"].(map[string]interface{})
+			assert.Equal(t, "env://HISTORY_DB_PW", database["password"])
+			assert.Equal(t, "${HISTORY_DB_USER}", database["user"])
+		}
+
+		// Rolling back resolves the snapshot against the current values.
+		t.Setenv("HISTORY_DB_PW", "rotated")
+		t.Setenv("HISTORY_DB_USER", "admin")
+		require.NoError(t, cfg.Rollback(1))
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+		assert.Equal(t, "rotated", cfg.GetString("database.password"))
+		assert.Equal(t, "admin", cfg.GetString("database.user"))
+		assert.Equal(t, "env://HISTORY_DB_PW", cfg.History()[0].Settings["database"].(map[string]interface{})["password"])
+	})
+
+	t.Run("Bounded", func(t *testing.T) {
+		cfg := New(configPath, logger, WithHistory(3))
+		for port := 1; port <= 5; port++ {
+			writePort(port)
+			require.NoError(t, cfg.Load())
+		}
+		// Unchanged reloads are not recorded.
+		require.NoError(t, cfg.Load())
+
+		history := cfg.History()
+		require.Len(t, history, 3)
+		assert.Equal(t, history[1].Version+1, history[0].Version)
+		assert.Equal(t, 5, history[0].Settings["server"].(map[string]interface{})["port"])
+		assert.Error(t, cfg.Rollback(3))
+	})
+}
+
+func TestGeneration(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	cfg := New(configPath, zap.NewNop(), WithSchema(&TestConfig{}))
+	assert.Zero(t, cfg.Generation())
+	assert.Empty(t, cfg.SourceHash())
+
+	require.NoError(t, cfg.Load())
+	assert.Equal(t, uint64(1), cfg.Generation())
+	hash := cfg.SourceHash()
+	assert.Len(t, hash, 64)
+	loaded := cfg.LastLoaded()
+	assert.False(t, loaded.IsZero())
+
+	// An unchanged reload keeps the generation.
+	require.NoError(t, cfg.Load())
+	assert.Equal(t, uint64(1), cfg.Generation())
+	assert.Equal(t, hash, cfg.SourceHash())
+
+	// A rejected reload keeps everything.
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(configPath, []byte("server:\n  port: -1\n"), 0644))
+	require.Error(t, cfg.Load())
+	assert.Equal(t, uint64(1), cfg.Generation())
+	assert.Equal(t, hash, cfg.SourceHash())
+
+	require.NoError(t, os.WriteFile(configPath, append(content, []byte("extra: true\n")...), 0644))
+	require.NoError(t, cfg.Load())
+	assert.Equal(t, uint64(2), cfg.Generation())
+	assert.NotEqual(t, hash, cfg.SourceHash())
+	assert.True(t, cfg.LastLoaded().After(loaded))
+	assert.Equal(t, uint64(2), cfg.Stats().Generation)
+}
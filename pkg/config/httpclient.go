@@ -0,0 +1,322 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// HTTPClientConfig is the schema of a config section describing an outbound
+// HTTP client.
+type HTTPClientConfig struct {
+	Timeout               time.Duration   `mapstructure:"timeout"`
+	DialTimeout           time.Duration   `mapstructure:"dial_timeout"`
+	TLSHandshakeTimeout   time.Duration   `mapstructure:"tls_handshake_timeout"`
+	ResponseHeaderTimeout time.Duration   `mapstructure:"response_header_timeout"`
+	IdleConnTimeout       time.Duration   `mapstructure:"idle_conn_timeout"`
+	MaxIdleConns          int             `mapstructure:"max_idle_conns" validate:"min=0"`
+	MaxIdleConnsPerHost   int             `mapstructure:"max_idle_conns_per_host" validate:"min=0"`
+	Proxy                 string          `mapstructure:"proxy" validate:"omitempty,url"`
+	TLS                   HTTPTLSConfig   `mapstructure:"tls"`
+	Retry                 HTTPRetryPolicy `mapstructure:"retry"`
+}
+
+// HTTPTLSConfig holds the TLS settings of an HTTP client section.
+type HTTPTLSConfig struct {
+	CAFile             string `mapstructure:"ca_file"`
+	CertFile           string `mapstructure:"cert_file" validate:"required_with=KeyFile"`
+	KeyFile            string `mapstructure:"key_file" validate:"required_with=CertFile"`
+	ServerName         string `mapstructure:"server_name"`
+	MinVersion         string `mapstructure:"min_version" validate:"omitempty,oneof=1.0 1.1 1.2 1.3"`
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"`
+}
+
+// HTTPRetryPolicy controls retries of idempotent requests on transport
+// errors and retryable status codes.
+type HTTPRetryPolicy struct {
+	MaxAttempts int           `mapstructure:"max_attempts" validate:"min=0"`
+	Backoff     time.Duration `mapstructure:"backoff"`
+	MaxBackoff  time.Duration `mapstructure:"max_backoff"`
+	StatusCodes []int         `mapstructure:"status_codes"`
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// HTTPClient returns an *http.Client configured from the HTTPClientConfig
+// section at key. The client's transport is rebuilt whenever a reload
+// changes the section or the contents of its TLS files, so in-flight
+// callers keep the same *http.Client while new requests pick up the new
+// settings; reloads leaving both unchanged keep the transport and its
+// pooled connections. If a reload produces an invalid section, the
+// previous transport stays in use. The section's TLS files are watched
+// along with the config file.
+func (cm *ConfigManager) HTTPClient(key string) (*http.Client, error) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	rt := &reloadingTransport{}
+	settings, err := cm.buildClientTransport(cm.viper, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	rt.current.Store(settings)
+
+	cm.reloadHooks = append(cm.reloadHooks, func(v *viper.Viper) {
+		current := rt.current.Load()
+		next, err := cm.buildClientTransport(v, key, current)
+		if err != nil {
+			cm.logger.Error("Failed to rebuild HTTP client, keeping previous settings",
+				zap.String("section", key),
+				zap.Error(err))
+			return
+		}
+		if next == current {
+			return
+		}
+		rt.current.Store(next)
+		current.base.CloseIdleConnections()
+	})
+	cm.closeHooks = append(cm.closeHooks, func() {
+		rt.current.Load().base.CloseIdleConnections()
+	})
+
+	return &http.Client{Transport: rt}, nil
+}
+
+// buildClientTransport decodes and validates the section at key and builds
+// a transport from it. It returns current instead when neither the section
+// nor its TLS files changed since current was built.
+func (cm *ConfigManager) buildClientTransport(v *viper.Viper, key string, current *clientTransport) (*clientTransport, error) {
+	var conf HTTPClientConfig
+	if err := v.UnmarshalKey(key, &conf, cm.decodeHook()); err != nil {
+		return nil, err
+	}
+	if err := cm.validateSchemaAt(&conf, key); err != nil {
+		return nil, err
+	}
+	// Watch the TLS files, so rotated certificates rebuild the transport.
+	cm.watchTargets.addFile(conf.TLS.CAFile)
+	cm.watchTargets.addFile(conf.TLS.CertFile)
+	cm.watchTargets.addFile(conf.TLS.KeyFile)
+
+	digest, err := tlsFilesDigest(conf.TLS)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil && current.digest == digest && reflect.DeepEqual(current.conf, conf) {
+		return current, nil
+	}
+	next, err := newClientTransport(conf)
+	if err != nil {
+		return nil, err
+	}
+	next.conf, next.digest = conf, digest
+	return next, nil
+}
+
+// tlsFilesDigest hashes the contents of the TLS files of conf, so rotated
+// certificates are noticed even when the section is unchanged.
+func tlsFilesDigest(conf HTTPTLSConfig) ([sha256.Size]byte, error) {
+	h := sha256.New()
+	for _, file := range []string{conf.CAFile, conf.CertFile, conf.KeyFile} {
+		if file == "" {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return [sha256.Size]byte{}, fmt.Errorf("error reading TLS file: %w", err)
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", file, len(data))
+		h.Write(data)
+	}
+	var digest [sha256.Size]byte
+	h.Sum(digest[:0])
+	return digest, nil
+}
+
+func newClientTransport(conf HTTPClientConfig) (*clientTransport, error) {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if conf.DialTimeout > 0 {
+		dialer.Timeout = conf.DialTimeout
+	}
+	base.DialContext = dialer.DialContext
+	if conf.TLSHandshakeTimeout > 0 {
+		base.TLSHandshakeTimeout = conf.TLSHandshakeTimeout
+	}
+	if conf.ResponseHeaderTimeout > 0 {
+		base.ResponseHeaderTimeout = conf.ResponseHeaderTimeout
+	}
+	if conf.IdleConnTimeout > 0 {
+		base.IdleConnTimeout = conf.IdleConnTimeout
+	}
+	if conf.MaxIdleConns > 0 {
+		base.MaxIdleConns = conf.MaxIdleConns
+	}
+	if conf.MaxIdleConnsPerHost > 0 {
+		base.MaxIdleConnsPerHost = conf.MaxIdleConnsPerHost
+	}
+
+	if conf.Proxy != "" {
+		proxyURL, err := url.Parse(conf.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		base.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	tlsConfig, err := newTLSConfig(conf.TLS)
+	if err != nil {
+		return nil, err
+	}
+	base.TLSClientConfig = tlsConfig
+
+	return &clientTransport{base: base, timeout: conf.Timeout, retry: conf.Retry}, nil
+}
+
+func newTLSConfig(conf HTTPTLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         conf.ServerName,
+		InsecureSkipVerify: conf.InsecureSkipVerify, //nolint:gosec // explicitly configured
+	}
+	if conf.MinVersion != "" {
+		tlsConfig.MinVersion = tlsVersions[conf.MinVersion]
+	}
+	if conf.CAFile != "" {
+		pem, err := os.ReadFile(conf.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", conf.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if conf.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(conf.CertFile, conf.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// reloadingTransport delegates to the transport built from the latest valid
+// section.
+type reloadingTransport struct {
+	current atomic.Pointer[clientTransport]
+}
+
+func (t *reloadingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.current.Load().RoundTrip(req)
+}
+
+// clientTransport applies the configured timeout and retry policy on top of
+// an *http.Transport.
+type clientTransport struct {
+	base    *http.Transport
+	timeout time.Duration
+	retry   HTTPRetryPolicy
+	// conf and digest are the section and TLS file digest the transport
+	// was built from.
+	conf   HTTPClientConfig
+	digest [sha256.Size]byte
+}
+
+func (t *clientTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	cancel := context.CancelFunc(func() {})
+	if t.timeout > 0 {
+		var ctx context.Context
+		ctx, cancel = context.WithTimeout(req.Context(), t.timeout)
+		req = req.WithContext(ctx)
+	}
+
+	resp, err := t.roundTripWithRetry(req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// Keep the deadline active until the caller is done reading the body.
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+func (t *clientTransport) roundTripWithRetry(req *http.Request) (*http.Response, error) {
+	attempts := t.retry.MaxAttempts
+	if attempts < 1 || !isRetryable(req) {
+		attempts = 1
+	}
+
+	backoff := t.retry.Backoff
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		retry := err != nil || slices.Contains(t.retry.StatusCodes, resp.StatusCode)
+		if !retry || attempt >= attempts {
+			return resp, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if t.retry.MaxBackoff > 0 && backoff > t.retry.MaxBackoff {
+			backoff = t.retry.MaxBackoff
+		}
+	}
+}
+
+// isRetryable reports whether req can safely be sent more than once.
+func isRetryable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	}
+	return false
+}
+
+// cancelOnClose releases a request context once the response body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
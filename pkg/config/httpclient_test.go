@@ -0,0 +1,149 @@
+package config
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHTTPClient(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		case "/flaky":
+			if calls.Add(1)%2 == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig := func(content string) {
+		require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+	}
+	writeConfig(`
+clients:
+  backend:
+    timeout: 50ms
+`)
+
+	logger, _ := zap.NewDevelopment()
+	cfg := New(configPath, logger)
+	require.NoError(t, cfg.Load())
+
+	client, err := cfg.HTTPClient("clients.backend")
+	require.NoError(t, err)
+
+	t.Run("Timeout Applied", func(t *testing.T) {
+		_, err := client.Get(server.URL + "/slow")
+		assert.Error(t, err)
+	})
+
+	t.Run("Reload Swaps Settings", func(t *testing.T) {
+		writeConfig(`
+clients:
+  backend:
+    timeout: 2s
+    retry:
+      max_attempts: 2
+      backoff: 10ms
+      status_codes: [503]
+`)
+		require.NoError(t, cfg.Load())
+
+		resp, err := client.Get(server.URL + "/slow")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp, err = client.Get(server.URL + "/flaky")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("Unrelated Reload Keeps Transport", func(t *testing.T) {
+		rt := client.Transport.(*reloadingTransport)
+		before := rt.current.Load()
+		writeConfig(`
+clients:
+  backend:
+    timeout: 2s
+    retry:
+      max_attempts: 2
+      backoff: 10ms
+      status_codes: [503]
+server:
+  port: 9090
+`)
+		require.NoError(t, cfg.Load())
+		assert.Same(t, before, rt.current.Load())
+	})
+
+	t.Run("Rotated CA File Rebuilds Transport", func(t *testing.T) {
+		caFile := filepath.Join(t.TempDir(), "ca.pem")
+		tlsServer := httptest.NewTLSServer(http.NotFoundHandler())
+		defer tlsServer.Close()
+		ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tlsServer.Certificate().Raw})
+		require.NoError(t, os.WriteFile(caFile, ca, 0644))
+		writeConfig(`
+clients:
+  backend:
+    timeout: 2s
+    tls:
+      ca_file: ` + caFile + `
+`)
+		require.NoError(t, cfg.Load())
+		rt := client.Transport.(*reloadingTransport)
+		before := rt.current.Load()
+
+		require.NoError(t, cfg.Load())
+		assert.Same(t, before, rt.current.Load())
+
+		// A rotated bundle with the same path and section.
+		require.NoError(t, os.WriteFile(caFile, append(ca, ca...), 0644))
+		require.NoError(t, cfg.Load())
+		assert.NotSame(t, before, rt.current.Load())
+	})
+
+	t.Run("Invalid Section Keeps Previous Transport", func(t *testing.T) {
+		writeConfig(`
+clients:
+  backend:
+    timeout: 2s
+    tls:
+      ca_file: /nonexistent/ca.pem
+`)
+		require.NoError(t, cfg.Load())
+
+		resp, err := client.Get(server.URL + "/slow")
+		require.NoError(t, err)
+		resp.Body.Close()
+	})
+
+	t.Run("Invalid Initial Section", func(t *testing.T) {
+		writeConfig(`
+clients:
+  broken:
+    proxy: "not a url"
+`)
+		require.NoError(t, cfg.Load())
+		_, err := cfg.HTTPClient("clients.broken")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/es"
+	"github.com/go-playground/locales/fr"
+	"github.com/go-playground/locales/it"
+	"github.com/go-playground/locales/ja"
+	"github.com/go-playground/locales/nl"
+	"github.com/go-playground/locales/pt"
+	"github.com/go-playground/locales/pt_BR"
+	"github.com/go-playground/locales/ru"
+	"github.com/go-playground/locales/tr"
+	"github.com/go-playground/locales/zh"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	entrans "github.com/go-playground/validator/v10/translations/en"
+	estrans "github.com/go-playground/validator/v10/translations/es"
+	frtrans "github.com/go-playground/validator/v10/translations/fr"
+	ittrans "github.com/go-playground/validator/v10/translations/it"
+	jatrans "github.com/go-playground/validator/v10/translations/ja"
+	nltrans "github.com/go-playground/validator/v10/translations/nl"
+	pttrans "github.com/go-playground/validator/v10/translations/pt"
+	ptbrtrans "github.com/go-playground/validator/v10/translations/pt_BR"
+	rutrans "github.com/go-playground/validator/v10/translations/ru"
+	trtrans "github.com/go-playground/validator/v10/translations/tr"
+	zhtrans "github.com/go-playground/validator/v10/translations/zh"
+	"go.uber.org/zap"
+)
+
+// MessageCatalog maps validation tags to message templates for one locale.
+// Templates may reference {0} for the field name and {1} for the tag's
+// parameter, e.g. "{0} muss mindestens {1} sein".
+type MessageCatalog map[string]string
+
+// builtinLocale pairs a locale with the validator's bundled translations.
+type builtinLocale struct {
+	locale   func() locales.Translator
+	register func(*validator.Validate, ut.Translator) error
+}
+
+var builtinLocales = map[string]builtinLocale{
+	"en":    {en.New, entrans.RegisterDefaultTranslations},
+	"es":    {es.New, estrans.RegisterDefaultTranslations},
+	"fr":    {fr.New, frtrans.RegisterDefaultTranslations},
+	"it":    {it.New, ittrans.RegisterDefaultTranslations},
+	"ja":    {ja.New, jatrans.RegisterDefaultTranslations},
+	"nl":    {nl.New, nltrans.RegisterDefaultTranslations},
+	"pt":    {pt.New, pttrans.RegisterDefaultTranslations},
+	"pt_br": {pt_BR.New, ptbrtrans.RegisterDefaultTranslations},
+	"ru":    {ru.New, rutrans.RegisterDefaultTranslations},
+	"tr":    {tr.New, trtrans.RegisterDefaultTranslations},
+	"zh":    {zh.New, zhtrans.RegisterDefaultTranslations},
+}
+
+// messageTranslator renders validation failures for the selected locale,
+// preferring the custom catalog over the validator's bundled translations.
+type messageTranslator struct {
+	catalog MessageCatalog
+	builtin ut.Translator
+}
+
+// normalizeLocale maps "pt-BR" and "pt_BR" onto the same key.
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(locale, "-", "_"))
+}
+
+// newMessageTranslator builds the translator for locale. It returns nil when
+// no locale is selected, in which case failures are reported by tag.
+func newMessageTranslator(v *validator.Validate, locale string, catalogs map[string]MessageCatalog, logger logSink) *messageTranslator {
+	if locale == "" {
+		return nil
+	}
+	locale = normalizeLocale(locale)
+	t := &messageTranslator{catalog: catalogs[locale]}
+
+	if b, ok := builtinLocales[locale]; ok && v != nil {
+		loc := b.locale()
+		trans, _ := ut.New(loc, loc).GetTranslator(loc.Locale())
+		if err := b.register(v, trans); err != nil {
+			logger.Error("Failed to register validation translations",
+				zap.String("locale", locale),
+				zap.Error(err))
+		} else {
+			t.builtin = trans
+		}
+	}
+
+	if t.builtin == nil && t.catalog == nil {
+		logger.Warn("No validation messages available for locale, falling back to tags",
+			zap.String("locale", locale))
+		return nil
+	}
+	return t
+}
+
+// translate returns the localized message for a failed field, or the
+// failed tag when no message is available.
+func (t *messageTranslator) translate(fe validator.FieldError) string {
+	if t == nil {
+		return fe.Tag()
+	}
+	if tmpl, ok := t.catalog[fe.Tag()]; ok {
+		return strings.NewReplacer("{0}", fe.Field(), "{1}", fe.Param()).Replace(tmpl)
+	}
+	if t.builtin != nil {
+		if msg := fe.Translate(t.builtin); msg != fe.Error() {
+			return msg
+		}
+	}
+	return fe.Tag()
+}
+
+// WithValidationLocale selects the language of validation messages, e.g.
+// "fr" or "pt-BR". Locales without bundled translations need a catalog
+// supplied through WithValidationMessages.
+func WithValidationLocale(locale string) Option {
+	return func(cm *ConfigManager) {
+		cm.validationLocale = locale
+	}
+}
+
+// WithValidationMessages adds a message catalog for locale. Catalog entries
+// take precedence over bundled translations for the same tag.
+func WithValidationMessages(locale string, catalog MessageCatalog) Option {
+	return func(cm *ConfigManager) {
+		locale = normalizeLocale(locale)
+		merged := make(MessageCatalog, len(cm.catalogs[locale])+len(catalog))
+		for tag, msg := range cm.catalogs[locale] {
+			merged[tag] = msg
+		}
+		for tag, msg := range catalog {
+			merged[tag] = msg
+		}
+		cm.catalogs[locale] = merged
+	}
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type localeTestConfig struct {
+	Server struct {
+		Port int    `mapstructure:"port" validate:"min=1024"`
+		Host string `mapstructure:"host" validate:"required"`
+	} `mapstructure:"server"`
+}
+
+func TestValidationLocale(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("server:\n  port: 80\n  host: localhost\n"), 0644))
+	logger, _ := zap.NewDevelopment()
+
+	load := func(opts ...Option) error {
+		opts = append(opts, WithSchema(&localeTestConfig{}))
+		return New(configPath, logger, opts...).Load()
+	}
+
+	t.Run("Default Reports Tag", func(t *testing.T) {
+		err := load()
+		require.Error(t, err)
+		assert.Equal(t, "validation failed for field 'server.port': min", err.Error())
+	})
+
+	t.Run("Bundled Translation", func(t *testing.T) {
+		err := load(WithValidationLocale("en"))
+		require.Error(t, err)
+		assert.Equal(t, "validation failed for field 'server.port': Port must be 1,024 or greater", err.Error())
+	})
+
+	t.Run("Custom Catalog", func(t *testing.T) {
+		err := load(
+			WithValidationLocale("de"),
+			WithValidationMessages("de", MessageCatalog{"min": "{0} muss mindestens {1} sein"}),
+		)
+		require.Error(t, err)
+		assert.Equal(t, "validation failed for field 'server.port': Port muss mindestens 1024 sein", err.Error())
+	})
+
+	t.Run("Catalog Overrides Bundled Translation", func(t *testing.T) {
+		err := load(
+			WithValidationLocale("fr"),
+			WithValidationMessages("fr", MessageCatalog{"min": "{0} trop petit"}),
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Port trop petit")
+	})
+
+	t.Run("Unsupported Locale Falls Back To Tag", func(t *testing.T) {
+		err := load(WithValidationLocale("xx"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), ": min")
+	})
+}
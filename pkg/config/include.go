@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cast"
+	"go.uber.org/zap"
+)
+
+// IncludeKey is the key listing the files a config file includes, e.g.
+// include: [database.yaml, secrets/*.yaml]. Paths are relative to the
+// including file and may be glob patterns; a pattern matching no file is
+// skipped, while a missing plain path is an error. Included files are
+// merged in order, and the including file's own values override theirs.
+// Includes nest, a cycle is an error, and every included file is watched.
+const IncludeKey = "include"
+
+// decodeIncludes merges the files listed under IncludeKey in settings, the
+// decoded content of the file at path, once its conditions are applied, and
+// returns the result. Each file is recorded in trace after the files it
+// includes. stack holds the files
+// being included, outermost first, to detect cycles.
+func decodeIncludes(logger logSink, path string, settings map[string]interface{}, delimiter string, jsonnet *JsonnetOptions,
+	funcs template.FuncMap, profile string, targets *watchRegistry, trace *fileTrace, stack []string) (map[string]interface{}, error) {
+	settings, err := applyConditions(path, settings, profile)
+	if err != nil {
+		return nil, err
+	}
+	var raw interface{}
+	for k, v := range settings {
+		if strings.EqualFold(k, IncludeKey) {
+			raw = v
+			delete(settings, k)
+		}
+	}
+	if raw == nil {
+		trace.add(path, settings)
+		return settings, nil
+	}
+	patterns, err := cast.ToStringSliceE(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s in %s: %w", IncludeKey, path, err)
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	stack = append(stack, abs)
+	merged := make(map[string]interface{})
+	for _, pattern := range patterns {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(filepath.Dir(path), pattern)
+		}
+		files := []string{pattern}
+		if strings.ContainsAny(pattern, "*?[") {
+			if files, err = filepath.Glob(pattern); err != nil {
+				return nil, fmt.Errorf("invalid %s pattern %q in %s: %w", IncludeKey, pattern, path, err)
+			}
+			// Watch the directory, so files created later are included.
+			targets.addDir(filepath.Dir(pattern))
+		}
+
+		for _, file := range files {
+			included, err := decodeIncludedFile(logger, file, delimiter, jsonnet, funcs, profile, targets, trace, stack)
+			if err != nil {
+				return nil, err
+			}
+			mergeSettings(merged, lowerKeys(included))
+		}
+	}
+	trace.add(path, settings)
+	mergeSettings(merged, lowerKeys(settings))
+	return merged, nil
+}
+
+// decodeIncludedFile reads and decodes an included file, with its own
+// includes resolved.
+func decodeIncludedFile(logger logSink, file, delimiter string, jsonnet *JsonnetOptions, funcs template.FuncMap,
+	profile string, targets *watchRegistry, trace *fileTrace, stack []string) (map[string]interface{}, error) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+	for i, p := range stack {
+		if p == abs {
+			return nil, fmt.Errorf("include cycle: %s", strings.Join(append(stack[i:], abs), " -> "))
+		}
+	}
+
+	targets.addFile(file)
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading included file: %w", err)
+	}
+	settings, err := decodeFile(logger, file, data, "", delimiter, jsonnet, funcs, targets)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding included file %s: %w", file, err)
+	}
+	logger.Debug("Included config file", zap.String("path", file))
+	return decodeIncludes(logger, file, settings, delimiter, jsonnet, funcs, profile, targets, trace, stack)
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestInclude(t *testing.T) {
+	write := func(t *testing.T, path, content string) {
+		t.Helper()
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	t.Run("Merged Relative To File", func(t *testing.T) {
+		dir := t.TempDir()
+		configPath := filepath.Join(dir, "config.yaml")
+		write(t, configPath, "include: [database.yaml, secrets/*.yaml]\nserver:\n  port: 8080\ndatabase:\n  port: 6432\n")
+		write(t, filepath.Join(dir, "database.yaml"), "include: [pool.json]\ndatabase:\n  host: db.internal\n  port: 5432\n")
+		write(t, filepath.Join(dir, "pool.json"), `{"database": {"maxConns": 25}}`)
+		write(t, filepath.Join(dir, "secrets", "db.yaml"), "database:\n  password: s3cr3t\n")
+		write(t, filepath.Join(dir, "secrets", "api.yaml"), "api:\n  token: abc\n")
+
+		cfg := New(configPath, zap.NewNop())
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+		assert.Equal(t, "db.internal", cfg.GetString("database.host"))
+		assert.Equal(t, 6432, cfg.GetInt("database.port"), "the including file wins")
+		assert.Equal(t, 25, cfg.GetInt("database.maxconns"))
+		assert.Equal(t, "s3cr3t", cfg.GetString("database.password"))
+		assert.Equal(t, "abc", cfg.GetString("api.token"))
+		assert.False(t, cfg.IsSet(IncludeKey))
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		dir := t.TempDir()
+		configPath := filepath.Join(dir, "config.yaml")
+		write(t, configPath, "include: [a.yaml]\n")
+		write(t, filepath.Join(dir, "a.yaml"), "include: [b.yaml]\n")
+		write(t, filepath.Join(dir, "b.yaml"), "include: [a.yaml]\n")
+
+		err := New(configPath, zap.NewNop()).Load()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "include cycle")
+		assert.Contains(t, err.Error(), filepath.Join(dir, "a.yaml")+" -> "+filepath.Join(dir, "b.yaml"))
+	})
+
+	t.Run("Missing File", func(t *testing.T) {
+		dir := t.TempDir()
+		configPath := filepath.Join(dir, "config.yaml")
+		write(t, configPath, "include: [missing.yaml, optional/*.yaml]\n")
+
+		err := New(configPath, zap.NewNop()).Load()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing.yaml")
+	})
+
+	t.Run("Watched", func(t *testing.T) {
+		dir := t.TempDir()
+		configPath := filepath.Join(dir, "config.yaml")
+		included := filepath.Join(dir, "database.yaml")
+		write(t, configPath, "include: [database.yaml]\n")
+		write(t, included, "database:\n  port: 5432\n")
+
+		cfg := New(configPath, zap.NewNop(), WithHighFrequencyReload())
+		defer cfg.Close()
+		require.NoError(t, cfg.Load())
+
+		changes := make(chan struct{}, 10)
+		require.NoError(t, cfg.Watch(context.Background(), func() { changes <- struct{}{} }))
+		require.NoError(t, writeFileAtomic(included, []byte("database:\n  port: 6432\n")))
+		select {
+		case <-changes:
+		case <-time.After(2 * time.Second):
+			t.Fatal("timeout waiting for config change")
+		}
+		assert.Equal(t, 6432, cfg.GetInt("database.port"))
+	})
+}
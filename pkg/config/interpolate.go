@@ -0,0 +1,116 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// WithoutEnvInterpolation leaves ${NAME} placeholders in config file and
+// remote document values as they are.
+func WithoutEnvInterpolation() Option {
+	return func(cm *ConfigManager) {
+		cm.noInterpolation = true
+	}
+}
+
+// MissingEnvError is returned by a load whose documents reference
+// environment variables that are not set and have no default.
+type MissingEnvError struct {
+	// Missing maps each undefined variable to the keys referencing it.
+	Missing map[string][]string
+}
+
+func (e *MissingEnvError) Error() string {
+	names := make([]string, 0, len(e.Missing))
+	for name := range e.Missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s (%s)", name, strings.Join(e.Missing[name], ", "))
+	}
+	return "undefined environment variables: " + strings.Join(parts, ", ")
+}
+
+// envPlaceholder matches $${...} escapes, ${NAME}, and ${NAME:-default}.
+// Names are environment variable names; placeholders whose name contains
+// other characters, such as a key delimiter, are left alone.
+var envPlaceholder = regexp.MustCompile(`\$\$\{[^}]*\}|\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// interpolateEnv replaces the environment placeholders in the string values
+// of settings in place. Values of unset variables come from the default
+// after ":-", which also applies to empty ones; a placeholder without a
+// default for an unset variable is reported in a MissingEnvError. "$${"
+// escapes a literal "${". If templates is non-nil, the original of every
+// value that changed is recorded in it, so Save can write it back.
+func interpolateEnv(settings map[string]interface{}, delim string, templates map[string]interpolation) error {
+	missing := make(map[string][]string)
+	for key, value := range settings {
+		settings[key] = interpolateValue(value, key, delim, missing, templates)
+	}
+	if len(missing) > 0 {
+		for _, keys := range missing {
+			sort.Strings(keys)
+		}
+		return &MissingEnvError{Missing: missing}
+	}
+	return nil
+}
+
+// interpolation is a value that env interpolation changed.
+type interpolation struct {
+	// template is the value as written, value the interpolated result.
+	template interface{}
+	value    interface{}
+}
+
+// interpolateValue interpolates value, found at key, recording changed
+// values in templates unless it is nil. A list is recorded as a whole.
+func interpolateValue(value interface{}, key, delim string, missing map[string][]string, templates map[string]interpolation) interface{} {
+	switch v := value.(type) {
+	case string:
+		result := envPlaceholder.ReplaceAllStringFunc(v, func(match string) string {
+			if strings.HasPrefix(match, "$$") {
+				// Escaped key references are unescaped once resolved.
+				if isReference(match[3:len(match)-1], delim) {
+					return match
+				}
+				return match[1:]
+			}
+			m := envPlaceholder.FindStringSubmatch(match)
+			name, def, hasDefault := m[1], m[2], strings.Contains(match, ":-")
+			if env, ok := os.LookupEnv(name); ok && (env != "" || !hasDefault) {
+				return env
+			}
+			if !hasDefault {
+				missing[name] = append(missing[name], key)
+			}
+			return def
+		})
+		if templates != nil && result != v {
+			templates[strings.ToLower(key)] = interpolation{template: v, value: result}
+		}
+		return result
+	case map[string]interface{}:
+		for k, elem := range v {
+			v[k] = interpolateValue(elem, key+delim+k, delim, missing, templates)
+		}
+	case []interface{}:
+		var original interface{}
+		if templates != nil {
+			original = deepCopyValue(v)
+		}
+		for i, elem := range v {
+			v[i] = interpolateValue(elem, fmt.Sprintf("%s[%d]", key, i), delim, missing, nil)
+		}
+		if templates != nil && !reflect.DeepEqual(original, value) {
+			templates[strings.ToLower(key)] = interpolation{template: original, value: deepCopyValue(v)}
+		}
+	}
+	return value
+}
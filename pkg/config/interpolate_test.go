@@ -0,0 +1,100 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func writeInterpolateConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+// unsetenv unsets the variables for the duration of the test.
+func unsetenv(t *testing.T, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+}
+
+func TestEnvInterpolation(t *testing.T) {
+	content := `
+server:
+  host: ${HOST:-localhost}
+  url: http://${HOST:-localhost}:${PORT:-8080}/api
+database:
+  password: ${DATABASE_PASSWORD}
+  hosts:
+    - ${DB_PRIMARY:-db1}
+    - db2
+literal: $${NOT_A_VAR}
+`
+
+	t.Run("Resolves Placeholders", func(t *testing.T) {
+		t.Setenv("DATABASE_PASSWORD", "s3cret")
+		t.Setenv("HOST", "example.com")
+		cfg := New(writeInterpolateConfig(t, content), zap.NewNop())
+		require.NoError(t, cfg.Load())
+
+		assert.Equal(t, "example.com", cfg.GetString("server.host"))
+		assert.Equal(t, "http://example.com:8080/api", cfg.GetString("server.url"))
+		assert.Equal(t, "s3cret", cfg.GetString("database.password"))
+		assert.Equal(t, []string{"db1", "db2"}, cfg.GetStringSlice("database.hosts"))
+		assert.Equal(t, "${NOT_A_VAR}", cfg.GetString("literal"))
+	})
+
+	t.Run("Default For Empty Variable", func(t *testing.T) {
+		t.Setenv("DATABASE_PASSWORD", "s3cret")
+		t.Setenv("HOST", "")
+		cfg := New(writeInterpolateConfig(t, content), zap.NewNop())
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, "localhost", cfg.GetString("server.host"))
+	})
+
+	t.Run("Missing Variables", func(t *testing.T) {
+		path := writeInterpolateConfig(t, `
+database:
+  user: ${DB_USER}
+  password: ${DB_PASSWORD}
+backup:
+  password: ${DB_PASSWORD}
+`)
+		unsetenv(t, "DB_USER", "DB_PASSWORD")
+		cfg := New(path, zap.NewNop())
+		err := cfg.Load()
+
+		var missing *MissingEnvError
+		require.ErrorAs(t, err, &missing)
+		assert.Equal(t, map[string][]string{
+			"DB_PASSWORD": {"backup.password", "database.password"},
+			"DB_USER":     {"database.user"},
+		}, missing.Missing)
+		assert.Contains(t, err.Error(), "DB_PASSWORD (backup.password, database.password), DB_USER (database.user)")
+	})
+
+	t.Run("Opt Out", func(t *testing.T) {
+		unsetenv(t, "DATABASE_PASSWORD")
+		cfg := New(writeInterpolateConfig(t, content), zap.NewNop(), WithoutEnvInterpolation())
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, "${DATABASE_PASSWORD}", cfg.GetString("database.password"))
+		assert.Equal(t, "$${NOT_A_VAR}", cfg.GetString("literal"))
+	})
+
+	t.Run("Remote Document", func(t *testing.T) {
+		remote := useFakeRemote(t)
+		remote.set("app/config", []byte(`{"server": {"token": "${API_TOKEN:-none}"}}`))
+		t.Setenv("API_TOKEN", "abc")
+		cfg := newRemoteTestConfig()
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, "abc", cfg.GetString("server.token"))
+	})
+}
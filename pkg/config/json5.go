@@ -0,0 +1,184 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+// json5Exts lists the extensions treated as JSON5.
+var json5Exts = map[string]bool{
+	"json5": true,
+}
+
+// stripJSON5 converts a JSON5 document into strict JSON. Besides the
+// comments and trailing commas of JSONC, it quotes identifier keys,
+// rewrites single-quoted strings and their escapes, joins line
+// continuations, and converts hexadecimal numbers, explicit plus signs, and
+// leading or trailing decimal points. Infinity and NaN have no JSON form
+// and are rejected. Line structure is preserved, so decoder errors keep
+// their line numbers.
+func stripJSON5(data []byte) ([]byte, error) {
+	out := make([]byte, 0, len(data)+len(data)/8)
+	line := 1
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c == '"' || c == '\'':
+			var err error
+			if out, i, err = appendJSON5String(out, data, i); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				out = append(out, '\n')
+				line++
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			for i += 2; i < len(data); i++ {
+				if data[i] == '*' && i+1 < len(data) && data[i+1] == '/' {
+					i++
+					break
+				}
+				if data[i] == '\n' {
+					out = append(out, '\n')
+					line++
+				}
+			}
+		case c == ']' || c == '}':
+			out = trimTrailingComma(out)
+			out = append(out, c)
+		case isJSON5IdentStart(c):
+			start := i
+			for i+1 < len(data) && isJSON5IdentPart(data[i+1]) {
+				i++
+			}
+			switch word := string(data[start : i+1]); word {
+			case "true", "false", "null":
+				out = append(out, word...)
+			case "Infinity", "NaN":
+				return nil, fmt.Errorf("line %d: %s cannot be represented in JSON", line, word)
+			default:
+				out = strconv.AppendQuote(out, word)
+			}
+		case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+			var err error
+			if out, i, err = appendJSON5Number(out, data, i); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		default:
+			if c == '\n' {
+				line++
+			}
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+// appendJSON5String appends the string literal starting at data[i] as a
+// double-quoted JSON string and returns the index of its closing quote.
+func appendJSON5String(out, data []byte, i int) ([]byte, int, error) {
+	quote := data[i]
+	out = append(out, '"')
+	for i++; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c == quote:
+			return append(out, '"'), i, nil
+		case c == '"':
+			out = append(out, '\\', '"')
+		case c == '\\' && i+1 < len(data):
+			i++
+			switch e := data[i]; e {
+			case '\n':
+				// A line continuation.
+			case '\r':
+				if i+1 < len(data) && data[i+1] == '\n' {
+					i++
+				}
+			case '"', '\\', '/', 'b', 'f', 'n', 'r', 't', 'u':
+				out = append(out, '\\', e)
+			case '\'':
+				out = append(out, '\'')
+			case 'v':
+				out = append(out, `\u000b`...)
+			case '0':
+				out = append(out, `\u0000`...)
+			case 'x':
+				if i+2 >= len(data) {
+					return nil, 0, fmt.Errorf("invalid \\x escape")
+				}
+				out = append(out, `\u00`...)
+				out = append(out, data[i+1:i+3]...)
+				i += 2
+			default:
+				// Any other character escapes to itself.
+				out = append(out, e)
+			}
+		default:
+			out = append(out, c)
+		}
+	}
+	return nil, 0, fmt.Errorf("unterminated string")
+}
+
+// appendJSON5Number appends the number starting at data[i] in JSON form and
+// returns the index of its last byte.
+func appendJSON5Number(out, data []byte, i int) ([]byte, int, error) {
+	neg := false
+	for ; i < len(data) && (data[i] == '+' || data[i] == '-'); i++ {
+		if data[i] == '-' {
+			neg = !neg
+		}
+	}
+	start := i
+	for i < len(data) && (isJSON5IdentPart(data[i]) || data[i] == '.' ||
+		((data[i] == '+' || data[i] == '-') && (data[i-1] == 'e' || data[i-1] == 'E') && !isHexPrefix(data[start:]))) {
+		i++
+	}
+	literal := data[start:i]
+	if neg {
+		out = append(out, '-')
+	}
+
+	switch {
+	case isHexPrefix(literal):
+		n, err := strconv.ParseUint(string(literal[2:]), 16, 64)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid hexadecimal number %q", literal)
+		}
+		out = strconv.AppendUint(out, n, 10)
+	case string(literal) == "Infinity" || string(literal) == "NaN":
+		return nil, 0, fmt.Errorf("%s cannot be represented in JSON", literal)
+	default:
+		mantissa, exponent := literal, []byte(nil)
+		if e := bytes.IndexAny(literal, "eE"); e >= 0 {
+			mantissa, exponent = literal[:e], literal[e:]
+		}
+		if bytes.HasPrefix(mantissa, []byte(".")) {
+			out = append(out, '0')
+		}
+		out = append(out, mantissa...)
+		if bytes.HasSuffix(mantissa, []byte(".")) {
+			out = append(out, '0')
+		}
+		out = append(out, exponent...)
+	}
+	return out, i - 1, nil
+}
+
+func isHexPrefix(b []byte) bool {
+	return len(b) > 1 && b[0] == '0' && (b[1] == 'x' || b[1] == 'X')
+}
+
+func isJSON5IdentStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == '$' || c >= 0x80
+}
+
+func isJSON5IdentPart(c byte) bool {
+	return isJSON5IdentStart(c) || c >= '0' && c <= '9'
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestStripJSON5(t *testing.T) {
+	t.Run("Converted To JSON", func(t *testing.T) {
+		in := []byte(`{
+  // line comment
+  unquoted: 'single "quoted"', /* block */
+  $id_2: 0x1F,
+  neg: -0xff,
+  plus: +1,
+  lead: .5,
+  trail: 5.,
+  exp: 1.e-3,
+  esc: 'it\'s\x41\
+ continued',
+  list: [1, 2, ],
+  "quoted": true,
+}`)
+		want := `{
+  
+  "unquoted": "single \"quoted\"", 
+  "$id_2": 31,
+  "neg": -255,
+  "plus": 1,
+  "lead": 0.5,
+  "trail": 5.0,
+  "exp": 1.0e-3,
+  "esc": "it's\u0041 continued",
+  "list": [1, 2 ],
+  "quoted": true
+}`
+		out, err := stripJSON5(in)
+		require.NoError(t, err)
+		assert.Equal(t, want, string(out))
+	})
+
+	t.Run("Strings Are Preserved", func(t *testing.T) {
+		in := []byte(`{"url": 'http://example.com/*x*/', "q": "a '//' b,}"}`)
+		out, err := stripJSON5(in)
+		require.NoError(t, err)
+		assert.Equal(t, `{"url": "http://example.com/*x*/", "q": "a '//' b,}"}`, string(out))
+	})
+
+	t.Run("Non-Finite Numbers", func(t *testing.T) {
+		for _, in := range []string{`{a: Infinity}`, `{a: -Infinity}`, `{a: NaN}`} {
+			_, err := stripJSON5([]byte(in))
+			assert.ErrorContains(t, err, "cannot be represented in JSON", in)
+		}
+	})
+
+	t.Run("Unterminated String", func(t *testing.T) {
+		_, err := stripJSON5([]byte("{\n  a: 'open}"))
+		assert.EqualError(t, err, "line 2: unterminated string")
+	})
+}
+
+func TestJSON5ConfigFile(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json5")
+	content := []byte(`{
+  // Server settings maintained by hand.
+  server: {
+    port: 0x1F90,
+    host: 'localhost',
+  },
+}`)
+	require.NoError(t, os.WriteFile(configPath, content, 0644))
+
+	cfg := New(configPath, zap.NewNop())
+	require.NoError(t, cfg.Load())
+
+	assert.Equal(t, 8080, cfg.GetInt("server.port"))
+	assert.Equal(t, "localhost", cfg.GetString("server.host"))
+}
@@ -0,0 +1,73 @@
+package config
+
+// jsoncExts lists the extensions treated as JSON with comments.
+var jsoncExts = map[string]bool{
+	"jsonc": true,
+}
+
+// stripJSONC converts a JSONC document into strict JSON by removing line
+// comments, block comments, and trailing commas before a closing bracket or
+// brace. String literals are left untouched.
+func stripJSONC(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c == '"':
+			// Copy the string literal verbatim, honoring escapes.
+			start := i
+			for i++; i < len(data); i++ {
+				if data[i] == '\\' {
+					i++
+					continue
+				}
+				if data[i] == '"' {
+					break
+				}
+			}
+			if i >= len(data) {
+				i = len(data) - 1
+			}
+			out = append(out, data[start:i+1]...)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				out = append(out, '\n')
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			for i += 2; i < len(data); i++ {
+				if data[i] == '*' && i+1 < len(data) && data[i+1] == '/' {
+					i++
+					break
+				}
+				// Preserve line structure so decoder errors keep their line numbers.
+				if data[i] == '\n' {
+					out = append(out, '\n')
+				}
+			}
+		case c == ']' || c == '}':
+			out = trimTrailingComma(out)
+			out = append(out, c)
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// trimTrailingComma removes a comma that is followed only by whitespace at the
+// end of buf.
+func trimTrailingComma(buf []byte) []byte {
+	for j := len(buf) - 1; j >= 0; j-- {
+		switch buf[j] {
+		case ' ', '\t', '\n', '\r':
+			continue
+		case ',':
+			return append(buf[:j], buf[j+1:]...)
+		}
+		break
+	}
+	return buf
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestStripJSONC(t *testing.T) {
+	t.Run("Comments And Trailing Commas", func(t *testing.T) {
+		in := []byte(`{
+  // line comment
+  "a": 1, /* block
+  comment */
+  "b": [1, 2, ],
+}`)
+		want := "{\n  \n  \"a\": 1, \n\n  \"b\": [1, 2 ]\n}"
+		assert.Equal(t, want, string(stripJSONC(in)))
+	})
+
+	t.Run("Strings Are Preserved", func(t *testing.T) {
+		in := []byte(`{"url": "http://example.com/*x*/", "q": "a \"//\" b,}"}`)
+		assert.Equal(t, string(in), string(stripJSONC(in)))
+	})
+}
+
+func TestJSONCConfigFile(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.jsonc")
+	content := []byte(`{
+  // Server settings maintained by hand.
+  "server": {
+    "port": 8080,
+    "host": "localhost", /* trailing comma below */
+  },
+}`)
+	require.NoError(t, os.WriteFile(configPath, content, 0644))
+
+	logger, _ := zap.NewDevelopment()
+	cfg := New(configPath, logger)
+	require.NoError(t, cfg.Load())
+
+	assert.Equal(t, 8080, cfg.GetInt("server.port"))
+	assert.Equal(t, "localhost", cfg.GetString("server.host"))
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/google/go-jsonnet"
+)
+
+// JsonnetOptions configures evaluation of .jsonnet config files.
+type JsonnetOptions struct {
+	// ImportPaths are searched, after the file's own directory, by import statements.
+	ImportPaths []string
+	// ExtVars are exposed to the document as std.extVar string values.
+	ExtVars map[string]string
+	// ExtCode are exposed to the document as std.extVar Jsonnet expressions.
+	ExtCode map[string]string
+}
+
+// evaluateJsonnet evaluates a Jsonnet document to JSON and returns the
+// paths of the files it imported.
+func evaluateJsonnet(filename string, data []byte, opts *JsonnetOptions) ([]byte, []string, error) {
+	vm := jsonnet.MakeVM()
+	importPaths := []string{filepath.Dir(filename)}
+	if opts != nil {
+		importPaths = append(importPaths, opts.ImportPaths...)
+		for k, val := range opts.ExtVars {
+			vm.ExtVar(k, val)
+		}
+		for k, code := range opts.ExtCode {
+			vm.ExtCode(k, code)
+		}
+	}
+	importer := &recordingImporter{FileImporter: jsonnet.FileImporter{JPaths: importPaths}}
+	vm.Importer(importer)
+
+	out, err := vm.EvaluateAnonymousSnippet(filename, string(data))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error evaluating Jsonnet: %w", err)
+	}
+	return []byte(out), importer.found, nil
+}
+
+// recordingImporter is a file importer that records where each import was found.
+type recordingImporter struct {
+	jsonnet.FileImporter
+	found []string
+}
+
+func (i *recordingImporter) Import(importedFrom, importedPath string) (jsonnet.Contents, string, error) {
+	contents, foundAt, err := i.FileImporter.Import(importedFrom, importedPath)
+	if err == nil {
+		i.found = append(i.found, foundAt)
+	}
+	return contents, foundAt, err
+}
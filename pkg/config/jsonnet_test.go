@@ -0,0 +1,52 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestJsonnetConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	libDir := filepath.Join(dir, "lib")
+	require.NoError(t, os.Mkdir(libDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(libDir, "base.libsonnet"), []byte(`{
+  server: { host: "localhost", port: 8080 },
+}`), 0644))
+
+	configPath := filepath.Join(dir, "config.jsonnet")
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+local base = import 'base.libsonnet';
+base {
+  server+: { host: std.extVar('cluster') + ".internal" },
+  database: { maxConns: std.extVar('replicas') * 5 },
+}`), 0644))
+
+	logger, _ := zap.NewDevelopment()
+
+	t.Run("Imports And External Variables", func(t *testing.T) {
+		cfg := New(configPath, logger, WithJsonnet(&JsonnetOptions{
+			ImportPaths: []string{libDir},
+			ExtVars:     map[string]string{"cluster": "eu1"},
+			ExtCode:     map[string]string{"replicas": "2"},
+		}))
+		require.NoError(t, cfg.Load())
+
+		assert.Equal(t, "eu1.internal", cfg.GetString("server.host"))
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+		assert.Equal(t, 10, cfg.GetInt("database.maxConns"))
+	})
+
+	t.Run("Missing External Variable", func(t *testing.T) {
+		cfg := New(configPath, logger, WithJsonnet(&JsonnetOptions{
+			ImportPaths: []string{libDir},
+		}))
+		err := cfg.Load()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "error evaluating Jsonnet")
+	})
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrKeychainNotFound is returned by KeychainSecretResolver when the OS
+// keychain has no item for a reference.
+var ErrKeychainNotFound = errors.New("keychain item not found")
+
+// KeychainSecretResolver resolves references to passwords stored in the OS
+// keychain: the login Keychain on macOS, the Secret Service (GNOME Keyring,
+// KWallet) on Linux and BSD, and the Credential Manager on Windows. It lets
+// developers keep local credentials out of .env files while the config keys
+// stay those of production, e.g. with a development profile setting
+//
+//	database:
+//	  password: keychain://myapp/db-password
+//
+// A reference is "service/account". When Service is set, the whole
+// reference is the account. Items are looked up the way common keyring
+// tools store them: generic passwords on macOS, items with service and
+// username attributes in the Secret Service, and generic credentials named
+// "service:account" on Windows.
+type KeychainSecretResolver struct {
+	Service string
+}
+
+// keychainLookup reads the password of an item from the OS keychain.
+var keychainLookup = readKeychain
+
+// Resolve reads the password of the keychain item ref names.
+func (r KeychainSecretResolver) Resolve(ctx context.Context, ref string) (string, error) {
+	service, account := r.Service, ref
+	if service == "" {
+		var ok bool
+		if service, account, ok = strings.Cut(ref, "/"); !ok || service == "" {
+			return "", fmt.Errorf("keychain reference %q is not of the form service/account", ref)
+		}
+	}
+	if account == "" {
+		return "", fmt.Errorf("keychain reference %q has no account", ref)
+	}
+	secret, err := keychainLookup(ctx, service, account)
+	if err != nil {
+		return "", fmt.Errorf("keychain item %s/%s: %w", service, account, err)
+	}
+	return secret, nil
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// securityNotFound is the exit status of security(1) for a missing item.
+const securityNotFound = 44
+
+// readKeychain reads a generic password from the login Keychain with
+// security(1).
+func readKeychain(ctx context.Context, service, account string) (string, error) {
+	out, err := exec.CommandContext(ctx, "security", "find-generic-password",
+		"-s", service, "-a", account, "-w").Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			if exitErr.ExitCode() == securityNotFound {
+				return "", ErrKeychainNotFound
+			}
+			return "", fmt.Errorf("security: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
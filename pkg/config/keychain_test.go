@@ -0,0 +1,70 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// useFakeKeychain replaces the OS keychain with items, keyed by
+// "service/account", for the duration of the test.
+func useFakeKeychain(t *testing.T, items map[string]string) {
+	orig := keychainLookup
+	keychainLookup = func(_ context.Context, service, account string) (string, error) {
+		if secret, ok := items[service+"/"+account]; ok {
+			return secret, nil
+		}
+		return "", ErrKeychainNotFound
+	}
+	t.Cleanup(func() { keychainLookup = orig })
+}
+
+func TestKeychainSecretResolver(t *testing.T) {
+	useFakeKeychain(t, map[string]string{
+		"myapp/db-password": "local-pass",
+		"other/token":       "tok",
+	})
+
+	t.Run("Resolves Service And Account", func(t *testing.T) {
+		cfg := New(writeInterpolateConfig(t, `
+database:
+  password: keychain://myapp/db-password
+`), zap.NewNop(), WithSecretResolver("keychain", KeychainSecretResolver{}))
+		require.NoError(t, cfg.Load())
+
+		assert.Equal(t, "local-pass", cfg.GetString("database.password"))
+		assert.Equal(t, RedactedValue, cfg.AllSettingsRedacted()["database"].(map[string]interface{})["password"])
+	})
+
+	t.Run("Default Service", func(t *testing.T) {
+		secret, err := KeychainSecretResolver{Service: "myapp"}.Resolve(context.Background(), "db-password")
+		require.NoError(t, err)
+		assert.Equal(t, "local-pass", secret)
+	})
+
+	t.Run("Missing Item", func(t *testing.T) {
+		cfg := New(writeInterpolateConfig(t, `
+api:
+  token: keychain://myapp/token
+`), zap.NewNop(), WithSecretResolver("keychain", KeychainSecretResolver{}))
+		err := cfg.Load()
+		require.Error(t, err)
+
+		var secretErr *SecretError
+		require.True(t, errors.As(err, &secretErr))
+		assert.Equal(t, "api.token", secretErr.Key)
+		assert.ErrorIs(t, err, ErrKeychainNotFound)
+	})
+
+	t.Run("Invalid Reference", func(t *testing.T) {
+		_, err := KeychainSecretResolver{}.Resolve(context.Background(), "db-password")
+		assert.ErrorContains(t, err, "service/account")
+
+		_, err = KeychainSecretResolver{}.Resolve(context.Background(), "myapp/")
+		assert.ErrorContains(t, err, "no account")
+	})
+}
@@ -0,0 +1,29 @@
+//go:build !darwin && !windows
+
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// readKeychain looks up an item in the Secret Service with secret-tool(1),
+// which exits with status 1 and no output when there is none.
+func readKeychain(ctx context.Context, service, account string) (string, error) {
+	out, err := exec.CommandContext(ctx, "secret-tool", "lookup",
+		"service", service, "username", account).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			if stderr := strings.TrimSpace(string(exitErr.Stderr)); stderr != "" {
+				return "", fmt.Errorf("secret-tool: %s", stderr)
+			}
+			return "", ErrKeychainNotFound
+		}
+		return "", err
+	}
+	return string(out), nil
+}
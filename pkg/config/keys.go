@@ -0,0 +1,39 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// DefaultKeyDelimiter separates the segments of nested keys unless
+// WithKeyDelimiter sets another one.
+const DefaultKeyDelimiter = "."
+
+// WithKeyDelimiter sets the separator of nested keys, e.g. "/" so map keys
+// that contain dots, such as hostnames, can be addressed: with it,
+// GetString("upstreams/api.example.com/timeout") reads the timeout of the
+// "api.example.com" entry. It applies to every key the manager accepts or
+// reports, including defaults, secret patterns, sections, and change events.
+// Environment variables are still named with underscores.
+//
+// Keys are always case-insensitive: viper lowercases every key it stores
+// and has no option to preserve case.
+func WithKeyDelimiter(delim string) Option {
+	return func(cm *ConfigManager) {
+		if delim != "" {
+			cm.keyDelimiter = delim
+		}
+	}
+}
+
+// newViper returns an empty viper instance using the manager's key
+// delimiter.
+func (cm *ConfigManager) newViper() *viper.Viper {
+	return viper.NewWithOptions(viper.KeyDelimiter(cm.keyDelimiter))
+}
+
+// splitKey splits key into its path segments.
+func (cm *ConfigManager) splitKey(key string) []string {
+	return strings.Split(key, cm.keyDelimiter)
+}
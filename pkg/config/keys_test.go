@@ -0,0 +1,66 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestKeyDelimiter(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := `
+upstreams:
+  api.example.com:
+    timeout: 5s
+    token: s3cr3t
+  static.example.com:
+    timeout: 1s
+`
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+
+	type Upstream struct {
+		Timeout time.Duration `mapstructure:"timeout" validate:"max=2s"`
+		Token   string        `mapstructure:"token" secret:"true"`
+	}
+
+	cfg := New(configPath, zap.NewNop(),
+		WithKeyDelimiter("/"),
+		WithDefaults(map[string]interface{}{"upstreams/api.example.com/retries": 3}),
+		WithSecretKeys("upstreams/*/token"))
+	require.NoError(t, cfg.Load())
+
+	t.Run("Dotted Segments", func(t *testing.T) {
+		assert.Equal(t, 5*time.Second, cfg.GetDuration("upstreams/api.example.com/timeout"))
+		assert.Equal(t, 3, cfg.GetInt("upstreams/api.example.com/retries"))
+		assert.Contains(t, cfg.AllKeys(), "upstreams/static.example.com/timeout")
+		assert.Equal(t, time.Second, cfg.Sub("upstreams/static.example.com").GetDuration("timeout"))
+		assert.Equal(t, []string{"upstreams/api.example.com/token"}, cfg.ScopedView("upstreams/api.example.com/token").AllKeys())
+	})
+
+	t.Run("Redaction", func(t *testing.T) {
+		settings := cfg.AllSettingsRedacted()
+		upstream := settings["upstreams"].(map[string]interface{})["api.example.com"].(map[string]interface{})
+		assert.Equal(t, RedactedValue, upstream["token"])
+	})
+
+	t.Run("Runtime Changes", func(t *testing.T) {
+		require.NoError(t, cfg.Set("upstreams/static.example.com/timeout", "2s"))
+		assert.Equal(t, 2*time.Second, cfg.GetDuration("upstreams/static.example.com/timeout"))
+		require.NoError(t, cfg.Unset("upstreams/static.example.com"))
+		assert.Equal(t, time.Second, cfg.GetDuration("upstreams/static.example.com/timeout"))
+	})
+
+	t.Run("Validation Keys", func(t *testing.T) {
+		cfg := New(configPath, zap.NewNop(), WithKeyDelimiter("/"))
+		cfg.RegisterSection("upstreams/api.example.com", &Upstream{})
+		err := cfg.Load()
+		var verrs ValidationErrors
+		require.ErrorAs(t, err, &verrs)
+		assert.Equal(t, "upstreams/api.example.com/timeout", verrs[0].Key)
+	})
+}
@@ -0,0 +1,173 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SecretLease is a secret issued for a limited time, such as Vault database
+// credentials or a PKI certificate.
+type SecretLease struct {
+	Value string
+	// ID identifies the lease to the store, e.g. a Vault lease ID.
+	ID string
+	// TTL is how long the secret stays valid from the time it was issued
+	// or renewed. A zero TTL never expires.
+	TTL       time.Duration
+	Renewable bool
+}
+
+// LeasedSecretResolver is implemented by resolvers of dynamic secrets. The
+// manager keeps the lease of each reference across reloads instead of
+// resolving it again, renews it in the background once two thirds of its
+// TTL have passed, and, when it cannot be renewed or renewing no longer
+// extends it, resolves the reference again before it expires and reloads,
+// so the new secret reaches Subscribe and OnSecretRotated callbacks.
+type LeasedSecretResolver interface {
+	SecretResolver
+	ResolveLease(ctx context.Context, ref string) (SecretLease, error)
+	// RenewLease extends lease. A renewed lease with an empty Value keeps
+	// the secret of lease.
+	RenewLease(ctx context.Context, lease SecretLease) (SecretLease, error)
+}
+
+// leaseRetryDelay is how long a reload that failed to replace an expiring
+// lease waits before trying again.
+var leaseRetryDelay = 10 * time.Second
+
+// secretLease is the live lease of a secret reference.
+type secretLease struct {
+	r       LeasedSecretResolver
+	lease   SecretLease
+	expires time.Time
+	timer   *time.Timer
+}
+
+// leasedSecret resolves a new lease for the reference s, with ref the part
+// after the scheme, and returns its secret. The caller must hold cm.mu for
+// writing.
+func (cm *ConfigManager) leasedSecret(ctx context.Context, s, ref string, r LeasedSecretResolver) (string, error) {
+	lease, err := r.ResolveLease(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	if cm.leases == nil {
+		cm.leases = make(map[string]*secretLease)
+		cm.closeHooks = append(cm.closeHooks, cm.stopLeaseTimers)
+	}
+	l := &secretLease{r: r}
+	cm.leases[s] = l
+	cm.scheduleLease(s, l, lease)
+	return lease.Value, nil
+}
+
+// scheduleLease records lease as the current lease of l and schedules its
+// renewal. The caller must hold cm.mu.
+func (cm *ConfigManager) scheduleLease(s string, l *secretLease, lease SecretLease) {
+	l.lease = lease
+	if lease.TTL <= 0 {
+		l.expires = time.Time{}
+		return
+	}
+	l.expires = time.Now().Add(lease.TTL)
+	l.timer = cm.sup.AfterFunc("lease-renewal", lease.TTL*2/3, func(ctx context.Context) { cm.renewLease(ctx, s, l) })
+}
+
+// renewLease renews the lease l of the reference s, or replaces it if it
+// cannot be renewed. ctx is cancelled by Close.
+func (cm *ConfigManager) renewLease(ctx context.Context, s string, l *secretLease) {
+	cm.mu.RLock()
+	current, closed, lease, expires := cm.leases[s], cm.closed, l.lease, l.expires
+	cm.mu.RUnlock()
+	if current != l || closed {
+		return
+	}
+
+	if lease.Renewable {
+		ctx, cancel := context.WithTimeout(ctx, secretTimeout)
+		renewed, err := l.r.RenewLease(ctx, lease)
+		cancel()
+		if err == nil && time.Now().Add(renewed.TTL).After(expires) {
+			if renewed.Value == "" {
+				renewed.Value = lease.Value
+			}
+			cm.mu.Lock()
+			if cm.leases[s] == l && !cm.closed {
+				cm.scheduleLease(s, l, renewed)
+			}
+			cm.mu.Unlock()
+			cm.logger.Debug("Renewed secret lease",
+				zap.String("lease", lease.ID),
+				zap.Duration("ttl", renewed.TTL))
+			return
+		}
+		if err != nil {
+			cm.logger.Warn("Failed to renew secret lease",
+				zap.String("lease", lease.ID),
+				zap.Error(err))
+		}
+	}
+	cm.replaceLease(s, l)
+}
+
+// replaceLease drops the lease l of the reference s and reloads, which
+// resolves s again. A reload that fails is retried after leaseRetryDelay
+// while the old lease lasts.
+func (cm *ConfigManager) replaceLease(s string, l *secretLease) {
+	cm.mu.Lock()
+	if cm.leases[s] == l {
+		delete(cm.leases, s)
+	}
+	// The sources may be unchanged, but the secret must be resolved again.
+	if c, ok := cm.provider.(committer); ok {
+		c.invalidate()
+	}
+	id, expires := l.lease.ID, l.expires
+	cm.mu.Unlock()
+
+	event := cm.loadEvent()
+	if event.Err != nil {
+		cm.logger.Error("Failed to replace expiring secret",
+			zap.String("lease", id),
+			zap.Error(event.Err))
+		cm.reportReloadError(event.Err)
+		if !errors.Is(event.Err, ErrClosed) && !errors.Is(event.Err, ErrFrozen) && time.Until(expires) > leaseRetryDelay {
+			cm.mu.Lock()
+			// Keep the old lease, which the live configuration still
+			// uses, unless the failed reload leased a new one.
+			if _, ok := cm.leases[s]; !ok && !cm.closed {
+				cm.leases[s] = l
+				l.timer = cm.sup.AfterFunc("lease-renewal", leaseRetryDelay, func(context.Context) { cm.replaceLease(s, l) })
+			}
+			cm.mu.Unlock()
+		}
+	}
+	if event.Err != nil || len(event.Changes) > 0 {
+		cm.publish(event)
+	}
+}
+
+// pruneLeases stops renewing the leases of references the live
+// configuration no longer uses. The caller must hold cm.mu.
+func (cm *ConfigManager) pruneLeases(used map[string]bool) {
+	for s, l := range cm.leases {
+		if !used[s] {
+			if l.timer != nil {
+				l.timer.Stop()
+			}
+			delete(cm.leases, s)
+		}
+	}
+}
+
+// stopLeaseTimers stops the pending renewals. The caller must hold cm.mu.
+func (cm *ConfigManager) stopLeaseTimers() {
+	for _, l := range cm.leases {
+		if l.timer != nil {
+			l.timer.Stop()
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeLeases issues numbered credentials as leases.
+type fakeLeases struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	renewable bool
+	failRenew bool
+	issued    int
+	renewals  int
+}
+
+func (f *fakeLeases) Resolve(ctx context.Context, ref string) (string, error) {
+	lease, err := f.ResolveLease(ctx, ref)
+	return lease.Value, err
+}
+
+func (f *fakeLeases) ResolveLease(_ context.Context, ref string) (SecretLease, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.issued++
+	return SecretLease{
+		Value:     fmt.Sprintf("%s-%d", ref, f.issued),
+		ID:        fmt.Sprintf("lease-%d", f.issued),
+		TTL:       f.ttl,
+		Renewable: f.renewable,
+	}, nil
+}
+
+func (f *fakeLeases) RenewLease(_ context.Context, lease SecretLease) (SecretLease, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.renewals++
+	if f.failRenew {
+		return SecretLease{}, errors.New("lease not found")
+	}
+	lease.Value = ""
+	lease.TTL = f.ttl
+	return lease, nil
+}
+
+func (f *fakeLeases) counts() (issued, renewals int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.issued, f.renewals
+}
+
+func TestSecretLeases(t *testing.T) {
+	content := "database:\n  password: vault://database/creds/app\n"
+
+	t.Run("Reused Across Reloads", func(t *testing.T) {
+		leases := &fakeLeases{ttl: time.Hour, renewable: true}
+		path := writeInterpolateConfig(t, content)
+		cfg := New(path, zap.NewNop(), WithSecretResolver("vault", leases))
+		defer cfg.Close()
+		require.NoError(t, cfg.Load())
+		require.NoError(t, cfg.Load())
+		require.NoError(t, cfg.DryRun(path))
+
+		assert.Equal(t, "database/creds/app-1", cfg.GetString("database.password"))
+		issued, _ := leases.counts()
+		assert.Equal(t, 1, issued)
+	})
+
+	t.Run("Renewed", func(t *testing.T) {
+		leases := &fakeLeases{ttl: 60 * time.Millisecond, renewable: true}
+		cfg := New(writeInterpolateConfig(t, content), zap.NewNop(), WithSecretResolver("vault", leases))
+		defer cfg.Close()
+		require.NoError(t, cfg.Load())
+
+		assert.Eventually(t, func() bool {
+			_, renewals := leases.counts()
+			return renewals >= 2
+		}, 2*time.Second, 10*time.Millisecond)
+		issued, _ := leases.counts()
+		assert.Equal(t, 1, issued)
+		assert.Equal(t, "database/creds/app-1", cfg.GetString("database.password"))
+	})
+
+	for _, tc := range []struct {
+		name   string
+		leases *fakeLeases
+	}{
+		{"Not Renewable", &fakeLeases{ttl: 60 * time.Millisecond}},
+		{"Renewal Failed", &fakeLeases{ttl: 60 * time.Millisecond, renewable: true, failRenew: true}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := New(writeInterpolateConfig(t, content), zap.NewNop(), WithSecretResolver("vault", tc.leases))
+			defer cfg.Close()
+			require.NoError(t, cfg.Load())
+
+			rotated := make(chan string, 10)
+			cfg.OnSecretRotated("database.password", func() {
+				rotated <- cfg.GetString("database.password")
+			})
+			select {
+			case value := <-rotated:
+				assert.Equal(t, "database/creds/app-2", value)
+			case <-time.After(2 * time.Second):
+				t.Fatal("secret was not replaced")
+			}
+		})
+	}
+
+	t.Run("Released", func(t *testing.T) {
+		leases := &fakeLeases{ttl: time.Hour, renewable: true}
+		path := writeInterpolateConfig(t, content)
+		cfg := New(path, zap.NewNop(), WithSecretResolver("vault", leases))
+		defer cfg.Close()
+		require.NoError(t, cfg.Load())
+		require.Len(t, cfg.leases, 1)
+
+		require.NoError(t, writeFileAtomic(path, []byte("database:\n  password: static\n")))
+		require.NoError(t, cfg.Load())
+		assert.Empty(t, cfg.leases)
+	})
+
+	t.Run("Close During Renewal", func(t *testing.T) {
+		leases := &blockingLeases{
+			fakeLeases: fakeLeases{ttl: 30 * time.Millisecond, renewable: true},
+			renewing:   make(chan struct{}),
+			cancelled:  make(chan struct{}),
+		}
+		cfg := New(writeInterpolateConfig(t, content), zap.NewNop(), WithSecretResolver("vault", leases))
+		require.NoError(t, cfg.Load())
+
+		select {
+		case <-leases.renewing:
+		case <-time.After(2 * time.Second):
+			t.Fatal("lease was not renewed")
+		}
+		assert.Equal(t, 1, cfg.Stats().Tasks["lease-renewal"])
+		require.NoError(t, cfg.Close())
+		// Close cancelled the renewal and waited for it.
+		select {
+		case <-leases.cancelled:
+		default:
+			t.Fatal("Close returned before the renewal")
+		}
+		assert.Zero(t, cfg.Goroutines())
+	})
+}
+
+// blockingLeases blocks renewals until their context is cancelled.
+type blockingLeases struct {
+	fakeLeases
+	renewing  chan struct{}
+	cancelled chan struct{}
+}
+
+func (b *blockingLeases) RenewLease(ctx context.Context, _ SecretLease) (SecretLease, error) {
+	close(b.renewing)
+	<-ctx.Done()
+	close(b.cancelled)
+	return SecretLease{}, ctx.Err()
+}
@@ -0,0 +1,207 @@
+package config
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// LifecycleKind is the kind of a LifecycleEvent.
+type LifecycleKind int
+
+const (
+	// EventLoaded is emitted by the first load that applies a configuration.
+	EventLoaded LifecycleKind = iota + 1
+	// EventReloaded is emitted by every later applied load, whether it
+	// was triggered by Load, a watcher, or a runtime change.
+	EventReloaded
+	// EventReloadFailed is emitted by a load that was rejected or only
+	// partially applied, including a failed first load.
+	EventReloadFailed
+	// EventWatchStarted is emitted when a watcher starts.
+	EventWatchStarted
+	// EventClosed is emitted once Close has stopped the manager.
+	EventClosed
+)
+
+func (k LifecycleKind) String() string {
+	switch k {
+	case EventLoaded:
+		return "loaded"
+	case EventReloaded:
+		return "reloaded"
+	case EventReloadFailed:
+		return "reload_failed"
+	case EventWatchStarted:
+		return "watch_started"
+	case EventClosed:
+		return "closed"
+	default:
+		return "unknown"
+	}
+}
+
+// LifecycleEvent describes a step in the life of a manager.
+type LifecycleEvent struct {
+	Kind LifecycleKind
+	Time time.Time
+	// Source names the file or remote document, as in ChangeEvent.
+	Source string
+	// Generation is the generation live after the event.
+	Generation uint64
+	// Changes lists the changes applied by a load.
+	Changes []Change
+	// Err is the error of a failed load.
+	Err error
+	// Mode is the watch mode of EventWatchStarted, "file" or "remote-poll".
+	Mode string
+}
+
+// lifecycleBus holds the callbacks registered through OnLifecycle and the
+// events waiting to be delivered to them.
+type lifecycleBus struct {
+	mu     sync.Mutex
+	nextID uint64
+	subs   map[uint64]func(LifecycleEvent)
+	queue  []LifecycleEvent
+	// delivering is held by the goroutine delivering the queue.
+	delivering sync.Mutex
+}
+
+// OnLifecycle registers onEvent to receive a LifecycleEvent for every load,
+// failed load, watcher start, and Close, and returns a function that
+// removes it. Unlike Subscribe, it does not start a watcher, and it also
+// sees loads triggered by Load. Events are delivered in order, after the
+// manager's lock is released, so onEvent may call the getters. A callback
+// that panics is logged and does not affect the others.
+func (cm *ConfigManager) OnLifecycle(onEvent func(LifecycleEvent)) (unsubscribe func()) {
+	b := &cm.lifecycle
+	b.mu.Lock()
+	if b.subs == nil {
+		b.subs = make(map[uint64]func(LifecycleEvent))
+	}
+	b.nextID++
+	id := b.nextID
+	b.subs[id] = onEvent
+	b.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, id)
+			b.mu.Unlock()
+		})
+	}
+}
+
+// emit queues event for delivery by flushLifecycle. The caller may hold
+// cm.mu.
+func (cm *ConfigManager) emit(event LifecycleEvent) {
+	b := &cm.lifecycle
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.subs) == 0 {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	b.queue = append(b.queue, event)
+}
+
+// emitLoad queues the event of a load that failed with err. first reports
+// whether no configuration had been applied before. The caller must hold
+// cm.mu.
+func (cm *ConfigManager) emitLoad(first bool, err error) {
+	event := LifecycleEvent{
+		Kind:       EventReloaded,
+		Source:     cm.sourceName(),
+		Generation: cm.generation,
+		Err:        err,
+	}
+	var partial *PartialApplyError
+	if err == nil || errors.As(err, &partial) {
+		event.Changes = append([]Change(nil), cm.changes...)
+	}
+	switch {
+	case err != nil:
+		event.Kind = EventReloadFailed
+	case first:
+		event.Kind = EventLoaded
+	}
+	cm.emit(event)
+}
+
+// watchStarted emits EventWatchStarted unless starting the watcher failed
+// with err, and returns err.
+func (cm *ConfigManager) watchStarted(err error) error {
+	if err == nil {
+		cm.emit(LifecycleEvent{
+			Kind:       EventWatchStarted,
+			Source:     cm.sourceName(),
+			Generation: cm.Generation(),
+			Mode:       cm.WatchStatus().Mode,
+		})
+		cm.flushLifecycle()
+	}
+	return err
+}
+
+// flushLifecycle delivers the queued events. It must be called without
+// cm.mu held; a call made while another goroutine, or a callback further
+// up the stack, is delivering leaves the events to it.
+func (cm *ConfigManager) flushLifecycle() {
+	b := &cm.lifecycle
+	for b.delivering.TryLock() {
+		for {
+			b.mu.Lock()
+			if len(b.queue) == 0 {
+				b.mu.Unlock()
+				break
+			}
+			event := b.queue[0]
+			b.queue = b.queue[1:]
+			ids := make([]uint64, 0, len(b.subs))
+			for id := range b.subs {
+				ids = append(ids, id)
+			}
+			sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+			subs := make([]func(LifecycleEvent), len(ids))
+			for i, id := range ids {
+				subs[i] = b.subs[id]
+			}
+			b.mu.Unlock()
+
+			for i, fn := range subs {
+				cm.notifyLifecycle(ids[i], fn, event)
+			}
+		}
+		b.delivering.Unlock()
+
+		// Deliver events queued by a goroutine that found the lock held.
+		b.mu.Lock()
+		empty := len(b.queue) == 0
+		b.mu.Unlock()
+		if empty {
+			return
+		}
+	}
+}
+
+// notifyLifecycle calls fn, recovering from a panic.
+func (cm *ConfigManager) notifyLifecycle(id uint64, fn func(LifecycleEvent), event LifecycleEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			cm.logger.Error("Lifecycle subscriber panicked",
+				zap.Uint64("subscriber", id),
+				zap.Stringer("event", event.Kind),
+				zap.Any("panic", r),
+				zap.Stack("stack"))
+		}
+	}()
+	fn(event)
+}
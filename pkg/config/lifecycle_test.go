@@ -0,0 +1,102 @@
+package config
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// lifecycleRecorder collects the events of a manager.
+type lifecycleRecorder struct {
+	mu     sync.Mutex
+	events []LifecycleEvent
+}
+
+func (r *lifecycleRecorder) record(e LifecycleEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+}
+
+func (r *lifecycleRecorder) kinds() []LifecycleKind {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kinds := make([]LifecycleKind, len(r.events))
+	for i, e := range r.events {
+		kinds[i] = e.Kind
+	}
+	return kinds
+}
+
+func (r *lifecycleRecorder) last() LifecycleEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.events[len(r.events)-1]
+}
+
+func TestLifecycleEvents(t *testing.T) {
+	t.Run("Load And Runtime Changes", func(t *testing.T) {
+		configPath, cleanup := setupTestConfig(t)
+		defer cleanup()
+		var schema TestConfig
+		cfg := New(configPath, zap.NewNop(), WithSchema(&schema))
+		rec := &lifecycleRecorder{}
+		cfg.OnLifecycle(rec.record)
+		cfg.OnLifecycle(func(LifecycleEvent) { panic("subscriber bug") })
+		// Callbacks run without the lock held, so they may read the config.
+		var port int
+		cfg.OnLifecycle(func(LifecycleEvent) { port = cfg.GetInt("server.port") })
+
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, []LifecycleKind{EventLoaded}, rec.kinds())
+		assert.Equal(t, uint64(1), rec.last().Generation)
+		assert.Equal(t, configPath, rec.last().Source)
+		assert.Equal(t, 8080, port)
+
+		require.NoError(t, cfg.Set("server.port", 9090))
+		assert.Equal(t, []LifecycleKind{EventLoaded, EventReloaded}, rec.kinds())
+		assert.Equal(t, []string{"server.port"}, ChangeEvent{Changes: rec.last().Changes}.Keys())
+		assert.Equal(t, 9090, port)
+
+		require.Error(t, cfg.Set("server.port", 0))
+		assert.Equal(t, EventReloadFailed, rec.last().Kind)
+		assert.Error(t, rec.last().Err)
+		assert.Empty(t, rec.last().Changes)
+
+		require.NoError(t, cfg.Close())
+		assert.Equal(t, EventClosed, rec.last().Kind)
+		assert.Equal(t, "closed", rec.last().Kind.String())
+	})
+
+	t.Run("Watcher", func(t *testing.T) {
+		configPath, cleanup := setupTestConfig(t)
+		defer cleanup()
+		cfg := New(configPath, zap.NewNop(), WithWatcher())
+		defer cfg.Close()
+		require.NoError(t, cfg.Load())
+
+		rec := &lifecycleRecorder{}
+		unsubscribe := cfg.OnLifecycle(rec.record)
+		require.NoError(t, cfg.WatchEvents(context.Background(), func(ChangeEvent) {}))
+		assert.Equal(t, []LifecycleKind{EventWatchStarted}, rec.kinds())
+		assert.Equal(t, "file", rec.last().Mode)
+
+		content, err := os.ReadFile(configPath)
+		require.NoError(t, err)
+		require.NoError(t, writeFileAtomic(configPath, append(content, []byte("extra: true\n")...)))
+		assert.Eventually(t, func() bool {
+			kinds := rec.kinds()
+			return len(kinds) == 2 && kinds[1] == EventReloaded
+		}, 2*time.Second, 10*time.Millisecond)
+
+		unsubscribe()
+		require.NoError(t, cfg.Load())
+		assert.Len(t, rec.kinds(), 2)
+	})
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// logSink is the logger the manager writes to. zapSink adapts the zap
+// logger given to New, and slogSink the slog logger given to WithSlog, so
+// neither is routed through the other.
+type logSink interface {
+	Debug(msg string, fields ...zap.Field)
+	Info(msg string, fields ...zap.Field)
+	Warn(msg string, fields ...zap.Field)
+	Error(msg string, fields ...zap.Field)
+	// Enabled reports whether entries at level are written, so callers can
+	// skip building expensive fields.
+	Enabled(level zapcore.Level) bool
+	Log(level zapcore.Level, msg string, fields ...zap.Field)
+	With(fields ...zap.Field) logSink
+}
+
+// nopSink discards all entries.
+var nopSink logSink = zapSink{zap.NewNop()}
+
+// zapSink writes to a zap logger.
+type zapSink struct {
+	*zap.Logger
+}
+
+func (s zapSink) Enabled(level zapcore.Level) bool {
+	return s.Core().Enabled(level)
+}
+
+func (s zapSink) With(fields ...zap.Field) logSink {
+	return zapSink{s.Logger.With(fields...)}
+}
+
+// WithSlog logs through l instead of the zap logger given to New, so
+// projects standardized on log/slog get the manager's logs from their own
+// handler. Records go to the handler of l directly; pass a nil zap logger
+// to New when using it. Levels map to their slog counterparts, and fields
+// become attributes, objects such as a Change becoming groups.
+func WithSlog(l *slog.Logger) Option {
+	return func(cm *ConfigManager) {
+		if l == nil {
+			cm.logger = nopSink
+			return
+		}
+		cm.logger = slogSink{h: l.Handler()}
+	}
+}
+
+// slogSink writes records to a slog.Handler.
+type slogSink struct {
+	h slog.Handler
+}
+
+func (s slogSink) Debug(msg string, fields ...zap.Field) { s.Log(zapcore.DebugLevel, msg, fields...) }
+func (s slogSink) Info(msg string, fields ...zap.Field)  { s.Log(zapcore.InfoLevel, msg, fields...) }
+func (s slogSink) Warn(msg string, fields ...zap.Field)  { s.Log(zapcore.WarnLevel, msg, fields...) }
+func (s slogSink) Error(msg string, fields ...zap.Field) { s.Log(zapcore.ErrorLevel, msg, fields...) }
+
+func (s slogSink) Enabled(level zapcore.Level) bool {
+	return s.h.Enabled(context.Background(), slogLevel(level))
+}
+
+func (s slogSink) Log(level zapcore.Level, msg string, fields ...zap.Field) {
+	if !s.Enabled(level) {
+		return
+	}
+	r := slog.NewRecord(time.Now(), slogLevel(level), msg, 0)
+	r.AddAttrs(slogAttrs(fields)...)
+	_ = s.h.Handle(context.Background(), r)
+}
+
+func (s slogSink) With(fields ...zap.Field) logSink {
+	return slogSink{h: s.h.WithAttrs(slogAttrs(fields))}
+}
+
+// slogLevel maps a zap level to a slog level. Levels above ErrorLevel,
+// which the manager does not use, log as errors.
+func slogLevel(level zapcore.Level) slog.Level {
+	switch {
+	case level <= zapcore.DebugLevel:
+		return slog.LevelDebug
+	case level == zapcore.InfoLevel:
+		return slog.LevelInfo
+	case level == zapcore.WarnLevel:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
+
+// slogAttrs converts zap fields to slog attributes, in order.
+func slogAttrs(fields []zapcore.Field) []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(fields))
+	for _, f := range fields {
+		// The encoder resolves errors, stacks, and objects to plain values.
+		enc := zapcore.NewMapObjectEncoder()
+		f.AddTo(enc)
+		if value, ok := enc.Fields[f.Key]; ok {
+			attrs = append(attrs, slogAttr(f.Key, value))
+		}
+		// Fields adding other keys, such as verbose errors, come after.
+		for _, key := range sortedKeys(enc.Fields) {
+			if key != f.Key {
+				attrs = append(attrs, slogAttr(key, enc.Fields[key]))
+			}
+		}
+	}
+	return attrs
+}
+
+// slogAttr converts a value of a zapcore.MapObjectEncoder, turning nested
+// objects into groups.
+func slogAttr(key string, value interface{}) slog.Attr {
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return slog.Any(key, value)
+	}
+	group := make([]any, 0, len(m))
+	for _, k := range sortedKeys(m) {
+		group = append(group, slogAttr(k, m[k]))
+	}
+	return slog.Group(key, group...)
+}
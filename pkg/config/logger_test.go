@@ -0,0 +1,58 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSlogLogger(t *testing.T) {
+	t.Run("Forwards Records", func(t *testing.T) {
+		var buf bytes.Buffer
+		cfg := New("", nil, WithSlog(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))))
+
+		require.IsType(t, slogSink{}, cfg.logger, "records must not pass through zap")
+		cfg.logger.Debug("hidden")
+		cfg.logger.With(zap.String("tenant", "acme")).Warn("Config rejected",
+			zap.Int("port", 80),
+			zap.Error(errors.New("boom")),
+			zap.Object("change", Change{Key: "db.password", Old: "a", New: "b", Secret: true}))
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 1)
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+		assert.Equal(t, "WARN", record["level"])
+		assert.Equal(t, "Config rejected", record["msg"])
+		assert.Equal(t, "acme", record["tenant"])
+		assert.EqualValues(t, 80, record["port"])
+		assert.Equal(t, "boom", record["error"])
+		change := record["change"].(map[string]interface{})
+		assert.Equal(t, "db.password", change["key"])
+		assert.Equal(t, RedactedValue, change["new"])
+	})
+
+	t.Run("Logs From Load", func(t *testing.T) {
+		var buf bytes.Buffer
+		configPath, cleanup := setupTestConfig(t)
+		defer cleanup()
+		cfg := New(configPath, nil, WithSlog(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))))
+		require.NoError(t, cfg.Load())
+		assert.Contains(t, buf.String(), `msg="Configuration loaded"`)
+	})
+
+	t.Run("Nil Logger", func(t *testing.T) {
+		configPath, cleanup := setupTestConfig(t)
+		defer cleanup()
+		cfg := New(configPath, nil)
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+	})
+}
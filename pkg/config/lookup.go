@@ -0,0 +1,53 @@
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+// Lookup returns the value for key and whether the key has a value from any
+// source, including defaults, so callers can tell an absent key from one
+// set to its zero value, e.g. "maxConns: 0".
+func (cm *ConfigManager) Lookup(key string) (interface{}, bool) {
+	v := cm.current()
+	if !v.IsSet(key) {
+		return nil, false
+	}
+	return v.Get(key), true
+}
+
+// GetStringOk returns a string value for the given key and whether the key
+// is set.
+func (cm *ConfigManager) GetStringOk(key string) (string, bool) {
+	v, ok := cm.Lookup(key)
+	return cast.ToString(v), ok
+}
+
+// GetIntOk returns an int value for the given key and whether the key is
+// set.
+func (cm *ConfigManager) GetIntOk(key string) (int, bool) {
+	v, ok := cm.Lookup(key)
+	return cast.ToInt(v), ok
+}
+
+// GetFloat64Ok returns a float64 value for the given key and whether the
+// key is set.
+func (cm *ConfigManager) GetFloat64Ok(key string) (float64, bool) {
+	v, ok := cm.Lookup(key)
+	return cast.ToFloat64(v), ok
+}
+
+// GetBoolOk returns a bool value for the given key and whether the key is
+// set.
+func (cm *ConfigManager) GetBoolOk(key string) (bool, bool) {
+	v, ok := cm.Lookup(key)
+	return cast.ToBool(v), ok
+}
+
+// GetDurationOk returns a duration value for the given key and whether the
+// key is set.
+func (cm *ConfigManager) GetDurationOk(key string) (time.Duration, bool) {
+	v, ok := cm.Lookup(key)
+	return cast.ToDuration(v), ok
+}
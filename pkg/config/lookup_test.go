@@ -0,0 +1,85 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestLookup(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := `
+database:
+  maxConns: 0
+  name: ""
+  ratio: 0.5
+  tls: false
+  timeout: 0s
+`
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+	cfg := New(configPath, zap.NewNop(), WithDefaults(map[string]interface{}{
+		"database.port": 5432,
+	}))
+	require.NoError(t, cfg.Load())
+
+	t.Run("Zero Values Are Set", func(t *testing.T) {
+		v, ok := cfg.Lookup("database.maxconns")
+		assert.True(t, ok)
+		assert.Equal(t, 0, v)
+
+		n, ok := cfg.GetIntOk("database.maxConns")
+		assert.True(t, ok)
+		assert.Equal(t, 0, n)
+
+		s, ok := cfg.GetStringOk("database.name")
+		assert.True(t, ok)
+		assert.Equal(t, "", s)
+
+		b, ok := cfg.GetBoolOk("database.tls")
+		assert.True(t, ok)
+		assert.False(t, b)
+
+		d, ok := cfg.GetDurationOk("database.timeout")
+		assert.True(t, ok)
+		assert.Equal(t, time.Duration(0), d)
+
+		f, ok := cfg.GetFloat64Ok("database.ratio")
+		assert.True(t, ok)
+		assert.Equal(t, 0.5, f)
+	})
+
+	t.Run("Absent Keys", func(t *testing.T) {
+		v, ok := cfg.Lookup("database.missing")
+		assert.False(t, ok)
+		assert.Nil(t, v)
+
+		n, ok := cfg.GetIntOk("database.missing")
+		assert.False(t, ok)
+		assert.Equal(t, 0, n)
+	})
+
+	t.Run("Defaults", func(t *testing.T) {
+		n, ok := cfg.GetIntOk("database.port")
+		assert.True(t, ok)
+		assert.Equal(t, 5432, n)
+	})
+
+	t.Run("Views", func(t *testing.T) {
+		n, ok := cfg.Sub("database").GetIntOk("maxconns")
+		assert.True(t, ok)
+		assert.Equal(t, 0, n)
+		_, ok = cfg.Sub("database").Lookup("missing")
+		assert.False(t, ok)
+
+		scoped := cfg.ScopedView("database.maxconns")
+		_, ok = scoped.GetIntOk("database.maxconns")
+		assert.True(t, ok)
+		_, ok = scoped.Lookup("database.name")
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// lookupPath returns the value at path in a nested settings map.
+func lookupPath(m map[string]interface{}, path []string) (interface{}, bool) {
+	var cur interface{} = m
+	for _, p := range path {
+		node, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = node[p]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// setPath sets value at path in a nested settings map, creating intermediate maps.
+func setPath(m map[string]interface{}, path []string, value interface{}) {
+	for _, p := range path[:len(path)-1] {
+		child, ok := m[p].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			m[p] = child
+		}
+		m = child
+	}
+	m[path[len(path)-1]] = value
+}
+
+// deletePath removes the value at path from a nested settings map.
+func deletePath(m map[string]interface{}, path []string) {
+	for _, p := range path[:len(path)-1] {
+		child, ok := m[p].(map[string]interface{})
+		if !ok {
+			return
+		}
+		m = child
+	}
+	delete(m, path[len(path)-1])
+}
+
+// mergeSettings deep-merges src into dst: nested maps are merged key by key
+// and any other value in src replaces the one in dst.
+func mergeSettings(dst, src map[string]interface{}) {
+	for k, sv := range src {
+		if sm, ok := sv.(map[string]interface{}); ok {
+			if dm, ok := dst[k].(map[string]interface{}); ok {
+				mergeSettings(dm, sm)
+				continue
+			}
+		}
+		dst[k] = sv
+	}
+}
+
+// deepCopyMap returns a copy of m in which nested maps and slices are
+// copied as well.
+func deepCopyMap(m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[k] = deepCopyValue(v)
+	}
+	return out
+}
+
+// deepCopyValue returns a copy of v that shares no map or slice with it.
+// Other values are returned as is.
+func deepCopyValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		return deepCopyMap(t)
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, e := range t {
+			out[i] = deepCopyValue(e)
+		}
+		return out
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice:
+		if rv.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			if e := deepCopyValue(rv.Index(i).Interface()); e != nil {
+				out.Index(i).Set(reflect.ValueOf(e))
+			}
+		}
+		return out.Interface()
+	case reflect.Map:
+		if rv.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			e := reflect.Zero(rv.Type().Elem())
+			if c := deepCopyValue(iter.Value().Interface()); c != nil {
+				e = reflect.ValueOf(c)
+			}
+			out.SetMapIndex(iter.Key(), e)
+		}
+		return out.Interface()
+	}
+	return v
+}
+
+// lowerKeys returns a copy of m with every key, at any depth, lowercased.
+func lowerKeys(m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if child, ok := v.(map[string]interface{}); ok {
+			v = lowerKeys(child)
+		}
+		out[strings.ToLower(k)] = v
+	}
+	return out
+}
+
+// leafKeys returns the paths of all non-map values in m, joined with delim.
+func leafKeys(m map[string]interface{}, prefix, delim string) []string {
+	var keys []string
+	for k, v := range m {
+		if child, ok := v.(map[string]interface{}); ok {
+			keys = append(keys, leafKeys(child, prefix+k+delim, delim)...)
+			continue
+		}
+		keys = append(keys, prefix+k)
+	}
+	return keys
+}
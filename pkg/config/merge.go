@@ -0,0 +1,160 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// MergeStrategy decides how a value from a higher-precedence source or a
+// later override file combines with the value it overrides.
+type MergeStrategy int
+
+const (
+	// MergeDefault deep-merges maps key by key; any other value, including
+	// a slice, replaces the one below it.
+	MergeDefault MergeStrategy = iota
+	// MergeReplace replaces the value wholesale, maps included.
+	MergeReplace
+	// MergeAppend appends a slice to the slice below it.
+	MergeAppend
+	// MergeByKey merges a slice of maps into the one below it element by
+	// element, matching elements on a field set with WithMergeByKey;
+	// unmatched elements are appended.
+	MergeByKey
+)
+
+func (s MergeStrategy) String() string {
+	switch s {
+	case MergeDefault:
+		return "default"
+	case MergeReplace:
+		return "replace"
+	case MergeAppend:
+		return "append"
+	case MergeByKey:
+		return "by-key"
+	}
+	return "unknown"
+}
+
+// mergeRule is the strategy configured for one key.
+type mergeRule struct {
+	strategy MergeStrategy
+	// field matches elements for MergeByKey.
+	field string
+}
+
+// WithMergeStrategy sets how values at key combine when sources are
+// layered and override or profile files are merged, e.g.
+// WithMergeStrategy("server.allowed_origins", MergeAppend) so an override
+// file adds origins instead of replacing the list, or MergeReplace so a map
+// is never merged with the one below it. Including files always merge with
+// MergeDefault.
+func WithMergeStrategy(key string, strategy MergeStrategy) Option {
+	return func(cm *ConfigManager) {
+		cm.setMergeRule(key, mergeRule{strategy: strategy})
+	}
+}
+
+// WithMergeByKey merges the slice of maps at key element by element,
+// matching elements whose field has the same value, e.g.
+// WithMergeByKey("upstreams", "name") lets an override file change one
+// upstream's timeout by listing just its name and the new timeout.
+func WithMergeByKey(key, field string) Option {
+	return func(cm *ConfigManager) {
+		cm.setMergeRule(key, mergeRule{strategy: MergeByKey, field: strings.ToLower(field)})
+	}
+}
+
+func (cm *ConfigManager) setMergeRule(key string, rule mergeRule) {
+	if cm.mergeRules == nil {
+		cm.mergeRules = make(map[string]mergeRule)
+	}
+	cm.mergeRules[strings.ToLower(key)] = rule
+}
+
+// mergePolicy applies the per-key merge rules. A nil policy merges every
+// key with MergeDefault.
+type mergePolicy struct {
+	delim string
+	rules map[string]mergeRule
+}
+
+// newMergePolicy returns the policy for rules, or nil if there are none.
+func newMergePolicy(rules map[string]mergeRule, delim string) *mergePolicy {
+	if len(rules) == 0 {
+		return nil
+	}
+	return &mergePolicy{delim: delim, rules: rules}
+}
+
+// merge merges src into dst following the policy.
+func (p *mergePolicy) merge(dst, src map[string]interface{}) {
+	if p == nil {
+		mergeSettings(dst, src)
+		return
+	}
+	p.mergeAt(dst, src, "")
+}
+
+func (p *mergePolicy) mergeAt(dst, src map[string]interface{}, prefix string) {
+	for k, sv := range src {
+		key := prefix + k
+		rule := p.rules[key]
+		switch rule.strategy {
+		case MergeReplace:
+			dst[k] = sv
+			continue
+		case MergeAppend:
+			if ds, ok := dst[k].([]interface{}); ok {
+				if ss, ok := sv.([]interface{}); ok {
+					dst[k] = append(append([]interface{}(nil), ds...), ss...)
+					continue
+				}
+			}
+		case MergeByKey:
+			if ds, ok := dst[k].([]interface{}); ok {
+				if ss, ok := sv.([]interface{}); ok {
+					dst[k] = mergeByField(ds, ss, rule.field)
+					continue
+				}
+			}
+		}
+
+		if sm, ok := sv.(map[string]interface{}); ok {
+			if dm, ok := dst[k].(map[string]interface{}); ok {
+				p.mergeAt(dm, sm, key+p.delim)
+				continue
+			}
+		}
+		dst[k] = sv
+	}
+}
+
+// mergeByField merges the maps in src into those in dst with the same value
+// of field, and appends the others.
+func mergeByField(dst, src []interface{}, field string) []interface{} {
+	out := append([]interface{}(nil), dst...)
+	for _, se := range src {
+		sm, ok := se.(map[string]interface{})
+		if !ok {
+			out = append(out, se)
+			continue
+		}
+		matched := false
+		for i, de := range out {
+			dm, ok := de.(map[string]interface{})
+			if ok && sm[field] != nil && reflect.DeepEqual(dm[field], sm[field]) {
+				merged := deepCopyMap(dm)
+				mergeSettings(merged, sm)
+				out[i] = merged
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			out = append(out, se)
+		}
+	}
+	return out
+}
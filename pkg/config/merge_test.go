@@ -0,0 +1,85 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMergeStrategies(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	local := filepath.Join(dir, "config.local.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+server:
+  origins: [https://app.example.com]
+  ports: [80]
+labels:
+  team: core
+  tier: web
+upstreams:
+  - name: api
+    timeout: 5s
+    retries: 3
+  - name: auth
+    timeout: 1s
+`), 0644))
+	require.NoError(t, os.WriteFile(local, []byte(`
+server:
+  origins: [http://localhost:3000]
+  ports: [8080]
+labels:
+  team: platform
+upstreams:
+  - name: api
+    timeout: 30s
+  - name: search
+    timeout: 2s
+`), 0644))
+
+	t.Run("Default", func(t *testing.T) {
+		cfg := New(configPath, zap.NewNop(), WithOverrideFiles(local))
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, []string{"http://localhost:3000"}, cfg.GetStringSlice("server.origins"))
+		assert.Equal(t, map[string]string{"team": "platform", "tier": "web"}, cfg.GetStringMapString("labels"))
+		assert.Len(t, cfg.Get("upstreams"), 2)
+	})
+
+	t.Run("Configured", func(t *testing.T) {
+		cfg := New(configPath, zap.NewNop(),
+			WithOverrideFiles(local),
+			WithMergeStrategy("server.origins", MergeAppend),
+			WithMergeStrategy("labels", MergeReplace),
+			WithMergeByKey("upstreams", "Name"))
+		require.NoError(t, cfg.Load())
+
+		assert.Equal(t, []string{"https://app.example.com", "http://localhost:3000"}, cfg.GetStringSlice("server.origins"))
+		assert.Equal(t, []int{8080}, cfg.GetIntSlice("server.ports"))
+		assert.Equal(t, map[string]string{"team": "platform"}, cfg.GetStringMapString("labels"))
+
+		type Upstream struct {
+			Name    string `mapstructure:"name"`
+			Timeout string `mapstructure:"timeout"`
+			Retries int    `mapstructure:"retries"`
+		}
+		var upstreams []Upstream
+		require.NoError(t, cfg.UnmarshalKey("upstreams", &upstreams))
+		assert.Equal(t, []Upstream{
+			{Name: "api", Timeout: "30s", Retries: 3},
+			{Name: "auth", Timeout: "1s"},
+			{Name: "search", Timeout: "2s"},
+		}, upstreams)
+	})
+
+	t.Run("Across Sources", func(t *testing.T) {
+		cfg := New(configPath, zap.NewNop(),
+			WithDefaults(map[string]interface{}{"server.origins": []interface{}{"https://status.example.com"}}),
+			WithMergeStrategy("server.origins", MergeAppend))
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, []string{"https://status.example.com", "https://app.example.com"}, cfg.GetStringSlice("server.origins"))
+	})
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cast"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// ConfigVersionKey is the document key declaring the version of the
+// config schema a document was written for, read by migrations.
+// SchemaVersionKey is accepted as an alias, so documents already declaring
+// their version for the remote compatibility check need no second key.
+const ConfigVersionKey = "configVersion"
+
+// MigrationFunc upgrades a configuration document in place. Keys in
+// settings are lowercased, as viper stores them.
+type MigrationFunc func(settings map[string]interface{}) error
+
+// migration upgrades documents at one schema version to a later one.
+type migration struct {
+	to int
+	fn MigrationFunc
+}
+
+// RegisterMigration registers fn to upgrade documents whose
+// ConfigVersionKey is from to version to. On every load, migrations are
+// chained from the version a document declares until none is registered
+// for the version reached, or the version set with WithSchemaVersion is
+// reached; each step is logged. Documents declaring no version are not
+// migrated. With WithSchemaVersion, a document that cannot be brought up to
+// that version is rejected.
+func (cm *ConfigManager) RegisterMigration(from, to int, fn MigrationFunc) error {
+	if to <= from {
+		return fmt.Errorf("migration from version %d must target a later version, got %d", from, to)
+	}
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	if cm.migrations == nil {
+		cm.migrations = make(map[int]migration)
+	}
+	if _, ok := cm.migrations[from]; ok {
+		return fmt.Errorf("migration from version %d is already registered", from)
+	}
+	cm.migrations[from] = migration{to: to, fn: fn}
+	return nil
+}
+
+// applyMigrations upgrades the document in v to the current schema version.
+// The caller must hold cm.mu.
+func (cm *ConfigManager) applyMigrations(v *viper.Viper) (*viper.Viper, error) {
+	if len(cm.migrations) == 0 {
+		return v, nil
+	}
+	key := ConfigVersionKey
+	if !v.IsSet(key) {
+		key = SchemaVersionKey
+	}
+	if !v.IsSet(key) {
+		return v, nil
+	}
+	version, err := cast.ToIntE(v.Get(key))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", key, err)
+	}
+
+	var settings map[string]interface{}
+	for cm.schemaVersion <= 0 || version < cm.schemaVersion {
+		m, ok := cm.migrations[version]
+		if !ok {
+			break
+		}
+		if settings == nil {
+			settings = v.AllSettings()
+		}
+		if err := m.fn(settings); err != nil {
+			return nil, fmt.Errorf("migrating config from version %d to %d: %w", version, m.to, err)
+		}
+		cm.logger.Info("Migrated configuration",
+			zap.Int("from", version),
+			zap.Int("to", m.to))
+		version = m.to
+	}
+	if cm.schemaVersion > 0 && version < cm.schemaVersion {
+		return nil, fmt.Errorf("no migration registered from config version %d towards %d", version, cm.schemaVersion)
+	}
+	if settings == nil {
+		return v, nil
+	}
+
+	// The migrated document declares the version it was brought up to.
+	settings[strings.ToLower(key)] = version
+	migrated := cm.newViper()
+	if err := migrated.MergeConfigMap(settings); err != nil {
+		return nil, err
+	}
+	return migrated, nil
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestMigrations(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(content string) {
+		require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+	}
+
+	// v1 had a flat db_host; v2 nested it; v3 renamed port to listen.
+	register := func(cfg *ConfigManager) {
+		require.NoError(t, cfg.RegisterMigration(1, 2, func(s map[string]interface{}) error {
+			setPath(s, []string{"database", "host"}, s["db_host"])
+			delete(s, "db_host")
+			return nil
+		}))
+		require.NoError(t, cfg.RegisterMigration(2, 3, func(s map[string]interface{}) error {
+			s["listen"] = s["port"]
+			delete(s, "port")
+			return nil
+		}))
+	}
+
+	t.Run("Chained", func(t *testing.T) {
+		write("configVersion: 1\ndb_host: db.internal\nport: 8080\n")
+		core, logs := observer.New(zap.InfoLevel)
+		cfg := New(configPath, zap.New(core), WithSchemaVersion(3))
+		register(cfg)
+		require.NoError(t, cfg.Load())
+
+		assert.Equal(t, "db.internal", cfg.GetString("database.host"))
+		assert.Equal(t, 8080, cfg.GetInt("listen"))
+		assert.False(t, cfg.IsSet("db_host"))
+		assert.Equal(t, 3, cfg.GetInt(ConfigVersionKey))
+		assert.Equal(t, 2, logs.FilterMessage("Migrated configuration").Len())
+	})
+
+	t.Run("Schema Version Alias", func(t *testing.T) {
+		write("schemaVersion: 2\nport: 8080\n")
+		cfg := New(configPath, zap.NewNop(), WithSchemaVersion(3))
+		register(cfg)
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 8080, cfg.GetInt("listen"))
+		assert.Equal(t, 3, cfg.GetInt(SchemaVersionKey))
+		assert.False(t, cfg.IsSet(ConfigVersionKey))
+	})
+
+	t.Run("Config Version Preferred", func(t *testing.T) {
+		write("configVersion: 2\nschemaVersion: 1\nport: 8080\n")
+		cfg := New(configPath, zap.NewNop())
+		register(cfg)
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 8080, cfg.GetInt("listen"))
+		assert.Equal(t, 3, cfg.GetInt(ConfigVersionKey))
+	})
+
+	t.Run("Current Document Untouched", func(t *testing.T) {
+		write("configVersion: 3\nlisten: 9090\n")
+		cfg := New(configPath, zap.NewNop(), WithSchemaVersion(3))
+		register(cfg)
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 9090, cfg.GetInt("listen"))
+	})
+
+	t.Run("Missing Step", func(t *testing.T) {
+		write("configVersion: 0\nport: 1\n")
+		cfg := New(configPath, zap.NewNop(), WithSchemaVersion(3))
+		register(cfg)
+		assert.ErrorContains(t, cfg.Load(), "no migration registered from config version 0")
+	})
+
+	t.Run("Failed Migration", func(t *testing.T) {
+		write("configVersion: 1\n")
+		cfg := New(configPath, zap.NewNop())
+		require.NoError(t, cfg.RegisterMigration(1, 2, func(map[string]interface{}) error {
+			return errors.New("boom")
+		}))
+		assert.ErrorContains(t, cfg.Load(), "migrating config from version 1 to 2: boom")
+	})
+
+	t.Run("Invalid Registration", func(t *testing.T) {
+		cfg := New(configPath, zap.NewNop())
+		assert.Error(t, cfg.RegisterMigration(2, 2, nil))
+		require.NoError(t, cfg.RegisterMigration(1, 2, func(map[string]interface{}) error { return nil }))
+		assert.Error(t, cfg.RegisterMigration(1, 3, func(map[string]interface{}) error { return nil }))
+	})
+}
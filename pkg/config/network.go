@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// GetURL returns the value for key parsed as an absolute URL. A missing key
+// returns an error matching ErrKeyNotSet, and an invalid value a
+// *ValidationError with rule "url".
+func (cm *ConfigManager) GetURL(key string) (*url.URL, error) {
+	raw, err := cm.getNetworkString(key)
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" || (u.Host == "" && u.Opaque == "" && u.Path == "") {
+		return nil, networkValidationError(key, raw, "url", "must be an absolute URL")
+	}
+	return u, nil
+}
+
+// GetIP returns the value for key parsed as an IPv4 or IPv6 address. A
+// missing key returns an error matching ErrKeyNotSet, and an invalid value a
+// *ValidationError with rule "ip".
+func (cm *ConfigManager) GetIP(key string) (net.IP, error) {
+	raw, err := cm.getNetworkString(key)
+	if err != nil {
+		return nil, err
+	}
+	ip := net.ParseIP(raw)
+	if ip == nil {
+		return nil, networkValidationError(key, raw, "ip", "must be an IP address")
+	}
+	return ip, nil
+}
+
+// GetCIDR returns the value for key parsed as a network in CIDR notation,
+// e.g. "10.0.0.0/8". A missing key returns an error matching ErrKeyNotSet,
+// and an invalid value a *ValidationError with rule "cidr".
+func (cm *ConfigManager) GetCIDR(key string) (*net.IPNet, error) {
+	raw, err := cm.getNetworkString(key)
+	if err != nil {
+		return nil, err
+	}
+	_, network, err := net.ParseCIDR(raw)
+	if err != nil {
+		return nil, networkValidationError(key, raw, "cidr", "must be a network in CIDR notation")
+	}
+	return network, nil
+}
+
+// getNetworkString returns the trimmed string value for key.
+func (cm *ConfigManager) getNetworkString(key string) (string, error) {
+	if !cm.IsSet(key) {
+		return "", fmt.Errorf("%w: '%s'", ErrKeyNotSet, key)
+	}
+	return strings.TrimSpace(cm.GetString(key)), nil
+}
+
+func networkValidationError(key, value, rule, msg string) *ValidationError {
+	return &ValidationError{Key: strings.ToLower(key), Value: value, Rule: rule, Message: msg}
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestNetworkGetters(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := `
+upstream:
+  url: https://api.example.com:8443/v1
+  socket: unix:///var/run/app.sock
+  relative: /v1/items
+bind:
+  ip: "::1"
+  v4: 10.1.2.3
+  bad: 10.1.2
+allow:
+  cidr: 10.0.0.0/8
+  bad: 10.0.0.0/33
+`
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+	cfg := New(configPath, zap.NewNop())
+	require.NoError(t, cfg.Load())
+
+	t.Run("URL", func(t *testing.T) {
+		u, err := cfg.GetURL("upstream.url")
+		require.NoError(t, err)
+		assert.Equal(t, "api.example.com:8443", u.Host)
+		assert.Equal(t, "/v1", u.Path)
+
+		u, err = cfg.GetURL("upstream.socket")
+		require.NoError(t, err)
+		assert.Equal(t, "unix", u.Scheme)
+
+		_, err = cfg.GetURL("upstream.relative")
+		var verr *ValidationError
+		require.ErrorAs(t, err, &verr)
+		assert.Equal(t, "upstream.relative", verr.Key)
+		assert.Equal(t, "url", verr.Rule)
+		assert.Equal(t, "/v1/items", verr.Value)
+	})
+
+	t.Run("IP", func(t *testing.T) {
+		ip, err := cfg.GetIP("bind.ip")
+		require.NoError(t, err)
+		assert.True(t, ip.IsLoopback())
+
+		ip, err = cfg.GetIP("bind.v4")
+		require.NoError(t, err)
+		assert.Equal(t, "10.1.2.3", ip.String())
+
+		_, err = cfg.GetIP("bind.bad")
+		assert.EqualError(t, err, "validation failed for field 'bind.bad': must be an IP address")
+	})
+
+	t.Run("CIDR", func(t *testing.T) {
+		network, err := cfg.GetCIDR("allow.cidr")
+		require.NoError(t, err)
+		assert.Equal(t, "10.0.0.0/8", network.String())
+
+		_, err = cfg.GetCIDR("allow.bad")
+		var verr *ValidationError
+		require.ErrorAs(t, err, &verr)
+		assert.Equal(t, "cidr", verr.Rule)
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		_, err := cfg.GetURL("missing")
+		assert.ErrorIs(t, err, ErrKeyNotSet)
+		_, err = cfg.GetIP("missing")
+		assert.ErrorIs(t, err, ErrKeyNotSet)
+		_, err = cfg.GetCIDR("missing")
+		assert.ErrorIs(t, err, ErrKeyNotSet)
+	})
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"strings"
+)
+
+// Origin returns the layer the effective value of key was taken from:
+// Override for values written by Set, the source recorded by the last load,
+// or Defaults for keys no source sets. OriginName tells which file,
+// environment variable, or remote document it was.
+func (cm *ConfigManager) Origin(key string) Source {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.origin(strings.ToLower(key))
+}
+
+// OriginName returns the config file, environment variable, remote
+// document, or flag the effective value of key was taken from, e.g.
+// "conf/database.yaml", "APP_DATABASE_PORT",
+// "consul://localhost:8500/app/config", or "--database.port". It returns ""
+// for defaults and runtime overrides.
+func (cm *ConfigManager) OriginName(key string) string {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.originName(strings.ToLower(key))
+}
+
+// origin returns the layer key was taken from, including keys resolved only
+// through viper's automatic env lookup. The caller must hold cm.mu.
+func (cm *ConfigManager) origin(key string) Source {
+	src := cm.originOf(key, cm.origins)
+	if _, ok := cm.origins[key]; !ok && src == Defaults {
+		if cm.envPrefix != "" && automaticEnv(cm.precedence) {
+			if _, _, ok := lookupEnv(key, cm.envPrefix, cm.keyDelimiter, nil); ok {
+				return Env
+			}
+		}
+	}
+	return src
+}
+
+// originName names the file, variable, or document key was taken from. The
+// caller must hold cm.mu.
+func (cm *ConfigManager) originName(key string) string {
+	switch cm.origin(key) {
+	case File:
+		if name, ok := cm.files[key]; ok {
+			return name
+		}
+		return cm.path
+	case Env:
+		_, name, _ := lookupEnv(key, cm.envPrefix, cm.keyDelimiter, cm.envBindings)
+		return name
+	case Remote:
+		return cm.sourceName()
+	case Flags:
+		return "--" + key
+	}
+	return ""
+}
+
+// fileTrace records the settings decoded from each config file, in the
+// order they are merged, to tell which file set a key. A nil trace records
+// nothing.
+type fileTrace struct {
+	files []tracedFile
+}
+
+// tracedFile is the settings a single file sets itself.
+type tracedFile struct {
+	path     string
+	settings map[string]interface{}
+}
+
+// add records the settings read from the file at path.
+func (t *fileTrace) add(path string, settings map[string]interface{}) {
+	if t == nil {
+		return
+	}
+	t.files = append(t.files, tracedFile{path: path, settings: deepCopyMap(lowerKeys(settings))})
+}
+
+// locate returns the file that last set each key among the keys origins
+// attributes to File.
+func (t *fileTrace) locate(origins map[string]Source, delim string) map[string]string {
+	files := make(map[string]string)
+	for _, f := range t.files {
+		for _, key := range leafKeys(f.settings, "", delim) {
+			if origins[key] == File {
+				files[key] = f.path
+			}
+		}
+	}
+	return files
+}
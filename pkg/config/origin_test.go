@@ -0,0 +1,60 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestOrigin(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "config.yaml")
+	included := filepath.Join(dir, "database.yaml")
+	local := filepath.Join(dir, "config.local.yaml")
+	require.NoError(t, os.WriteFile(base, []byte("include: [database.yaml]\nserver:\n  port: 8080\n"), 0644))
+	require.NoError(t, os.WriteFile(included, []byte("database:\n  host: db\n  port: 5432\n"), 0644))
+	require.NoError(t, os.WriteFile(local, []byte("server:\n  host: localhost\n"), 0644))
+
+	t.Setenv("APP_DATABASE_PORT", "6543")
+	t.Setenv("APP_ONLY_ENV", "yes")
+	cfg := New(base, zap.NewNop(),
+		WithEnvPrefix("APP"),
+		WithOverrideFiles(local),
+		WithDefaults(map[string]interface{}{"cache.ttl": "1m"}))
+	require.NoError(t, cfg.Load())
+	require.NoError(t, cfg.Set("server.debug", true))
+
+	tests := []struct {
+		key    string
+		source Source
+		name   string
+	}{
+		{"server.port", File, base},
+		{"database.host", File, included},
+		{"server.host", File, local},
+		{"database.port", Env, "APP_DATABASE_PORT"},
+		{"only.env", Env, "APP_ONLY_ENV"},
+		{"cache.ttl", Defaults, ""},
+		{"server.debug", Override, ""},
+		{"Server.Port", File, base},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			assert.Equal(t, tt.source, cfg.Origin(tt.key))
+			assert.Equal(t, tt.name, cfg.OriginName(tt.key))
+		})
+	}
+
+	t.Run("Remote", func(t *testing.T) {
+		remote := useFakeRemote(t)
+		remote.set("app/config", []byte(`{"server": {"port": 8080}}`))
+		cfg := newRemoteTestConfig()
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, Remote, cfg.Origin("server.port"))
+		assert.Equal(t, "consul://localhost:8500/app/config", cfg.OriginName("server.port"))
+	})
+}
@@ -0,0 +1,237 @@
+package config
+
+import (
+	"errors"
+	"maps"
+	"strings"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// runtimeLayers holds the changes made through the runtime API. They are
+// reapplied on every load so they survive reloads.
+type runtimeLayers struct {
+	overrides map[string]interface{}
+	defaults  map[string]interface{}
+	// masked lists keys removed at runtime; they are hidden from every source.
+	masked map[string]bool
+	// expiries maps overrides set with SetWithTTL to their expiry timer.
+	expiries map[string]uint64
+}
+
+func (r runtimeLayers) clone() runtimeLayers {
+	return runtimeLayers{
+		overrides: maps.Clone(r.overrides),
+		defaults:  maps.Clone(r.defaults),
+		masked:    maps.Clone(r.masked),
+		expiries:  maps.Clone(r.expiries),
+	}
+}
+
+// Set overrides key with value at runtime. Overrides take precedence over
+// every source, survive reloads, and are validated like any other change:
+// if the resulting configuration is rejected, the override is discarded
+// and the error returned. An applied change is published to Subscribe
+// callbacks, as every runtime change is.
+func (cm *ConfigManager) Set(key string, value interface{}) error {
+	key = strings.ToLower(key)
+	return cm.updateRuntime(key, func(r *runtimeLayers) error {
+		r.overrides[key] = value
+		delete(r.masked, key)
+		delete(r.expiries, key)
+		return nil
+	})
+}
+
+// SetDefault sets a runtime default for key. It survives reloads and
+// applies only when no source, including WithDefaults, sets the key.
+func (cm *ConfigManager) SetDefault(key string, value interface{}) error {
+	key = strings.ToLower(key)
+	return cm.updateRuntime(key, func(r *runtimeLayers) error {
+		r.defaults[key] = value
+		return nil
+	})
+}
+
+// Unset removes every runtime change made to key and the keys nested below
+// it (overrides, runtime defaults, and keys removed by Patch), so its
+// effective value is computed from the sources again. Viper has no way to
+// unset a value; this works because runtime changes live in their own layer
+// that is reapplied on every load.
+func (cm *ConfigManager) Unset(key string) error {
+	key = strings.ToLower(key)
+	return cm.updateRuntime("unset "+key, func(r *runtimeLayers) error {
+		r.unset(key, cm.keyDelimiter)
+		return nil
+	})
+}
+
+// unset drops key and its nested keys from every runtime layer.
+func (r *runtimeLayers) unset(key, delim string) {
+	covers := func(k string) bool {
+		return k == key || strings.HasPrefix(k, key+delim)
+	}
+	for k := range r.overrides {
+		if covers(k) {
+			delete(r.overrides, k)
+		}
+	}
+	for k := range r.defaults {
+		if covers(k) {
+			delete(r.defaults, k)
+		}
+	}
+	for k := range r.masked {
+		if covers(k) {
+			delete(r.masked, k)
+		}
+	}
+	for k := range r.expiries {
+		if covers(k) {
+			delete(r.expiries, k)
+		}
+	}
+}
+
+// updateRuntime applies mutate to a copy of the runtime layers and reloads.
+// If mutate fails or the reload is rejected, the previous layers are kept.
+// An applied change is published to subscribers like a watcher reload, so
+// they see an override start as well as end. mutate runs with cm.mu held.
+func (cm *ConfigManager) updateRuntime(desc string, mutate func(r *runtimeLayers) error) error {
+	event, applied, err := cm.stageRuntime(desc, mutate)
+	cm.flushLifecycle()
+	if applied && (event.Err != nil || len(event.Changes) > 0) {
+		cm.publish(event)
+	}
+	return err
+}
+
+// stageRuntime runs the reload of updateRuntime under cm.mu and describes
+// it, reporting whether the change was applied.
+func (cm *ConfigManager) stageRuntime(desc string, mutate func(r *runtimeLayers) error) (ChangeEvent, bool, error) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	if cm.closed {
+		return ChangeEvent{}, false, ErrClosed
+	}
+	if cm.frozen.Load() {
+		return ChangeEvent{}, false, ErrFrozen
+	}
+
+	prev := cm.runtime
+	next := prev.clone()
+	if next.overrides == nil {
+		next.overrides = make(map[string]interface{})
+	}
+	if next.defaults == nil {
+		next.defaults = make(map[string]interface{})
+	}
+	if next.masked == nil {
+		next.masked = make(map[string]bool)
+	}
+	if next.expiries == nil {
+		next.expiries = make(map[string]uint64)
+	}
+	if err := mutate(&next); err != nil {
+		return ChangeEvent{}, false, err
+	}
+	cm.runtime = next
+
+	// Re-read the sources even if they are unchanged.
+	if c, ok := cm.provider.(committer); ok {
+		c.invalidate()
+	}
+	err := cm.load()
+	if err != nil {
+		// A partial apply has already swapped in the change.
+		var partial *PartialApplyError
+		if !errors.As(err, &partial) {
+			cm.runtime = prev
+			cm.logger.Warn("Rejected runtime configuration change",
+				zap.String("change", desc),
+				zap.Error(err))
+			return ChangeEvent{}, false, err
+		}
+	} else {
+		cm.logger.Info("Applied runtime configuration change", zap.String("change", desc))
+	}
+	return ChangeEvent{
+		Source:      cm.sourceName(),
+		Time:        cm.lastLoad,
+		Changes:     append([]Change(nil), cm.changes...),
+		Rotated:     append([]string(nil), cm.rotated...),
+		Annotations: cm.annotations,
+		Err:         err,
+	}, true, err
+}
+
+// applyRuntime writes the runtime layers into v: overrides through viper's
+// override layer, which beats env and config, and defaults through its
+// default layer, which loses to everything. Masked keys require rebuilding
+// v from its settings, since viper cannot unset values.
+func (cm *ConfigManager) applyRuntime(v *viper.Viper) (*viper.Viper, error) {
+	for key, value := range cm.runtime.defaults {
+		v.SetDefault(key, value)
+	}
+	for key, value := range cm.runtime.overrides {
+		v.Set(key, value)
+	}
+	if len(cm.runtime.masked) == 0 {
+		return v, nil
+	}
+
+	settings := v.AllSettings()
+	for key := range cm.runtime.masked {
+		deletePath(settings, cm.splitKey(key))
+	}
+	rebuilt := cm.newViper()
+	if err := rebuilt.MergeConfigMap(settings); err != nil {
+		return nil, err
+	}
+	return rebuilt, nil
+}
+
+// Tx stages runtime changes for Update.
+type Tx struct {
+	cm *ConfigManager
+	r  *runtimeLayers
+}
+
+// Set stages an override of key, as ConfigManager.Set does.
+func (tx *Tx) Set(key string, value interface{}) {
+	key = strings.ToLower(key)
+	tx.r.overrides[key] = value
+	delete(tx.r.masked, key)
+	delete(tx.r.expiries, key)
+}
+
+// SetDefault stages a runtime default for key, as ConfigManager.SetDefault does.
+func (tx *Tx) SetDefault(key string, value interface{}) {
+	tx.r.defaults[strings.ToLower(key)] = value
+}
+
+// Unset stages the removal of runtime changes to key, as ConfigManager.Unset does.
+func (tx *Tx) Unset(key string) {
+	tx.r.unset(strings.ToLower(key), tx.cm.keyDelimiter)
+}
+
+// Get returns the value staged for key in this transaction, or the live
+// value if the transaction has not overridden it.
+func (tx *Tx) Get(key string) interface{} {
+	if value, ok := tx.r.overrides[strings.ToLower(key)]; ok {
+		return value
+	}
+	return tx.cm.viper.Get(key)
+}
+
+// Update changes several keys atomically. fn stages changes on tx; once it
+// returns, the staged configuration is validated and swapped in only if it
+// passes. If fn returns an error or validation fails, nothing changes.
+// fn must not call other ConfigManager methods.
+func (cm *ConfigManager) Update(fn func(tx *Tx) error) error {
+	return cm.updateRuntime("transaction", func(r *runtimeLayers) error {
+		return fn(&Tx{cm: cm, r: r})
+	})
+}
@@ -0,0 +1,176 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRuntimeOverrides(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	logger, _ := zap.NewDevelopment()
+
+	t.Run("Set Beats Every Source", func(t *testing.T) {
+		t.Setenv("APP_SERVER_PORT", "9090")
+
+		cfg := New(configPath, logger, WithEnvPrefix("APP"))
+		require.NoError(t, cfg.Load())
+		require.NoError(t, cfg.Set("server.port", 7070))
+		assert.Equal(t, 7070, cfg.GetInt("server.port"))
+	})
+
+	t.Run("Survives Reload", func(t *testing.T) {
+		cfg := New(configPath, logger, WithHighFrequencyReload())
+		require.NoError(t, cfg.Load())
+		require.NoError(t, cfg.Set("server.host", "patched"))
+		require.NoError(t, cfg.SetDefault("cache.ttl", "1m"))
+
+		content, err := os.ReadFile(configPath)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(configPath, append(content, []byte("extra: true\n")...), 0644))
+		require.NoError(t, cfg.Load())
+
+		assert.True(t, cfg.GetBool("extra"))
+		assert.Equal(t, "patched", cfg.GetString("server.host"))
+		assert.Equal(t, "1m", cfg.GetString("cache.ttl"))
+	})
+
+	t.Run("SetDefault Loses To Sources", func(t *testing.T) {
+		cfg := New(configPath, logger)
+		require.NoError(t, cfg.Load())
+		require.NoError(t, cfg.SetDefault("server.port", 1))
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+	})
+
+	t.Run("Invalid Override Is Discarded", func(t *testing.T) {
+		schema := &TestConfig{}
+		cfg := New(configPath, logger, WithSchema(schema))
+		require.NoError(t, cfg.Load())
+
+		require.Error(t, cfg.Set("server.port", -1))
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+		assert.Equal(t, 8080, schema.Server.Port)
+
+		// A later reload must not resurrect the rejected override.
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+	})
+
+	t.Run("Scoped View Cannot Set", func(t *testing.T) {
+		cfg := New(configPath, logger)
+		require.NoError(t, cfg.Load())
+		assert.ErrorIs(t, cfg.ScopedView("server").Set("server.port", 1), ErrAccessDenied)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	logger, _ := zap.NewDevelopment()
+	schema := &TestConfig{}
+	cfg := New(configPath, logger, WithSchema(schema))
+	require.NoError(t, cfg.Load())
+
+	t.Run("Commit", func(t *testing.T) {
+		require.NoError(t, cfg.Update(func(tx *Tx) error {
+			tx.Set("database.host", "db.internal")
+			tx.Set("database.port", tx.Get("database.port").(int)+1)
+			return nil
+		}))
+		assert.Equal(t, "db.internal", cfg.GetString("database.host"))
+		assert.Equal(t, 5433, schema.Database.Port)
+	})
+
+	t.Run("Validation Failure Rolls Back", func(t *testing.T) {
+		err := cfg.Update(func(tx *Tx) error {
+			tx.Set("server.host", "api.internal")
+			tx.Set("server.port", 0)
+			return nil
+		})
+		require.Error(t, err)
+		assert.Equal(t, "localhost", cfg.GetString("server.host"))
+		assert.Equal(t, "localhost", schema.Server.Host)
+	})
+
+	t.Run("Callback Error Rolls Back", func(t *testing.T) {
+		errAbort := errors.New("abort")
+		err := cfg.Update(func(tx *Tx) error {
+			tx.Set("server.host", "api.internal")
+			return errAbort
+		})
+		assert.ErrorIs(t, err, errAbort)
+		assert.Equal(t, "localhost", cfg.GetString("server.host"))
+	})
+}
+
+func TestUnset(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	logger, _ := zap.NewDevelopment()
+	t.Setenv("APP_SERVER_HOST", "env.internal")
+	cfg := New(configPath, logger, WithEnvPrefix("APP"))
+	require.NoError(t, cfg.Load())
+
+	t.Run("Falls Back To Lower Layers", func(t *testing.T) {
+		require.NoError(t, cfg.Set("server.host", "patched"))
+		require.NoError(t, cfg.Set("server.port", 1234))
+		require.NoError(t, cfg.Unset("server.host"))
+
+		assert.Equal(t, "env.internal", cfg.GetString("server.host"))
+		assert.Equal(t, 1234, cfg.GetInt("server.port"))
+	})
+
+	t.Run("Subtree", func(t *testing.T) {
+		require.NoError(t, cfg.SetDefault("server.tls", true))
+		require.NoError(t, cfg.Unset("server"))
+
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+		assert.False(t, cfg.IsSet("server.tls"))
+	})
+
+	t.Run("Restores Patched Removal", func(t *testing.T) {
+		require.NoError(t, cfg.Patch([]byte(`{"database": {"name": null}}`), MergePatch))
+		require.False(t, cfg.IsSet("database.name"))
+
+		require.NoError(t, cfg.Unset("database.name"))
+		assert.Equal(t, "testdb", cfg.GetString("database.name"))
+	})
+}
+
+func TestRuntimeChangesPublished(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+	cfg := New(configPath, zap.NewNop(), WithSchema(&TestConfig{}))
+	defer cfg.Close()
+	require.NoError(t, cfg.Load())
+
+	var events []ChangeEvent
+	cfg.Subscribe(func(event ChangeEvent) { events = append(events, event) })
+	var ports []interface{}
+	cfg.OnKeyChange("server.port", func(_, new interface{}) { ports = append(ports, new) })
+
+	require.NoError(t, cfg.Set("server.port", 2))
+	require.NoError(t, cfg.Update(func(tx *Tx) error {
+		tx.Set("server.port", 3)
+		return nil
+	}))
+	require.NoError(t, cfg.Patch([]byte(`{"server": {"port": 4}}`), MergePatch))
+	require.NoError(t, cfg.Unset("server.port"))
+	assert.Equal(t, []interface{}{2, 3, float64(4), 8080}, ports)
+	require.Len(t, events, 4)
+	assert.Equal(t, Change{Key: "server.port", Old: 8080, New: 2, Source: Override}, events[0].Changes[0])
+	assert.False(t, events[0].Time.IsZero())
+
+	// Rejected and no-op changes publish nothing.
+	assert.Error(t, cfg.Set("server.port", -1))
+	require.NoError(t, cfg.SetDefault("server.port", 1))
+	assert.Len(t, events, 4)
+}
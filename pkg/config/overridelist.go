@@ -0,0 +1,31 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// WithOverrides sets keys from key=value pairs, e.g.
+// WithOverrides("server.port=9090", "logging.level=debug"), as CLIs, tests,
+// and container arguments pass them. The pairs go into the runtime override
+// layer, as if given to Set before the first load: they beat every source,
+// survive reloads, and Unset removes them. Values are trimmed strings, like
+// environment variables, and converted by the getters and schema decoding.
+// A pair without "=" or with an empty key makes Load fail.
+func WithOverrides(pairs ...string) Option {
+	return func(cm *ConfigManager) {
+		for _, pair := range pairs {
+			key, value, ok := strings.Cut(pair, "=")
+			key = strings.ToLower(strings.TrimSpace(key))
+			if !ok || key == "" {
+				cm.optionErr = errors.Join(cm.optionErr, fmt.Errorf("invalid override %q: want key=value", pair))
+				continue
+			}
+			if cm.runtime.overrides == nil {
+				cm.runtime.overrides = make(map[string]interface{})
+			}
+			cm.runtime.overrides[key] = strings.TrimSpace(value)
+		}
+	}
+}
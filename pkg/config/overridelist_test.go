@@ -0,0 +1,44 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWithOverrides(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	t.Run("Highest Precedence", func(t *testing.T) {
+		t.Setenv("APP_SERVER_PORT", "7070")
+		cfg := New(configPath, zap.NewNop(), WithEnvPrefix("APP"),
+			WithOverrides("server.port=9090", "Logging.Level = debug"))
+		require.NoError(t, cfg.Load())
+
+		assert.Equal(t, 9090, cfg.GetInt("server.port"))
+		assert.Equal(t, "debug", cfg.GetString("logging.level"))
+		assert.Equal(t, Override, cfg.Origin("server.port"))
+
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 9090, cfg.GetInt("server.port"))
+
+		require.NoError(t, cfg.Unset("server.port"))
+		assert.Equal(t, 7070, cfg.GetInt("server.port"))
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		cfg := New(configPath, zap.NewNop(), WithSchema(&TestConfig{}), WithOverrides("database.maxConns=42"))
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 42, cfg.GetSchema().(*TestConfig).Database.MaxConns)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		cfg := New(configPath, zap.NewNop(), WithOverrides("server.port"))
+		err := cfg.Load()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid override "server.port"`)
+	})
+}
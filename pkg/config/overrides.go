@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"text/template"
+
+	"go.uber.org/zap"
+)
+
+// WithOverrideFiles merges the given files on top of the config file, in
+// order, so the classic config.yaml plus config.local.yaml layout needs a
+// single manager. Each file is optional: a missing one is skipped, and
+// creating it later is picked up by the watcher, which covers all of them.
+// Override files are part of the File source, and a remote manager merges
+// them onto its local fallback file.
+func WithOverrideFiles(paths ...string) Option {
+	return func(cm *ConfigManager) {
+		for _, p := range paths {
+			cm.overrideFiles = append(cm.overrideFiles, p)
+			cm.watchTargets.addFile(p)
+		}
+	}
+}
+
+// overrideFile is the content read from an override file.
+type overrideFile struct {
+	path string
+	// data is nil if the file does not exist.
+	data []byte
+}
+
+// readOverrideFiles reads the override files at paths.
+func readOverrideFiles(paths []string) ([]overrideFile, error) {
+	files := make([]overrideFile, 0, len(paths))
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("error reading override file %s: %w", p, err)
+		}
+		files = append(files, overrideFile{path: p, data: data})
+	}
+	return files, nil
+}
+
+// overrideContent returns the content of files, for change detection.
+func overrideContent(files []overrideFile) [][]byte {
+	parts := make([][]byte, len(files))
+	for i, f := range files {
+		parts[i] = f.data
+	}
+	return parts
+}
+
+// mergeOverrideFiles decodes the existing override files and merges them
+// onto settings in order, following the merge policy.
+func mergeOverrideFiles(logger logSink, settings map[string]interface{}, files []overrideFile,
+	configType, delimiter string, jsonnet *JsonnetOptions, funcs template.FuncMap, profile string, targets *watchRegistry, policy *mergePolicy,
+	trace *fileTrace) (map[string]interface{}, error) {
+	merged := lowerKeys(settings)
+	for _, f := range files {
+		if f.data == nil {
+			continue
+		}
+		override, err := decodeConfigFile(logger, f.path, f.data, configType, delimiter, jsonnet, funcs, profile, targets, trace)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding override file %s: %w", f.path, err)
+		}
+		policy.merge(merged, lowerKeys(override))
+		logger.Debug("Merged override file", zap.String("path", f.path))
+	}
+	return merged, nil
+}
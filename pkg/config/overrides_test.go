@@ -0,0 +1,71 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestOverrideFiles(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+	dir := filepath.Dir(configPath)
+	local := filepath.Join(dir, "config.local.yaml")
+	prod := filepath.Join(dir, "config.prod.json")
+	missing := filepath.Join(dir, "config.missing.yaml")
+
+	require.NoError(t, os.WriteFile(local, []byte("server:\n  port: 9090\ncache:\n  ttl: 1m\n"), 0644))
+	require.NoError(t, os.WriteFile(prod, []byte(`{"server": {"port": 443}, "database": {"maxConns": 50}}`), 0644))
+
+	t.Run("Merged In Order", func(t *testing.T) {
+		cfg := New(configPath, zap.NewNop(), WithOverrideFiles(local, missing, prod))
+		require.NoError(t, cfg.Load())
+
+		assert.Equal(t, 443, cfg.GetInt("server.port"))
+		assert.Equal(t, "localhost", cfg.GetString("server.host"))
+		assert.Equal(t, "1m", cfg.GetString("cache.ttl"))
+		assert.Equal(t, 50, cfg.GetInt("database.maxconns"))
+		assert.Equal(t, "testdb", cfg.GetString("database.name"))
+	})
+
+	t.Run("Above File Below Env", func(t *testing.T) {
+		t.Setenv("APP_SERVER_PORT", "8443")
+		cfg := New(configPath, zap.NewNop(), WithEnvPrefix("APP"), WithOverrideFiles(local))
+		require.NoError(t, cfg.Load())
+
+		assert.Equal(t, 8443, cfg.GetInt("server.port"))
+		assert.Contains(t, cfg.Describe(), KeyInfo{Key: "cache.ttl", Type: "string", Source: File, Origin: local})
+	})
+
+	t.Run("Invalid Override", func(t *testing.T) {
+		bad := filepath.Join(dir, "config.bad.yaml")
+		require.NoError(t, os.WriteFile(bad, []byte("server: [port"), 0644))
+		cfg := New(configPath, zap.NewNop(), WithOverrideFiles(bad))
+		err := cfg.Load()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), bad)
+	})
+
+	t.Run("Watched", func(t *testing.T) {
+		cfg := New(configPath, zap.NewNop(), WithOverrideFiles(missing))
+		defer cfg.Close()
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+
+		changes := make(chan struct{}, 10)
+		require.NoError(t, cfg.Watch(context.Background(), func() { changes <- struct{}{} }))
+		require.NoError(t, writeFileAtomic(missing, []byte("server:\n  port: 7070\n")))
+		select {
+		case <-changes:
+		case <-time.After(2 * time.Second):
+			t.Fatal("timeout waiting for config change")
+		}
+		assert.Equal(t, 7070, cfg.GetInt("server.port"))
+	})
+}
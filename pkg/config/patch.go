@@ -0,0 +1,91 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+)
+
+// PatchKind selects the patch format accepted by Patch.
+type PatchKind int
+
+const (
+	// JSONPatch is an RFC 6902 JSON Patch, a list of add/remove/replace/
+	// move/copy/test operations.
+	JSONPatch PatchKind = iota
+	// MergePatch is an RFC 7386 JSON Merge Patch, a partial document in
+	// which null removes a key.
+	MergePatch
+)
+
+func (k PatchKind) String() string {
+	switch k {
+	case JSONPatch:
+		return "json-patch"
+	case MergePatch:
+		return "merge-patch"
+	}
+	return "unknown"
+}
+
+// Patch applies patch to the effective configuration. The changed keys are
+// written to the runtime override layer and removed keys are hidden from
+// every source, so the result survives reloads like Set does. The patched
+// configuration is validated before it is swapped in; if it is rejected,
+// nothing changes. Patch paths use the lowercased keys of AllSettings.
+func (cm *ConfigManager) Patch(patch []byte, kind PatchKind) error {
+	return cm.updateRuntime(kind.String(), func(r *runtimeLayers) error {
+		doc, err := json.Marshal(cm.viper.AllSettings())
+		if err != nil {
+			return fmt.Errorf("error encoding configuration: %w", err)
+		}
+		patched, err := applyPatch(doc, patch, kind)
+		if err != nil {
+			return fmt.Errorf("error applying %s: %w", kind, err)
+		}
+
+		var before, after map[string]interface{}
+		if err := json.Unmarshal(doc, &before); err != nil {
+			return err
+		}
+		if err := json.Unmarshal(patched, &after); err != nil {
+			return fmt.Errorf("patched configuration is not an object: %w", err)
+		}
+		after = lowerKeys(after)
+
+		for _, key := range leafKeys(after, "", cm.keyDelimiter) {
+			path := cm.splitKey(key)
+			newValue, _ := lookupPath(after, path)
+			if oldValue, ok := lookupPath(before, path); ok && reflect.DeepEqual(oldValue, newValue) {
+				continue
+			}
+			r.overrides[key] = newValue
+			delete(r.masked, key)
+			delete(r.expiries, key)
+		}
+		for _, key := range leafKeys(before, "", cm.keyDelimiter) {
+			if _, ok := lookupPath(after, cm.splitKey(key)); !ok {
+				r.masked[key] = true
+				delete(r.overrides, key)
+				delete(r.expiries, key)
+			}
+		}
+		return nil
+	})
+}
+
+func applyPatch(doc, patch []byte, kind PatchKind) ([]byte, error) {
+	switch kind {
+	case JSONPatch:
+		p, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, err
+		}
+		return p.Apply(doc)
+	case MergePatch:
+		return jsonpatch.MergePatch(doc, patch)
+	}
+	return nil, fmt.Errorf("unknown patch kind %d", kind)
+}
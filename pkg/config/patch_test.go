@@ -0,0 +1,66 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPatch(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+
+	t.Run("JSON Patch", func(t *testing.T) {
+		configPath, cleanup := setupTestConfig(t)
+		defer cleanup()
+
+		cfg := New(configPath, logger)
+		require.NoError(t, cfg.Load())
+
+		require.NoError(t, cfg.Patch([]byte(`[
+			{"op": "test", "path": "/server/port", "value": 8080},
+			{"op": "replace", "path": "/server/port", "value": 9090},
+			{"op": "remove", "path": "/database/maxconns"},
+			{"op": "add", "path": "/cache", "value": {"ttl": "1m"}}
+		]`), JSONPatch))
+
+		assert.Equal(t, 9090, cfg.GetInt("server.port"))
+		assert.False(t, cfg.IsSet("database.maxconns"))
+		assert.Equal(t, "1m", cfg.GetString("cache.ttl"))
+
+		// The patch survives a reload from the unchanged file.
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 9090, cfg.GetInt("server.port"))
+		assert.False(t, cfg.IsSet("database.maxconns"))
+		assert.Equal(t, "testdb", cfg.GetString("database.name"))
+	})
+
+	t.Run("Merge Patch", func(t *testing.T) {
+		configPath, cleanup := setupTestConfig(t)
+		defer cleanup()
+
+		cfg := New(configPath, logger)
+		require.NoError(t, cfg.Load())
+
+		require.NoError(t, cfg.Patch([]byte(`{"server": {"host": "example.com", "timeout": null}}`), MergePatch))
+		assert.Equal(t, "example.com", cfg.GetString("server.host"))
+		assert.False(t, cfg.IsSet("server.timeout"))
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+	})
+
+	t.Run("Rejected Patch Changes Nothing", func(t *testing.T) {
+		configPath, cleanup := setupTestConfig(t)
+		defer cleanup()
+
+		cfg := New(configPath, logger, WithSchema(&TestConfig{}))
+		require.NoError(t, cfg.Load())
+
+		assert.Error(t, cfg.Patch([]byte(`{"server": {"port": 0}}`), MergePatch))
+		assert.Error(t, cfg.Patch([]byte(`[{"op": "test", "path": "/server/port", "value": 1}]`), JSONPatch))
+		assert.Error(t, cfg.Patch([]byte(`not json`), JSONPatch))
+
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+	})
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// watchGate defers watcher-triggered reloads while watching is paused.
+type watchGate struct {
+	mu      sync.Mutex
+	paused  bool
+	nextID  int
+	pending map[int]func()
+}
+
+// gate wraps a watcher trigger so that, while paused, it is remembered
+// instead of run. Each trigger is remembered once, however many changes
+// arrive, since the reload it runs reads the latest content anyway.
+func (cm *ConfigManager) gate(trigger func()) func() {
+	g := &cm.watchGate
+	g.mu.Lock()
+	g.nextID++
+	id := g.nextID
+	g.mu.Unlock()
+
+	return func() {
+		g.mu.Lock()
+		if g.paused {
+			if g.pending == nil {
+				g.pending = make(map[int]func())
+			}
+			g.pending[id] = trigger
+			g.mu.Unlock()
+			cm.logger.Debug("Watching paused, deferring reload")
+			return
+		}
+		g.mu.Unlock()
+		trigger()
+	}
+}
+
+// PauseWatch suspends reloads triggered by watchers, e.g. during a
+// migration or while draining, so the live configuration cannot change
+// underneath a critical section. Changes are still detected and the latest
+// one is applied by ResumeWatch. Explicit calls such as Load and Set are
+// not affected.
+func (cm *ConfigManager) PauseWatch() {
+	cm.watchGate.mu.Lock()
+	defer cm.watchGate.mu.Unlock()
+	if !cm.watchGate.paused {
+		cm.watchGate.paused = true
+		cm.logger.Info("Paused configuration watching")
+	}
+}
+
+// ResumeWatch resumes watcher-triggered reloads. If changes arrived while
+// paused, the configuration is reloaded once and the watch callbacks run
+// before ResumeWatch returns.
+func (cm *ConfigManager) ResumeWatch() {
+	g := &cm.watchGate
+	g.mu.Lock()
+	if !g.paused {
+		g.mu.Unlock()
+		return
+	}
+	g.paused = false
+	ids := make([]int, 0, len(g.pending))
+	for id := range g.pending {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	triggers := make([]func(), len(ids))
+	for i, id := range ids {
+		triggers[i] = g.pending[id]
+	}
+	g.pending = nil
+	g.mu.Unlock()
+
+	cm.logger.Info("Resumed configuration watching", zap.Int("pendingReloads", len(triggers)))
+	for _, trigger := range triggers {
+		trigger()
+	}
+}
+
+// WatchPaused reports whether watching is paused.
+func (cm *ConfigManager) WatchPaused() bool {
+	cm.watchGate.mu.Lock()
+	defer cm.watchGate.mu.Unlock()
+	return cm.watchGate.paused
+}
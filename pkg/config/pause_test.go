@@ -0,0 +1,48 @@
+package config
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPauseWatch(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	cfg := New(configPath, zap.NewNop())
+	defer cfg.Close()
+	require.NoError(t, cfg.Load())
+
+	var reloads atomic.Int32
+	require.NoError(t, cfg.Watch(context.Background(), func() { reloads.Add(1) }))
+
+	cfg.PauseWatch()
+	assert.True(t, cfg.WatchPaused())
+
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	for _, port := range []string{"9001", "9002"} {
+		require.NoError(t, writeFileAtomic(configPath, []byte(strings.Replace(string(content), "8080", port, 1))))
+		time.Sleep(50 * time.Millisecond)
+	}
+	assert.Zero(t, reloads.Load())
+	assert.Equal(t, 8080, cfg.GetInt("server.port"), "paused watching must not reload")
+
+	cfg.ResumeWatch()
+	assert.False(t, cfg.WatchPaused())
+	assert.Equal(t, int32(1), reloads.Load(), "pending changes reload once")
+	assert.Equal(t, 9002, cfg.GetInt("server.port"))
+
+	// Resuming without pending changes does nothing.
+	cfg.PauseWatch()
+	cfg.ResumeWatch()
+	assert.Equal(t, int32(1), reloads.Load())
+}
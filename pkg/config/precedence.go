@@ -0,0 +1,146 @@
+package config
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Source identifies a configuration layer whose position in the precedence
+// order decides which value wins when several sources set the same key.
+type Source int
+
+const (
+	// Defaults are the values given through WithDefaults.
+	Defaults Source = iota
+	// File is the local configuration file.
+	File
+	// Remote is the document read from the remote provider.
+	Remote
+	// Env is the set of environment variables under the env prefix.
+	Env
+	// Override is the runtime layer written by Set. It always has the
+	// highest precedence and is not accepted by WithPrecedence.
+	Override
+	// Flags are the command-line flags given through WithFlags.
+	Flags
+)
+
+func (s Source) String() string {
+	switch s {
+	case Defaults:
+		return "defaults"
+	case File:
+		return "file"
+	case Remote:
+		return "remote"
+	case Env:
+		return "env"
+	case Override:
+		return "override"
+	case Flags:
+		return "flags"
+	}
+	return "unknown"
+}
+
+// DefaultPrecedence is the order used when WithPrecedence is not given,
+// highest first.
+var DefaultPrecedence = []Source{Flags, Env, Remote, File, Defaults}
+
+// WithPrecedence sets the order in which sources override each other,
+// highest first, e.g. WithPrecedence(Flags, File, Env, Defaults) to let an
+// image's files beat the environment. Sources left out of the list are not
+// consulted at all.
+//
+// When a remote provider is configured, the local file (if it exists) is
+// read as well, so File can act as a fallback for keys missing remotely.
+func WithPrecedence(sources ...Source) Option {
+	return func(cm *ConfigManager) {
+		cm.precedence = sources
+	}
+}
+
+// layers holds the settings read from each source before they are merged.
+type layers map[Source]map[string]interface{}
+
+// setDefaults stores the defaults layer, expanding keys nested with delim.
+func (l layers) setDefaults(defaults map[string]interface{}, delim string) {
+	settings := make(map[string]interface{})
+	for key, value := range defaults {
+		setPath(settings, strings.Split(strings.ToLower(key), delim), value)
+	}
+	l[Defaults] = settings
+}
+
+// set stores the settings read from src with their keys lowercased, the same
+// way viper stores them.
+func (l layers) set(src Source, settings map[string]interface{}) {
+	l[src] = lowerKeys(settings)
+}
+
+// apply merges the layers into v, lowest precedence first and following the
+// merge policy, and returns the source each leaf key was taken from. The env
+// layer is resolved for every key known to the other layers or bound to a
+// variable; when env has the highest precedence after flags, viper's
+// automatic env lookup is also enabled so keys that exist only in the
+// environment keep resolving.
+func (l layers) apply(v *viper.Viper, precedence []Source, envPrefix string, envBindings map[string]string,
+	delim string, policy *mergePolicy) (map[string]Source, error) {
+	if (envPrefix != "" || len(envBindings) > 0) && slices.Contains(precedence, Env) {
+		l[Env] = l.envLayer(envPrefix, delim, envBindings)
+		if envPrefix != "" && automaticEnv(precedence) {
+			v.SetEnvPrefix(envPrefix)
+			v.SetEnvKeyReplacer(strings.NewReplacer(delim, "_"))
+			v.AutomaticEnv()
+		}
+	}
+
+	merged := make(map[string]interface{})
+	origins := make(map[string]Source)
+	for i := len(precedence) - 1; i >= 0; i-- {
+		if settings, ok := l[precedence[i]]; ok {
+			policy.merge(merged, deepCopyMap(settings))
+			for _, key := range leafKeys(settings, "", delim) {
+				origins[key] = precedence[i]
+			}
+		}
+	}
+	return origins, v.MergeConfigMap(merged)
+}
+
+// automaticEnv reports whether env outranks every source other than flags.
+func automaticEnv(precedence []Source) bool {
+	for _, src := range precedence {
+		if src != Flags {
+			return src == Env
+		}
+	}
+	return false
+}
+
+// envLayer looks up PREFIX_KEY (delimiters replaced by underscores) for every
+// key set by another layer, and the variables bound to keys.
+func (l layers) envLayer(prefix, delim string, bindings map[string]string) map[string]interface{} {
+	known := make(map[string]bool)
+	for src, settings := range l {
+		if src == Env {
+			continue
+		}
+		for _, key := range leafKeys(settings, "", delim) {
+			known[key] = true
+		}
+	}
+	for key := range bindings {
+		known[key] = true
+	}
+
+	env := make(map[string]interface{})
+	for key := range known {
+		if value, _, ok := lookupEnv(key, prefix, delim, bindings); ok {
+			setPath(env, strings.Split(key, delim), value)
+		}
+	}
+	return env
+}
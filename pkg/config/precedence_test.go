@@ -0,0 +1,94 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPrecedence(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	logger, _ := zap.NewDevelopment()
+	defaults := map[string]interface{}{
+		"server.port":       1,
+		"database.maxConns": 99,
+		"cache.ttl":         "1m",
+	}
+
+	t.Run("Default Order", func(t *testing.T) {
+		t.Setenv("APP_SERVER_PORT", "9090")
+		t.Setenv("APP_DATABASE_MAXCONNS", "20")
+
+		cfg := New(configPath, logger, WithEnvPrefix("APP"), WithDefaults(defaults))
+		require.NoError(t, cfg.Load())
+
+		assert.Equal(t, 9090, cfg.GetInt("server.port"))
+		assert.Equal(t, 20, cfg.GetInt("database.maxconns"))
+		assert.Equal(t, "localhost", cfg.GetString("server.host"))
+		assert.Equal(t, "1m", cfg.GetString("cache.ttl"))
+	})
+
+	t.Run("File Above Env", func(t *testing.T) {
+		t.Setenv("APP_SERVER_PORT", "9090")
+		t.Setenv("APP_CACHE_TTL", "5m")
+
+		cfg := New(configPath, logger, WithEnvPrefix("APP"), WithDefaults(defaults),
+			WithPrecedence(File, Env, Defaults))
+		require.NoError(t, cfg.Load())
+
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+		assert.Equal(t, "5m", cfg.GetString("cache.ttl"))
+	})
+
+	t.Run("Omitted Source Is Ignored", func(t *testing.T) {
+		t.Setenv("APP_SERVER_PORT", "9090")
+
+		cfg := New(configPath, logger, WithEnvPrefix("APP"), WithDefaults(defaults),
+			WithPrecedence(File, Defaults))
+		require.NoError(t, cfg.Load())
+
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+	})
+}
+
+func TestRemotePrecedence(t *testing.T) {
+	remote := useFakeRemote(t)
+	remote.set("app/config", []byte(`{"server": {"port": 8080}, "database": {"maxConns": 5}}`))
+
+	t.Run("Env Keys Use Underscores", func(t *testing.T) {
+		t.Setenv("APP_SERVER_PORT", "9090")
+
+		cfg := newRemoteTestConfig(WithEnvPrefix("APP"))
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 9090, cfg.GetInt("server.port"))
+	})
+
+	t.Run("File Fallback", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("server:\n  port: 7070\n  host: filehost\n"), 0644))
+
+		cfg := New(path, zap.NewNop(), WithRemoteProvider(&RemoteProvider{
+			Type:     "consul",
+			Endpoint: "localhost:8500",
+			Path:     "app/config",
+		}))
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+		assert.Equal(t, "filehost", cfg.GetString("server.host"))
+		assert.Equal(t, 5, cfg.GetInt("database.maxconns"))
+
+		cfg = New(path, zap.NewNop(), WithPrecedence(File, Remote), WithRemoteProvider(&RemoteProvider{
+			Type:     "consul",
+			Endpoint: "localhost:8500",
+			Path:     "app/config",
+		}))
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 7070, cfg.GetInt("server.port"))
+	})
+}
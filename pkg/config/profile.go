@@ -0,0 +1,56 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// WithProfile selects an environment profile such as "dev", "staging", or
+// "prod": the profile file next to the config file, named after it with
+// the profile before the extension (config.prod.yaml for config.yaml), is
+// merged on top of it like a file given to WithOverrideFiles, before any of
+// those. With WithEnvPrefix, the PREFIX_PROFILE environment variable, e.g.
+// APP_PROFILE, selects the profile instead when set, so one binary carries
+// every environment's deltas.
+func WithProfile(profile string) Option {
+	return func(cm *ConfigManager) {
+		cm.profile = profile
+	}
+}
+
+// Profile returns the active profile, or "" if none is selected.
+func (cm *ConfigManager) Profile() string {
+	return cm.profile
+}
+
+// profileEnvSuffix names the environment variable selecting the profile.
+const profileEnvSuffix = "_PROFILE"
+
+// resolveProfile applies the environment's profile selection and registers
+// the profile file as the first override file. It runs once, after options.
+func (cm *ConfigManager) resolveProfile() {
+	if cm.envPrefix != "" {
+		if profile, ok := os.LookupEnv(strings.ToUpper(cm.envPrefix) + profileEnvSuffix); ok {
+			cm.profile = profile
+		}
+	}
+	if cm.profile == "" {
+		return
+	}
+
+	path := profilePath(cm.path, cm.profile)
+	cm.overrideFiles = append([]string{path}, cm.overrideFiles...)
+	cm.watchTargets.addFile(path)
+	cm.logger.Info("Using configuration profile",
+		zap.String("profile", cm.profile),
+		zap.String("path", path))
+}
+
+// profilePath returns the profile file for the config file at path.
+func profilePath(path, profile string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + profile + ext
+}
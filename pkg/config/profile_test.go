@@ -0,0 +1,54 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestProfile(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+	dir := filepath.Dir(configPath)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.prod.yaml"), []byte("server:\n  port: 443\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.staging.yaml"), []byte("server:\n  port: 8443\n"), 0644))
+
+	t.Run("Selected By Option", func(t *testing.T) {
+		cfg := New(configPath, zap.NewNop(), WithProfile("prod"))
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, "prod", cfg.Profile())
+		assert.Equal(t, 443, cfg.GetInt("server.port"))
+		assert.Equal(t, "localhost", cfg.GetString("server.host"))
+	})
+
+	t.Run("Selected By Environment", func(t *testing.T) {
+		t.Setenv("APP_PROFILE", "staging")
+		cfg := New(configPath, zap.NewNop(), WithEnvPrefix("APP"), WithProfile("prod"))
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, "staging", cfg.Profile())
+		assert.Equal(t, 8443, cfg.GetInt("server.port"))
+	})
+
+	t.Run("Below Override Files", func(t *testing.T) {
+		local := filepath.Join(dir, "config.local.yaml")
+		require.NoError(t, os.WriteFile(local, []byte("server:\n  port: 9999\n"), 0644))
+		cfg := New(configPath, zap.NewNop(), WithOverrideFiles(local), WithProfile("prod"))
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 9999, cfg.GetInt("server.port"))
+	})
+
+	t.Run("Missing Profile File", func(t *testing.T) {
+		cfg := New(configPath, zap.NewNop(), WithProfile("dev"))
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+	})
+
+	t.Run("Profile Path", func(t *testing.T) {
+		assert.Equal(t, "/etc/app/config.prod.yaml", profilePath("/etc/app/config.yaml", "prod"))
+		assert.Equal(t, "/etc/app/config.prod", profilePath("/etc/app/config", "prod"))
+	})
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// ErrRevisionMismatch is returned by Push when the remote document no longer
+// has the expected revision.
+var ErrRevisionMismatch = errors.New("remote revision mismatch")
+
+// RemoteWriter is implemented by remote backends that can store documents.
+// If ifRevision is non-empty, Put must atomically check that the stored
+// document still has that revision (the hex SHA-256 of its content, as
+// returned by RemoteRevision; a missing key counts as empty) and return
+// ErrRevisionMismatch otherwise.
+type RemoteWriter interface {
+	Put(ctx context.Context, rp *RemoteProvider, data []byte, ifRevision string) error
+}
+
+// PushOption configures a Push.
+type PushOption func(*pushOptions)
+
+type pushOptions struct {
+	revision    string
+	annotations Annotations
+}
+
+// IfRevision makes Push fail with ErrRevisionMismatch unless the remote
+// document still has the given revision, e.g. the one returned by
+// RemoteRevision after the last load.
+func IfRevision(revision string) PushOption {
+	return func(o *pushOptions) {
+		o.revision = revision
+	}
+}
+
+// WithPushAnnotations attributes the pushed version through the
+// AnnotationsKey envelope.
+func WithPushAnnotations(a Annotations) PushOption {
+	return func(o *pushOptions) {
+		o.annotations = a
+	}
+}
+
+// documentRevision returns the revision of a remote document: the hex
+// SHA-256 of its content.
+func documentRevision(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// RemoteRevision returns the revision of the remote document applied by the
+// last load, or "" without a remote provider.
+func (cm *ConfigManager) RemoteRevision() string {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	if r, ok := cm.provider.(*RemoteConfigProvider); ok {
+		return r.revision
+	}
+	return ""
+}
+
+// Push serializes the effective settings in the remote provider's format
+// and writes them to its path, for config-admin services built on gobits.
+// The backend must implement RemoteWriter; consul, etcd3, and redis do.
+func (cm *ConfigManager) Push(ctx context.Context, opts ...PushOption) error {
+	if cm.remoteProvider == nil {
+		return errors.New("no remote provider configured")
+	}
+	var o pushOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	backend, err := lookupRemoteBackend(cm.remoteProvider.Type)
+	if err != nil {
+		return err
+	}
+	writer, ok := backend.(RemoteWriter)
+	if !ok {
+		return fmt.Errorf("remote provider type '%s' does not support writes", cm.remoteProvider.Type)
+	}
+	enc, err := lookupEncoder(cm.remoteProvider.format())
+	if err != nil {
+		return err
+	}
+
+	settings := cm.unresolvedSettings()
+	if !o.annotations.IsZero() {
+		settings[AnnotationsKey] = map[string]interface{}{
+			"author":  o.annotations.Author,
+			"ticket":  o.annotations.Ticket,
+			"message": o.annotations.Message,
+		}
+	}
+	data, err := enc.Encode(settings)
+	if err != nil {
+		return err
+	}
+
+	if err := writer.Put(ctx, cm.remoteProvider, data, o.revision); err != nil {
+		cm.logger.Error("Failed to push remote config",
+			zap.String("type", cm.remoteProvider.Type),
+			zap.String("path", cm.remoteProvider.Path),
+			zap.Error(err))
+		return err
+	}
+	cm.logger.Info("Pushed configuration to remote provider",
+		zap.String("type", cm.remoteProvider.Type),
+		zap.String("path", cm.remoteProvider.Path),
+		zap.String("revision", documentRevision(data)))
+	return nil
+}
@@ -0,0 +1,317 @@
+package config
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// kvStore is a versioned in-memory key/value store behind the fake servers.
+type kvStore struct {
+	mu       sync.Mutex
+	values   map[string][]byte
+	versions map[string]uint64
+	version  uint64
+}
+
+func newKVStore() *kvStore {
+	return &kvStore{values: make(map[string][]byte), versions: make(map[string]uint64)}
+}
+
+func (s *kvStore) get(key string) ([]byte, uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.values[key], s.versions[key]
+}
+
+// put stores value if the key's version equals cas, or unconditionally if
+// cas is negative.
+func (s *kvStore) put(key string, value []byte, cas int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cas >= 0 && s.versions[key] != uint64(cas) {
+		return false
+	}
+	s.version++
+	s.values[key] = value
+	s.versions[key] = s.version
+	return true
+}
+
+func newConsulServer(store *kvStore) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.URL.Path, "/v1/kv/")
+		switch r.Method {
+		case http.MethodGet:
+			value, index := store.get(key)
+			if value == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			json.NewEncoder(w).Encode([]map[string]interface{}{{"ModifyIndex": index, "Value": value}})
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			cas := int64(-1)
+			if c := r.URL.Query().Get("cas"); c != "" {
+				cas, _ = strconv.ParseInt(c, 10, 64)
+			}
+			json.NewEncoder(w).Encode(store.put(key, body, cas))
+		}
+	}))
+}
+
+func newEtcdServer(store *kvStore) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]json.RawMessage
+		json.NewDecoder(r.Body).Decode(&req)
+		decode := func(raw json.RawMessage) string {
+			var s string
+			json.Unmarshal(raw, &s)
+			b, _ := base64.StdEncoding.DecodeString(s)
+			return string(b)
+		}
+
+		switch r.URL.Path {
+		case "/v3/kv/range":
+			value, rev := store.get(decode(req["key"]))
+			var kvs []map[string]interface{}
+			if value != nil {
+				kvs = append(kvs, map[string]interface{}{"mod_revision": strconv.FormatUint(rev, 10), "value": value})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"kvs": kvs})
+		case "/v3/kv/put":
+			store.put(decode(req["key"]), []byte(decode(req["value"])), -1)
+			w.Write([]byte("{}"))
+		case "/v3/kv/txn":
+			var txn struct {
+				Compare []struct {
+					Key         string `json:"key"`
+					ModRevision string `json:"mod_revision"`
+				} `json:"compare"`
+				Success []struct {
+					RequestPut struct {
+						Key   string `json:"key"`
+						Value string `json:"value"`
+					} `json:"request_put"`
+				} `json:"success"`
+			}
+			raw, _ := json.Marshal(req)
+			json.Unmarshal(raw, &txn)
+			key, _ := base64.StdEncoding.DecodeString(txn.Compare[0].Key)
+			value, _ := base64.StdEncoding.DecodeString(txn.Success[0].RequestPut.Value)
+			cas, _ := strconv.ParseInt(txn.Compare[0].ModRevision, 10, 64)
+			json.NewEncoder(w).Encode(map[string]bool{"succeeded": store.put(string(key), value, cas)})
+		}
+	}))
+}
+
+// newRedisServer serves GET, SET, WATCH, MULTI and EXEC over RESP.
+func newRedisServer(t *testing.T, store *kvStore) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveRedis(conn, store)
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func serveRedis(conn net.Conn, store *kvStore) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	watched := map[string]uint64{}
+	var queued [][]string
+	inMulti := false
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		args := make([]string, n)
+		for i := range args {
+			header, _ := r.ReadString('\n')
+			size, _ := strconv.Atoi(strings.TrimSpace(header[1:]))
+			arg := make([]byte, size+2)
+			if _, err := io.ReadFull(r, arg); err != nil {
+				return
+			}
+			args[i] = string(arg[:size])
+		}
+
+		bulk := func(v []byte) string {
+			if v == nil {
+				return "$-1\r\n"
+			}
+			return "$" + strconv.Itoa(len(v)) + "\r\n" + string(v) + "\r\n"
+		}
+		switch cmd := strings.ToUpper(args[0]); {
+		case cmd == "MULTI":
+			inMulti = true
+			io.WriteString(conn, "+OK\r\n")
+		case inMulti && cmd != "EXEC":
+			queued = append(queued, args)
+			io.WriteString(conn, "+QUEUED\r\n")
+		case cmd == "WATCH":
+			_, watched[args[1]] = store.get(args[1])
+			io.WriteString(conn, "+OK\r\n")
+		case cmd == "EXEC":
+			aborted := false
+			for key, version := range watched {
+				if _, current := store.get(key); current != version {
+					aborted = true
+				}
+			}
+			if aborted {
+				io.WriteString(conn, "*-1\r\n")
+			} else {
+				for _, q := range queued {
+					store.put(q[1], []byte(q[2]), -1)
+				}
+				io.WriteString(conn, "*"+strconv.Itoa(len(queued))+"\r\n")
+				for range queued {
+					io.WriteString(conn, "+OK\r\n")
+				}
+			}
+			inMulti, queued, watched = false, nil, map[string]uint64{}
+		case cmd == "GET":
+			value, _ := store.get(args[1])
+			io.WriteString(conn, bulk(value))
+		case cmd == "SET":
+			store.put(args[1], []byte(args[2]), -1)
+			io.WriteString(conn, "+OK\r\n")
+		default:
+			io.WriteString(conn, "-ERR unknown command\r\n")
+		}
+	}
+}
+
+func TestPush(t *testing.T) {
+	remote := useFakeRemote(t)
+	remote.set("app/config", []byte(`{"server": {"port": 8080}}`))
+
+	newPushConfig := func(typ, endpoint string) *ConfigManager {
+		cfg := New("", zap.NewNop(), WithRemoteProvider(&RemoteProvider{
+			Type:     typ,
+			Endpoint: endpoint,
+			Path:     "app/config",
+		}))
+		require.NoError(t, cfg.Load())
+		require.NoError(t, cfg.Set("server.port", 9090))
+		return cfg
+	}
+
+	check := func(t *testing.T, cfg *ConfigManager, store *kvStore) {
+		ctx := context.Background()
+		require.NoError(t, cfg.Push(ctx, WithPushAnnotations(Annotations{Author: "admin"})))
+
+		stored, _ := store.get("app/config")
+		var doc map[string]interface{}
+		require.NoError(t, json.Unmarshal(stored, &doc))
+		assert.Equal(t, float64(9090), doc["server"].(map[string]interface{})["port"])
+		assert.Equal(t, "admin", doc[AnnotationsKey].(map[string]interface{})["author"])
+
+		assert.ErrorIs(t, cfg.Push(ctx, IfRevision(documentRevision([]byte("stale")))), ErrRevisionMismatch)
+		require.NoError(t, cfg.Push(ctx, IfRevision(documentRevision(stored))))
+	}
+
+	t.Run("Consul", func(t *testing.T) {
+		store := newKVStore()
+		srv := newConsulServer(store)
+		defer srv.Close()
+		check(t, newPushConfig("consul", strings.TrimPrefix(srv.URL, "http://")), store)
+	})
+
+	t.Run("Etcd3", func(t *testing.T) {
+		store := newKVStore()
+		srv := newEtcdServer(store)
+		defer srv.Close()
+		check(t, newPushConfig("etcd3", srv.URL), store)
+	})
+
+	t.Run("Redis", func(t *testing.T) {
+		store := newKVStore()
+		store.put("app/config", []byte(`{"server": {"port": 8080}}`), -1)
+		cfg := newPushConfig("redis", newRedisServer(t, store))
+		assert.Equal(t, documentRevision([]byte(`{"server": {"port": 8080}}`)), cfg.RemoteRevision())
+		check(t, cfg, store)
+	})
+
+	t.Run("Redis Canceled", func(t *testing.T) {
+		// The server accepts but never replies.
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		t.Cleanup(func() { ln.Close() })
+		accepted := make(chan net.Conn, 1)
+		go func() {
+			if conn, err := ln.Accept(); err == nil {
+				accepted <- conn
+			}
+		}()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() {
+			_, err := redisBackend{}.Get(ctx, &RemoteProvider{Endpoint: ln.Addr().String(), Path: "app/config"})
+			done <- err
+		}()
+		conn := <-accepted
+		defer conn.Close()
+		// Give the client time to block on the GET reply.
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+		select {
+		case err := <-done:
+			assert.ErrorIs(t, err, context.Canceled)
+		case <-time.After(5 * time.Second):
+			t.Fatal("redis read not unblocked by cancellation")
+		}
+	})
+
+	t.Run("Secret References", func(t *testing.T) {
+		t.Setenv("PROBE_PW", "hunter2")
+		remote.set("app/secret", []byte(`{"database": {"password": "env://PROBE_PW"}}`))
+		store := newKVStore()
+		srv := newConsulServer(store)
+		defer srv.Close()
+		cfg := New("", zap.NewNop(),
+			WithRemoteProvider(&RemoteProvider{Type: "consul", Endpoint: strings.TrimPrefix(srv.URL, "http://"), Path: "app/secret"}),
+			WithSecretResolver("env", EnvSecretResolver{}))
+		require.NoError(t, cfg.Load())
+		require.Equal(t, "hunter2", cfg.GetString("database.password"))
+
+		require.NoError(t, cfg.Push(context.Background()))
+		stored, _ := store.get("app/secret")
+		assert.NotContains(t, string(stored), "hunter2")
+		var doc map[string]interface{}
+		require.NoError(t, json.Unmarshal(stored, &doc))
+		assert.Equal(t, "env://PROBE_PW", doc["database"].(map[string]interface{})["password"])
+	})
+
+	t.Run("Read-Only Provider", func(t *testing.T) {
+		cfg := newPushConfig("nats", "localhost:4222")
+		assert.ErrorContains(t, cfg.Push(context.Background()), "does not support writes")
+	})
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"path"
+	"reflect"
+	"strings"
+)
+
+// RedactedValue replaces secret values in redacted output.
+const RedactedValue = "[REDACTED]"
+
+// WithSecretKeys marks keys whose values must be masked in exported or
+// dumped configuration, in addition to schema fields tagged secret:"true". Patterns are dotted keys in which "*" matches a
+// single segment, e.g. "database.password" or "*.token"; a pattern that
+// matches a parent key masks the whole subtree.
+func WithSecretKeys(patterns ...string) Option {
+	return func(cm *ConfigManager) {
+		for _, p := range patterns {
+			cm.secretKeys = append(cm.secretKeys, strings.ToLower(p))
+		}
+	}
+}
+
+// AllSettingsRedacted returns all settings with secret values replaced by
+// RedactedValue. Secrets are the keys matched by WithSecretKeys, the keys
+// holding WithSecretResolver secrets, and the fields of the schema,
+// registered sections, and bound structs tagged secret:"true". Use it
+// instead of AllSettings whenever settings are logged or displayed. Like
+// AllSettings, it returns a deep copy.
+func (cm *ConfigManager) AllSettingsRedacted() map[string]interface{} {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.redact(deepCopyMap(cm.viper.AllSettings()))
+}
+
+// redact masks the secrets in settings. The caller must hold cm.mu.
+func (cm *ConfigManager) redact(settings map[string]interface{}) map[string]interface{} {
+	return redactSettings(settings, cm.secretPatterns(), "", cm.keyDelimiter)
+}
+
+// secretPatterns returns the configured secret key patterns plus the paths
+// of tagged schema, section, and bound struct fields and of resolved secret
+// references. The caller must hold cm.mu.
+func (cm *ConfigManager) secretPatterns() []string {
+	patterns := append(append([]string(nil), cm.secretKeys...), cm.resolvedSecrets...)
+	if cm.schema != nil {
+		patterns = append(patterns, taggedSecrets(reflect.TypeOf(cm.schema), "", cm.keyDelimiter)...)
+	}
+	for _, sec := range cm.sections {
+		patterns = append(patterns, taggedSecrets(reflect.TypeOf(sec.schema), sec.key+cm.keyDelimiter, cm.keyDelimiter)...)
+	}
+	for _, b := range cm.bindings {
+		patterns = append(patterns, taggedSecrets(reflect.TypeOf(b.ptr), "", cm.keyDelimiter)...)
+	}
+	return patterns
+}
+
+// taggedSecrets returns the dotted keys of struct fields tagged
+// secret:"true", named the way viper decodes them: by mapstructure tag, or
+// by field name when untagged. Squashed embedded structs share their
+// parent's prefix.
+func taggedSecrets(t reflect.Type, prefix, delim string) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("mapstructure"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if f.Anonymous && strings.Contains(opts, "squash") {
+			keys = append(keys, taggedSecrets(f.Type, prefix, delim)...)
+			continue
+		}
+		key := prefix + strings.ToLower(name)
+		if f.Tag.Get("secret") == "true" {
+			keys = append(keys, key)
+			continue
+		}
+		keys = append(keys, taggedSecrets(f.Type, key+delim, delim)...)
+	}
+	return keys
+}
+
+// isSecret reports whether key, or a parent of it, matches one of the
+// patterns, both nested with delim.
+func isSecret(key string, patterns []string, delim string) bool {
+	key = strings.ReplaceAll(key, delim, "/")
+	for _, p := range patterns {
+		p = strings.ReplaceAll(p, delim, "/")
+		for k := key; ; k = path.Dir(k) {
+			if ok, _ := path.Match(p, k); ok {
+				return true
+			}
+			if !strings.Contains(k, "/") {
+				break
+			}
+		}
+	}
+	return false
+}
+
+// redactSettings returns a copy of settings with secret values replaced by
+// RedactedValue. Parents are checked before their children, so a matching
+// parent masks its whole subtree.
+func redactSettings(settings map[string]interface{}, patterns []string, prefix, delim string) map[string]interface{} {
+	out := make(map[string]interface{}, len(settings))
+	for k, v := range settings {
+		key := prefix + k
+		if isSecret(key, patterns, delim) {
+			out[k] = RedactedValue
+			continue
+		}
+		if child, ok := v.(map[string]interface{}); ok {
+			out[k] = redactSettings(child, patterns, key+delim, delim)
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
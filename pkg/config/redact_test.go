@@ -0,0 +1,149 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestExport(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	content = append(content, []byte(`
+secrets:
+  apiKey: "abc"
+  signing:
+    key: "def"
+`)...)
+	require.NoError(t, os.WriteFile(configPath, content, 0644))
+
+	logger, _ := zap.NewDevelopment()
+	cfg := New(configPath, logger, WithSecretKeys("database.name", "secrets"))
+	require.NoError(t, cfg.Load())
+
+	t.Run("JSON Redacted", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, cfg.Export(&buf, "json"))
+
+		var out map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+		assert.Equal(t, RedactedValue, out["database"].(map[string]interface{})["name"])
+		assert.Equal(t, RedactedValue, out["secrets"])
+		assert.Equal(t, "localhost", out["server"].(map[string]interface{})["host"])
+		assert.Equal(t, "testdb", cfg.GetString("database.name"), "live config must not be masked")
+	})
+
+	t.Run("Wildcard Pattern", func(t *testing.T) {
+		cfg := New(configPath, logger, WithSecretKeys("*.host"))
+		require.NoError(t, cfg.Load())
+
+		var buf bytes.Buffer
+		require.NoError(t, cfg.Export(&buf, "yaml"))
+		assert.NotContains(t, buf.String(), "localhost")
+		assert.NotContains(t, buf.String(), "127.0.0.1")
+		assert.Contains(t, buf.String(), "testdb")
+	})
+
+	t.Run("Unsupported Format", func(t *testing.T) {
+		assert.Error(t, cfg.Export(&bytes.Buffer{}, "ini"))
+	})
+}
+
+func TestAllSettingsRedacted(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+server:
+  host: "localhost"
+database:
+  name: "testdb"
+  password: "hunter2"
+cache:
+  token: "t0ken"
+`), 0644))
+
+	type databaseConfig struct {
+		Password string `mapstructure:"password" secret:"true"`
+	}
+	type cacheConfig struct {
+		Token string `secret:"true"`
+	}
+	type appConfig struct {
+		Database databaseConfig `mapstructure:"database"`
+	}
+
+	core, logs := observer.New(zap.DebugLevel)
+	cfg := New(configPath, zap.New(core), WithSchema(&appConfig{}), WithSecretKeys("server.host"))
+	cfg.RegisterSection("cache", &cacheConfig{})
+	require.NoError(t, cfg.Load())
+
+	redacted := cfg.AllSettingsRedacted()
+	assert.Equal(t, RedactedValue, redacted["database"].(map[string]interface{})["password"])
+	assert.Equal(t, RedactedValue, redacted["cache"].(map[string]interface{})["token"])
+	assert.Equal(t, RedactedValue, redacted["server"].(map[string]interface{})["host"])
+	assert.Equal(t, "testdb", redacted["database"].(map[string]interface{})["name"])
+	assert.Equal(t, "hunter2", cfg.GetString("database.password"))
+
+	for _, entry := range logs.All() {
+		assert.NotContains(t, fmt.Sprint(entry.ContextMap()), "hunter2", "secret leaked in log %q", entry.Message)
+	}
+}
+
+func TestSecretChanges(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	type credentials struct {
+		Database struct {
+			Name string `mapstructure:"name" secret:"true"`
+		} `mapstructure:"database"`
+	}
+	var creds credentials
+	cfg := New(configPath, zap.NewNop(), WithSecretKeys("server"))
+	_, err := cfg.Bind(&creds)
+	require.NoError(t, err)
+	require.NoError(t, cfg.Load())
+
+	require.NoError(t, cfg.Set("database.name", "hunter2"))
+	require.NoError(t, cfg.Set("server.port", 9090))
+	require.NoError(t, cfg.Set("database.port", 6432))
+	assert.Equal(t, []Change{{Key: "database.port", Old: 5432, New: 6432, Source: Override}}, cfg.Diff())
+
+	t.Run("Marked", func(t *testing.T) {
+		require.NoError(t, cfg.Update(func(tx *Tx) error {
+			tx.Set("database.name", "s3cret")
+			tx.Set("server.port", 7070)
+			return nil
+		}))
+		assert.Equal(t, []Change{
+			{Key: "database.name", Old: "hunter2", New: "s3cret", Source: Override, Secret: true},
+			{Key: "server.port", Old: 9090, New: 7070, Source: Override, Secret: true},
+		}, cfg.Diff())
+
+		for _, info := range cfg.Describe() {
+			assert.Equal(t, info.Key != "database.port" && info.Key != "database.host" &&
+				info.Key != "database.maxconns", info.Secret, info.Key)
+		}
+		assert.Equal(t, RedactedValue, cfg.AllSettingsRedacted()["database"].(map[string]interface{})["name"])
+	})
+
+	t.Run("Logged Masked", func(t *testing.T) {
+		core, logs := observer.New(zap.InfoLevel)
+		zap.New(core).Info("changed", zap.Objects("changes", cfg.Diff()))
+		fields := fmt.Sprint(logs.All()[0].ContextMap())
+		assert.NotContains(t, fields, "s3cret")
+		assert.Contains(t, fields, RedactedValue)
+		assert.Contains(t, fields, "database.name")
+	})
+}
@@ -0,0 +1,199 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// redisBackend stores each document as a string value under the provider
+// path, read with GET and written with SET. Check-and-set uses WATCH and
+// MULTI/EXEC.
+type redisBackend struct{}
+
+func (redisBackend) Get(ctx context.Context, rp *RemoteProvider) (io.Reader, error) {
+	conn, err := dialRedis(ctx, rp.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	reply, err := conn.do("GET", rp.Path)
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil {
+		return nil, fmt.Errorf("redis key '%s' not found", rp.Path)
+	}
+	return bytes.NewReader(reply.([]byte)), nil
+}
+
+func (redisBackend) Put(ctx context.Context, rp *RemoteProvider, data []byte, ifRevision string) error {
+	conn, err := dialRedis(ctx, rp.Endpoint)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if ifRevision == "" {
+		_, err := conn.do("SET", rp.Path, string(data))
+		return err
+	}
+
+	if _, err := conn.do("WATCH", rp.Path); err != nil {
+		return err
+	}
+	reply, err := conn.do("GET", rp.Path)
+	if err != nil {
+		return err
+	}
+	current, _ := reply.([]byte)
+	if documentRevision(current) != ifRevision {
+		return ErrRevisionMismatch
+	}
+	for _, cmd := range [][]string{{"MULTI"}, {"SET", rp.Path, string(data)}} {
+		if _, err := conn.do(cmd...); err != nil {
+			return err
+		}
+	}
+	// EXEC returns a nil array when the watched key changed.
+	if reply, err = conn.do("EXEC"); err != nil {
+		return err
+	}
+	if reply == nil {
+		return ErrRevisionMismatch
+	}
+	return nil
+}
+
+// redisConn is a minimal RESP client for the handful of commands used above.
+type redisConn struct {
+	net.Conn
+	r *bufio.Reader
+	// ctx is the context of the dial; stop releases the hook closing the
+	// connection when ctx is done.
+	ctx  context.Context
+	stop func() bool
+}
+
+// Close closes the connection and releases its context hook.
+func (c *redisConn) Close() error {
+	c.stop()
+	return c.Conn.Close()
+}
+
+// dialRedis connects to a redis endpoint, either "host:port" or
+// "redis://[:password@]host:port[/db]".
+func dialRedis(ctx context.Context, endpoint string) (*redisConn, error) {
+	addr, password, db := endpoint, "", ""
+	if strings.Contains(endpoint, "://") {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		addr = u.Host
+		password, _ = u.User.Password()
+		db = strings.Trim(u.Path, "/")
+	}
+
+	var d net.Dialer
+	nc, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		nc.SetDeadline(deadline)
+	}
+	// A context without a deadline can still be canceled; closing the
+	// connection then unblocks a pending read or write.
+	conn := &redisConn{Conn: nc, r: bufio.NewReader(nc), ctx: ctx}
+	conn.stop = context.AfterFunc(ctx, func() { nc.Close() })
+
+	if password != "" {
+		if _, err := conn.do("AUTH", password); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	if db != "" {
+		if _, err := conn.do("SELECT", db); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// do sends a command and returns its reply: a string for status replies,
+// int64, []byte or nil for bulk strings, and []interface{} or nil for arrays.
+func (c *redisConn) do(args ...string) (interface{}, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*%d\r\n", len(args))
+	for _, a := range args {
+		fmt.Fprintf(&buf, "$%d\r\n%s\r\n", len(a), a)
+	}
+	if _, err := c.Write(buf.Bytes()); err != nil {
+		return nil, c.err(err)
+	}
+	reply, err := c.readReply()
+	return reply, c.err(err)
+}
+
+// err reports the context's error in place of the I/O error its
+// cancellation caused.
+func (c *redisConn) err(err error) error {
+	if err != nil && c.ctx.Err() != nil {
+		return c.ctx.Err()
+	}
+	return err
+}
+
+func (c *redisConn) readReply() (interface{}, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("redis: empty reply")
+	}
+
+	switch payload := line[1:]; line[0] {
+	case '+':
+		return payload, nil
+	case '-':
+		return nil, fmt.Errorf("redis: %s", payload)
+	case ':':
+		return strconv.ParseInt(payload, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(payload)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, data); err != nil {
+			return nil, err
+		}
+		return data[:n], nil
+	case '*':
+		n, err := strconv.Atoi(payload)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = c.readReply(); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("redis: unexpected reply %q", line)
+}
@@ -0,0 +1,190 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cast"
+	"github.com/spf13/viper"
+)
+
+// ReferenceCycleError is returned by a load whose values reference each
+// other in a cycle.
+type ReferenceCycleError struct {
+	// Cycle lists the keys of the cycle, starting and ending with the same key.
+	Cycle []string
+}
+
+func (e *ReferenceCycleError) Error() string {
+	return "reference cycle: " + strings.Join(e.Cycle, " -> ")
+}
+
+// keyPlaceholder matches $${...} escapes and ${...} placeholders.
+var keyPlaceholder = regexp.MustCompile(`\$\$\{[^}]*\}|\$\{([^}]*)\}`)
+
+// isReference reports whether the placeholder name refers to a config key
+// rather than an environment variable: key references name a nested key,
+// e.g. ${server.host}.
+func isReference(name, delim string) bool {
+	return strings.Contains(name, delim)
+}
+
+// applyReferences resolves the ${key} references in the string values of
+// the merged configuration in v. A value that is a single reference takes
+// the referenced value with its type, e.g. a map or a number; a reference
+// within a longer string is replaced with the value's string form. "$${"
+// escapes a literal "${".
+func (cm *ConfigManager) applyReferences(v *viper.Viper) (*viper.Viper, error) {
+	r := &referenceResolver{
+		settings: v.AllSettings(),
+		delim:    cm.keyDelimiter,
+		state:    make(map[string]resolveState),
+	}
+	keys := make([]string, 0, len(r.settings))
+	for key := range r.settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if _, err := r.resolveKey(key); err != nil {
+			return nil, err
+		}
+	}
+	if !r.changed {
+		return v, nil
+	}
+	rebuilt := cm.newViper()
+	if err := rebuilt.MergeConfigMap(r.settings); err != nil {
+		return nil, err
+	}
+	return rebuilt, nil
+}
+
+type resolveState int
+
+const (
+	resolving resolveState = iota + 1
+	resolved
+)
+
+// referenceResolver resolves the references in settings in place,
+// resolving each referenced key before the value that uses it.
+type referenceResolver struct {
+	settings map[string]interface{}
+	delim    string
+	state    map[string]resolveState
+	stack    []string
+	changed  bool
+}
+
+// resolveKey resolves the value at key and returns it.
+func (r *referenceResolver) resolveKey(key string) (interface{}, error) {
+	path := strings.Split(key, r.delim)
+	value, ok := lookupPath(r.settings, path)
+	if !ok {
+		return nil, nil
+	}
+	switch r.state[key] {
+	case resolved:
+		return value, nil
+	case resolving:
+		for i, k := range r.stack {
+			if k == key {
+				cycle := append(append([]string(nil), r.stack[i:]...), key)
+				return nil, &ReferenceCycleError{Cycle: cycle}
+			}
+		}
+	}
+
+	r.state[key] = resolving
+	r.stack = append(r.stack, key)
+	value, err := r.resolveValue(value, key)
+	r.stack = r.stack[:len(r.stack)-1]
+	if err != nil {
+		return nil, err
+	}
+	setPath(r.settings, path, value)
+	r.state[key] = resolved
+	return value, nil
+}
+
+// resolveValue resolves the references in value, found at key.
+func (r *referenceResolver) resolveValue(value interface{}, key string) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return r.resolveString(v, key)
+	case map[string]interface{}:
+		for k := range v {
+			if _, err := r.resolveKey(key + r.delim + k); err != nil {
+				return nil, err
+			}
+		}
+	case []interface{}:
+		for i, elem := range v {
+			resolved, err := r.resolveValue(elem, fmt.Sprintf("%s[%d]", key, i))
+			if err != nil {
+				return nil, err
+			}
+			v[i] = resolved
+		}
+	}
+	return value, nil
+}
+
+// resolveString resolves the references in s, found at key.
+func (r *referenceResolver) resolveString(s, key string) (interface{}, error) {
+	if m := keyPlaceholder.FindStringSubmatchIndex(s); m != nil && m[0] == 0 && m[1] == len(s) && m[2] >= 0 {
+		if name := s[m[2]:m[3]]; isReference(name, r.delim) {
+			value, err := r.reference(name, key)
+			if err != nil {
+				return nil, err
+			}
+			r.changed = true
+			return deepCopyValue(value), nil
+		}
+	}
+
+	var err error
+	out := keyPlaceholder.ReplaceAllStringFunc(s, func(match string) string {
+		if strings.HasPrefix(match, "$$") {
+			if !isReference(match[3:len(match)-1], r.delim) {
+				return match
+			}
+			r.changed = true
+			return match[1:]
+		}
+		name := match[2 : len(match)-1]
+		if err != nil || !isReference(name, r.delim) {
+			return match
+		}
+		r.changed = true
+		value, rerr := r.reference(name, key)
+		if rerr != nil {
+			err = rerr
+			return match
+		}
+		str, cerr := cast.ToStringE(value)
+		if cerr != nil {
+			err = fmt.Errorf("%s: reference ${%s} is not a scalar value", key, name)
+		}
+		return str
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// reference returns the resolved value of the key name, referenced at key.
+func (r *referenceResolver) reference(name, key string) (interface{}, error) {
+	value, err := r.resolveKey(strings.ToLower(name))
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, fmt.Errorf("%s: undefined reference ${%s}", key, name)
+	}
+	return value, nil
+}
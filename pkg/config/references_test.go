@@ -0,0 +1,72 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestReferences(t *testing.T) {
+	t.Run("Resolves References", func(t *testing.T) {
+		cfg := New(writeInterpolateConfig(t, `
+server:
+  host: example.com
+  port: 8443
+  addr: ${server.host}:${server.port}
+  url: https://${server.addr}/api
+  tls:
+    cert: server.pem
+client:
+  port: ${server.port}
+  tls: ${server.tls}
+  urls:
+    - ${server.url}
+literal: $${server.host}
+`), zap.NewNop())
+		require.NoError(t, cfg.Load())
+
+		assert.Equal(t, "example.com:8443", cfg.GetString("server.addr"))
+		assert.Equal(t, "https://example.com:8443/api", cfg.GetString("server.url"))
+		assert.Equal(t, 8443, cfg.Get("client.port"))
+		assert.Equal(t, "server.pem", cfg.GetString("client.tls.cert"))
+		assert.Equal(t, []string{"https://example.com:8443/api"}, cfg.GetStringSlice("client.urls"))
+		assert.Equal(t, "${server.host}", cfg.GetString("literal"))
+	})
+
+	t.Run("Across Sources", func(t *testing.T) {
+		configPath, cleanup := setupTestConfig(t)
+		defer cleanup()
+		cfg := New(configPath, zap.NewNop(), WithEnvPrefix("APP"),
+			WithDefaults(map[string]interface{}{"server.addr": "${server.host}:${server.port}"}))
+		t.Setenv("APP_SERVER_HOST", "0.0.0.0")
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, "0.0.0.0:8080", cfg.GetString("server.addr"))
+
+		require.NoError(t, cfg.Set("server.port", 9090))
+		assert.Equal(t, "0.0.0.0:9090", cfg.GetString("server.addr"))
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		cfg := New(writeInterpolateConfig(t, `
+a:
+  x: ${b.y}
+b:
+  y: ${c.z}
+c:
+  z: ${a.x}
+`), zap.NewNop())
+		err := cfg.Load()
+
+		var cycle *ReferenceCycleError
+		require.ErrorAs(t, err, &cycle)
+		assert.Equal(t, []string{"a.x", "b.y", "c.z", "a.x"}, cycle.Cycle)
+		assert.EqualError(t, err, "reference cycle: a.x -> b.y -> c.z -> a.x")
+	})
+
+	t.Run("Undefined Reference", func(t *testing.T) {
+		cfg := New(writeInterpolateConfig(t, "server:\n  addr: ${server.hots}:80\n"), zap.NewNop())
+		assert.EqualError(t, cfg.Load(), "server.addr: undefined reference ${server.hots}")
+	})
+}
@@ -0,0 +1,252 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHighFrequencyReload(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	logger, _ := zap.NewDevelopment()
+	cfg := New(configPath, logger, WithHighFrequencyReload())
+	require.NoError(t, cfg.Load())
+	live := cfg.viper
+
+	t.Run("Unchanged Content Is Skipped", func(t *testing.T) {
+		require.NoError(t, cfg.Load())
+		assert.Same(t, live, cfg.viper)
+	})
+
+	t.Run("Changed Content Is Applied", func(t *testing.T) {
+		content, err := os.ReadFile(configPath)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(configPath, append(content, []byte("extra: true\n")...), 0644))
+
+		require.NoError(t, cfg.Load())
+		assert.NotSame(t, live, cfg.viper)
+		assert.True(t, cfg.GetBool("extra"))
+		assert.Equal(t, []Change{{Key: "extra", New: true, Source: File}}, cfg.Diff())
+	})
+
+	t.Run("Diff Against Previous Version", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(configPath, []byte("server:\n  port: 9090\nextra:\n  nested: 1\n"), 0644))
+		require.NoError(t, cfg.Load())
+		keys := make(map[string]Change)
+		for _, c := range cfg.Diff() {
+			keys[c.Key] = c
+		}
+		assert.Equal(t, 9090, keys["server.port"].New)
+		assert.Equal(t, true, keys["extra"].Old)
+		assert.Equal(t, map[string]interface{}{"nested": 1}, keys["extra"].New)
+		assert.Nil(t, keys["extra.nested"].Old)
+		assert.Equal(t, 1, keys["extra.nested"].New)
+		assert.Contains(t, keys, "database.host")
+		assert.Nil(t, keys["database.host"].New)
+
+		require.NoError(t, cfg.Load())
+		assert.Empty(t, cfg.Diff())
+	})
+
+	t.Run("Skipped Reload Allocations", func(t *testing.T) {
+		// A skipped reload allocates for the read and the hash only, however
+		// large the document.
+		allocs := testing.AllocsPerRun(100, func() { _ = cfg.Load() })
+		assert.LessOrEqual(t, allocs, 100.0)
+	})
+
+	t.Run("Rejected Content Is Retried", func(t *testing.T) {
+		schemaCfg := New(configPath, logger, WithHighFrequencyReload(), WithSchema(&TestConfig{}))
+		require.NoError(t, os.WriteFile(configPath, []byte("server:\n  port: -1\n"), 0644))
+		require.Error(t, schemaCfg.Load())
+		// A failed load must not be remembered as applied.
+		require.Error(t, schemaCfg.Load())
+	})
+}
+
+// TestHighFrequencyReloadSoak drives reloads against a constantly changing
+// source with the watcher active and checks that goroutines and heap stay
+// flat. It is opt-in: set GOBITS_SOAK_DURATION (e.g. "10m") to run it.
+func TestHighFrequencyReloadSoak(t *testing.T) {
+	d := os.Getenv("GOBITS_SOAK_DURATION")
+	if d == "" || testing.Short() {
+		t.Skip("set GOBITS_SOAK_DURATION to run the soak test")
+	}
+	duration, err := time.ParseDuration(d)
+	require.NoError(t, err)
+
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	writeVersion := func(i int) {
+		content := fmt.Sprintf(`
+server:
+  port: %d
+  host: "localhost"
+  timeout: "30s"
+database:
+  host: "127.0.0.1"
+  port: 5432
+  name: "testdb"
+  maxConns: 10
+`, 1024+i%1000)
+		require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+	}
+
+	cfg := New(configPath, zap.NewNop(), WithHighFrequencyReload(), WithWatcher(), WithSchema(&TestConfig{}))
+	require.NoError(t, cfg.Load())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, cfg.Watch(ctx, func() {}))
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				_ = cfg.GetInt("server.port")
+			}
+		}
+	}()
+
+	// Warm up before taking the baseline.
+	for i := 0; i < 50; i++ {
+		writeVersion(i)
+		require.NoError(t, cfg.Load())
+	}
+	baseGoroutines, baseHeap := settledStats()
+
+	deadline := time.Now().Add(duration)
+	for i := 0; time.Now().Before(deadline); i++ {
+		// Every other reload sees unchanged content.
+		if i%2 == 0 {
+			writeVersion(i)
+		}
+		require.NoError(t, cfg.Load())
+	}
+
+	close(stop)
+	wg.Wait()
+
+	goroutines, heap := settledStats()
+	assert.LessOrEqual(t, goroutines, baseGoroutines, "goroutines grew during soak")
+	assert.LessOrEqual(t, heap, baseHeap+1<<20, "heap grew during soak")
+}
+
+// settledStats returns the goroutine count and live heap after letting
+// pending fsnotify events drain and forcing a GC.
+func settledStats() (int, uint64) {
+	time.Sleep(200 * time.Millisecond)
+	runtime.GC()
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return runtime.NumGoroutine(), m.HeapAlloc
+}
+
+func TestDebounce(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	cfg := New(configPath, zap.NewNop(), WithDebounce(200*time.Millisecond))
+	defer cfg.Close()
+	require.NoError(t, cfg.Load())
+
+	var reloads atomic.Int32
+	require.NoError(t, cfg.Watch(context.Background(), func() {
+		reloads.Add(1)
+	}))
+
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	for port := 9000; port < 9005; port++ {
+		updated := strings.Replace(string(content), "port: 8080", "port: "+strconv.Itoa(port), 1)
+		require.NoError(t, os.WriteFile(configPath, []byte(updated), 0644))
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	require.Eventually(t, func() bool { return reloads.Load() > 0 }, 2*time.Second, 20*time.Millisecond)
+	time.Sleep(400 * time.Millisecond)
+	assert.Equal(t, int32(1), reloads.Load())
+	assert.Equal(t, 9004, cfg.GetInt("server.port"))
+}
+
+func TestOnReloadError(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	errs := make(chan error, 10)
+	cfg := New(configPath, zap.NewNop(),
+		WithSchema(&TestConfig{}),
+		WithOnReloadError(func(err error) { errs <- err }),
+	)
+	defer cfg.Close()
+	require.NoError(t, cfg.Load())
+	require.NoError(t, cfg.Watch(context.Background(), func() {}))
+
+	require.NoError(t, writeFileAtomic(configPath, []byte("server:\n  port: -1\n")))
+	select {
+	case err := <-errs:
+		assert.Error(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for reload error")
+	}
+	assert.Equal(t, 8080, cfg.GetInt("server.port"), "stale config stays live")
+}
+
+func TestValidationGatedReload(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	schema := &TestConfig{}
+	cfg := New(configPath, zap.NewNop(), WithSchema(schema), WithHighFrequencyReload())
+	defer cfg.Close()
+	require.NoError(t, cfg.Load())
+
+	events := make(chan ChangeEvent, 10)
+	require.NoError(t, cfg.WatchEvents(context.Background(), func(e ChangeEvent) { events <- e }))
+	next := func() ChangeEvent {
+		select {
+		case e := <-events:
+			return e
+		case <-time.After(2 * time.Second):
+			t.Fatal("timeout waiting for change event")
+			return ChangeEvent{}
+		}
+	}
+
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+
+	bad := strings.Replace(string(content), "port: 8080", "port: 70000", 1)
+	require.NoError(t, writeFileAtomic(configPath, []byte(bad)))
+	assert.Error(t, next().Err)
+	assert.Equal(t, 8080, cfg.GetInt("server.port"), "the live config keeps the last valid version")
+	assert.Equal(t, 8080, schema.Server.Port)
+
+	good := strings.Replace(string(content), "port: 8080", "port: 9090", 1)
+	require.NoError(t, writeFileAtomic(configPath, []byte(good)))
+	event := next()
+	require.NoError(t, event.Err)
+	// The diff is against the last applied version, not the rejected one.
+	assert.Equal(t, []Change{{Key: "server.port", Old: 8080, New: 9090, Source: File}}, event.Changes)
+	assert.Equal(t, 9090, schema.Server.Port)
+}
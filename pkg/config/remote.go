@@ -0,0 +1,111 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+// RemoteBackend fetches raw configuration documents from a remote store.
+type RemoteBackend interface {
+	Get(ctx context.Context, rp *RemoteProvider) (io.Reader, error)
+}
+
+// RemoteBackendFunc adapts a function to the RemoteBackend interface.
+type RemoteBackendFunc func(ctx context.Context, rp *RemoteProvider) (io.Reader, error)
+
+// Get calls f(ctx, rp).
+func (f RemoteBackendFunc) Get(ctx context.Context, rp *RemoteProvider) (io.Reader, error) {
+	return f(ctx, rp)
+}
+
+// UnsupportedProviderError is returned when a RemoteProvider names a type
+// that has no registered backend.
+type UnsupportedProviderError struct {
+	Type      string
+	Supported []string
+}
+
+func (e *UnsupportedProviderError) Error() string {
+	return fmt.Sprintf("unsupported remote provider type '%s', supported types are: %s",
+		e.Type, strings.Join(e.Supported, ", "))
+}
+
+var (
+	remoteMu       sync.RWMutex
+	remoteBackends = make(map[string]RemoteBackend)
+)
+
+func init() {
+	for _, name := range viper.SupportedRemoteProviders {
+		remoteBackends[name] = viperRemoteBackend{}
+	}
+	// Stores with a native write API also support Push.
+	remoteBackends["consul"] = writableBackend{viperRemoteBackend{}, consulWriter{}}
+	remoteBackends["etcd3"] = writableBackend{viperRemoteBackend{}, etcd3Writer{}}
+	remoteBackends["redis"] = redisBackend{}
+}
+
+// RegisterRemoteProvider registers backend under the provider type name, so
+// RemoteProvider{Type: name} can be used with WithRemoteProvider. It
+// replaces any backend registered under the same name, including the
+// built-in viper-backed types.
+func RegisterRemoteProvider(name string, backend RemoteBackend) {
+	remoteMu.Lock()
+	defer remoteMu.Unlock()
+	remoteBackends[strings.ToLower(name)] = backend
+}
+
+// SupportedProviders returns the registered remote provider types, sorted.
+func SupportedProviders() []string {
+	remoteMu.RLock()
+	defer remoteMu.RUnlock()
+	return sortedKeys(remoteBackends)
+}
+
+func lookupRemoteBackend(name string) (RemoteBackend, error) {
+	remoteMu.RLock()
+	defer remoteMu.RUnlock()
+	if b, ok := remoteBackends[strings.ToLower(name)]; ok {
+		return b, nil
+	}
+	return nil, &UnsupportedProviderError{Type: name, Supported: sortedKeys(remoteBackends)}
+}
+
+// fetchRemote retrieves the raw document stored at the provider's path.
+func fetchRemote(ctx context.Context, rp *RemoteProvider) ([]byte, error) {
+	backend, err := lookupRemoteBackend(rp.Type)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := backend.Get(ctx, rp)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(reader)
+}
+
+// viperRemoteBackend reads through viper's remote config factory, enabled by
+// a blank import of github.com/spf13/viper/remote.
+type viperRemoteBackend struct{}
+
+func (viperRemoteBackend) Get(_ context.Context, rp *RemoteProvider) (io.Reader, error) {
+	if viper.RemoteConfig == nil {
+		return nil, viper.RemoteConfigError("remote support is not enabled, add a blank import of github.com/spf13/viper/remote")
+	}
+	return viper.RemoteConfig.Get(remoteProviderAdapter{rp: rp})
+}
+
+// remoteProviderAdapter exposes a RemoteProvider through viper's provider interface.
+type remoteProviderAdapter struct {
+	rp *RemoteProvider
+}
+
+func (a remoteProviderAdapter) Provider() string      { return a.rp.Type }
+func (a remoteProviderAdapter) Endpoint() string      { return a.rp.Endpoint }
+func (a remoteProviderAdapter) Path() string          { return a.rp.Path }
+func (a remoteProviderAdapter) SecretKeyring() string { return "" }
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeRemote is an in-memory stand-in for viper's remote config factory.
+type fakeRemote struct {
+	mu   sync.Mutex
+	docs map[string][]byte
+}
+
+func (f *fakeRemote) set(path string, doc []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.docs[path] = doc
+}
+
+func (f *fakeRemote) remove(path string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.docs, path)
+}
+
+func (f *fakeRemote) Get(rp viper.RemoteProvider) (io.Reader, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	doc, ok := f.docs[rp.Path()]
+	if !ok {
+		return nil, errors.New("key not found")
+	}
+	return bytes.NewReader(doc), nil
+}
+
+func (f *fakeRemote) Watch(rp viper.RemoteProvider) (io.Reader, error) {
+	return f.Get(rp)
+}
+
+func (f *fakeRemote) WatchChannel(viper.RemoteProvider) (<-chan *viper.RemoteResponse, chan bool) {
+	return make(chan *viper.RemoteResponse), make(chan bool)
+}
+
+// useFakeRemote installs a fakeRemote for the duration of the test.
+func useFakeRemote(t *testing.T) *fakeRemote {
+	t.Helper()
+	prev := viper.RemoteConfig
+	f := &fakeRemote{docs: make(map[string][]byte)}
+	viper.RemoteConfig = f
+	t.Cleanup(func() { viper.RemoteConfig = prev })
+	return f
+}
+
+func newRemoteTestConfig(opts ...Option) *ConfigManager {
+	logger, _ := zap.NewDevelopment()
+	opts = append([]Option{WithRemoteProvider(&RemoteProvider{
+		Type:     "consul",
+		Endpoint: "localhost:8500",
+		Path:     "app/config",
+	})}, opts...)
+	return New("", logger, opts...)
+}
+
+func TestRemoteConfigLoad(t *testing.T) {
+	remote := useFakeRemote(t)
+
+	t.Run("Basic Document", func(t *testing.T) {
+		remote.set("app/config", []byte(`{"server": {"port": 8080}}`))
+		cfg := newRemoteTestConfig()
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+		assert.True(t, cfg.Annotations().IsZero())
+	})
+
+	t.Run("Annotated Document", func(t *testing.T) {
+		remote.set("app/config", []byte(`{
+			"_annotations": {"author": "jdoe", "ticket": "OPS-42", "message": "raise port"},
+			"server": {"port": 9090}
+		}`))
+		cfg := newRemoteTestConfig()
+		require.NoError(t, cfg.Load())
+
+		assert.Equal(t, 9090, cfg.GetInt("server.port"))
+		assert.Equal(t, Annotations{Author: "jdoe", Ticket: "OPS-42", Message: "raise port"}, cfg.Annotations())
+		assert.False(t, cfg.IsSet(AnnotationsKey), "annotations must not leak into settings")
+	})
+
+	t.Run("Newer Schema Version Rejected", func(t *testing.T) {
+		remote.set("app/config", []byte(`{"schemaVersion": 3, "server": {"port": 8080}}`))
+		cfg := newRemoteTestConfig(WithSchemaVersion(2))
+		var versionErr *SchemaVersionError
+		require.ErrorAs(t, cfg.Load(), &versionErr)
+		assert.False(t, cfg.IsSet("server.port"))
+	})
+
+	t.Run("Remote Support Disabled", func(t *testing.T) {
+		viper.RemoteConfig = nil
+		defer func() { viper.RemoteConfig = remote }()
+
+		cfg := newRemoteTestConfig()
+		var remoteErr viper.RemoteConfigError
+		assert.ErrorAs(t, cfg.Load(), &remoteErr)
+	})
+}
+
+func TestRemoteProviderRegistry(t *testing.T) {
+	t.Run("Unsupported Type Lists Options", func(t *testing.T) {
+		cfg := New("", zap.NewNop(), WithRemoteProvider(&RemoteProvider{Type: "zookeeper", Path: "app/config"}))
+		var providerErr *UnsupportedProviderError
+		require.ErrorAs(t, cfg.Load(), &providerErr)
+		assert.Equal(t, "zookeeper", providerErr.Type)
+		assert.Contains(t, providerErr.Supported, "consul")
+		assert.Contains(t, providerErr.Error(), "consul")
+	})
+
+	t.Run("Custom Type", func(t *testing.T) {
+		RegisterRemoteProvider("memory", RemoteBackendFunc(func(_ context.Context, rp *RemoteProvider) (io.Reader, error) {
+			return strings.NewReader(`{"server": {"path": "` + rp.Path + `"}}`), nil
+		}))
+		defer func() {
+			remoteMu.Lock()
+			delete(remoteBackends, "memory")
+			remoteMu.Unlock()
+		}()
+
+		assert.Contains(t, SupportedProviders(), "memory")
+		cfg := New("", zap.NewNop(), WithRemoteProvider(&RemoteProvider{Type: "memory", Path: "app/config"}))
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, "app/config", cfg.GetString("server.path"))
+	})
+}
+
+func TestRemotePollChangeDetection(t *testing.T) {
+	remote := useFakeRemote(t)
+	remote.set("app/config", []byte(`{"server": {"port": 8080}}`))
+
+	cfg := newRemoteTestConfig(WithWatcher(), WithPollInterval(5*time.Millisecond))
+	defer cfg.Close()
+	require.NoError(t, cfg.Load())
+
+	var reloads atomic.Int32
+	require.NoError(t, cfg.Watch(context.Background(), func() { reloads.Add(1) }))
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Zero(t, reloads.Load(), "unchanged polls must not reload")
+
+	remote.set("app/config", []byte(`{"server": {"port": 9090}}`))
+	require.Eventually(t, func() bool { return cfg.GetInt("server.port") == 9090 }, 2*time.Second, 5*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(1), reloads.Load())
+}
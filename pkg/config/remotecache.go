@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"go.uber.org/zap"
+)
+
+// WithRemoteFallback keeps the remote provider authoritative but lets the
+// manager start when it is unreachable: the first load then falls back to
+// the copy cached at cachePath, or, without one, to the local config file
+// alone, and Stale reports true until a load reaches the remote again. With
+// WithWatcher, the next successful poll reloads the remote document. Every
+// applied remote document is written to cachePath; an empty cachePath only
+// uses the local file. Once a remote document has been applied, later
+// failed fetches fail the load as usual and keep the live configuration.
+func WithRemoteFallback(cachePath string) Option {
+	return func(cm *ConfigManager) {
+		cm.remoteFallback = true
+		cm.remoteCache = cachePath
+	}
+}
+
+// Stale reports whether the live configuration was loaded from the remote
+// fallback because the remote provider was unreachable.
+func (cm *ConfigManager) Stale() bool {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	if r, ok := cm.provider.(*RemoteConfigProvider); ok {
+		return r.stale
+	}
+	return false
+}
+
+// fallbackDocument returns the cached remote document to use after the
+// fetch failed with fetchErr, or nil to use the local file alone. It
+// returns fetchErr if there is nothing to fall back to.
+func (r *RemoteConfigProvider) fallbackDocument(fileData []byte, fetchErr error) ([]byte, error) {
+	if r.cache != "" {
+		data, err := os.ReadFile(r.cache)
+		if err == nil {
+			r.logger.Warn("Using cached remote config",
+				zap.String("path", r.cache),
+				zap.Error(fetchErr))
+			return data, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("error reading remote config cache: %w", err)
+		}
+	}
+	if fileData == nil && len(r.overrides) == 0 {
+		return nil, fetchErr
+	}
+	r.logger.Warn("Using local config file without remote config",
+		zap.String("path", r.path),
+		zap.Error(fetchErr))
+	return nil, nil
+}
+
+// writeCache stores data as the last applied remote document.
+func (r *RemoteConfigProvider) writeCache(data []byte) {
+	if r.cache == "" {
+		return
+	}
+	if err := writeFileAtomic(r.cache, data); err != nil {
+		r.logger.Warn("Failed to cache remote config",
+			zap.String("path", r.cache),
+			zap.Error(err))
+	}
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRemoteFallback(t *testing.T) {
+	remote := useFakeRemote(t)
+
+	t.Run("Cached Copy", func(t *testing.T) {
+		cache := filepath.Join(t.TempDir(), "remote.json")
+		remote.set("app/config", []byte(`{"server": {"port": 8080}}`))
+		cfg := newRemoteTestConfig(WithRemoteFallback(cache))
+		require.NoError(t, cfg.Load())
+		assert.False(t, cfg.Stale())
+		data, err := os.ReadFile(cache)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"server": {"port": 8080}}`, string(data))
+
+		remote.remove("app/config")
+		cfg = newRemoteTestConfig(WithRemoteFallback(cache))
+		require.NoError(t, cfg.Load())
+		assert.True(t, cfg.Stale())
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+		assert.Empty(t, cfg.RemoteRevision())
+
+		remote.set("app/config", []byte(`{"server": {"port": 9090}}`))
+		require.NoError(t, cfg.Load())
+		assert.False(t, cfg.Stale())
+		assert.Equal(t, 9090, cfg.GetInt("server.port"))
+	})
+
+	t.Run("Local File", func(t *testing.T) {
+		remote.remove("app/config")
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("server:\n  port: 7070\n"), 0644))
+		logger, _ := zap.NewDevelopment()
+		cfg := New(path, logger,
+			WithRemoteProvider(&RemoteProvider{Type: "consul", Endpoint: "localhost:8500", Path: "app/config"}),
+			WithRemoteFallback(""))
+		require.NoError(t, cfg.Load())
+		assert.True(t, cfg.Stale())
+		assert.Equal(t, 7070, cfg.GetInt("server.port"))
+	})
+
+	t.Run("Nothing To Fall Back To", func(t *testing.T) {
+		remote.remove("app/config")
+		cfg := newRemoteTestConfig(WithRemoteFallback(filepath.Join(t.TempDir(), "missing.json")))
+		assert.Error(t, cfg.Load())
+	})
+
+	t.Run("Later Failures", func(t *testing.T) {
+		remote.set("app/config", []byte(`{"server": {"port": 8080}}`))
+		cfg := newRemoteTestConfig(WithRemoteFallback(filepath.Join(t.TempDir(), "remote.json")))
+		require.NoError(t, cfg.Load())
+
+		remote.remove("app/config")
+		assert.Error(t, cfg.Load())
+		assert.False(t, cfg.Stale())
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+	})
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"strings"
+)
+
+// OnSecretRotated calls onRotate whenever a reload changes the secret held
+// by key, or by a key under it, and returns a function that removes it.
+// Only keys holding a WithSecretResolver reference or a WithAgeIdentity
+// value count, so applications can rebuild connection pools and TLS
+// clients when their credentials rotate rather than on every config change.
+// Secrets are resolved again on every reload; file:// secrets resolved by
+// FileSecretResolver are watched, so a rotated Kubernetes or Docker secret
+// triggers one.
+func (cm *ConfigManager) OnSecretRotated(key string, onRotate func()) (unsubscribe func()) {
+	key = strings.ToLower(key)
+	return cm.subscribe(callbackName(onRotate), func(event ChangeEvent) {
+		for _, rotated := range event.Rotated {
+			if rotated == key || strings.HasPrefix(rotated, key+cm.keyDelimiter) {
+				onRotate()
+				return
+			}
+		}
+	})
+}
+
+// recordRotations picks the changes of the load just applied that changed
+// a resolved secret. The caller must hold cm.mu.
+func (cm *ConfigManager) recordRotations() {
+	cm.rotated = nil
+	for _, c := range cm.changes {
+		if c.New != nil && isSecret(c.Key, cm.resolvedSecrets, cm.keyDelimiter) {
+			cm.rotated = append(cm.rotated, c.Key)
+		}
+	}
+}
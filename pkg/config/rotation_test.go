@@ -0,0 +1,64 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestOnSecretRotated(t *testing.T) {
+	dir := t.TempDir()
+	secretPath := filepath.Join(dir, "db-password")
+	require.NoError(t, os.WriteFile(secretPath, []byte("s3cret\n"), 0600))
+	configPath := filepath.Join(dir, "config.yaml")
+	content := []byte("database:\n  password: file://db-password\n  port: 5432\n")
+	require.NoError(t, os.WriteFile(configPath, content, 0644))
+
+	cfg := New(configPath, zap.NewNop(), WithSecretResolver("file", FileSecretResolver{Dir: dir}))
+	defer cfg.Close()
+	require.NoError(t, cfg.Load())
+
+	rotated := make(chan struct{}, 10)
+	events := make(chan ChangeEvent, 10)
+	unsubscribe := cfg.OnSecretRotated("database", func() { rotated <- struct{}{} })
+	cfg.Subscribe(func(event ChangeEvent) { events <- event })
+
+	receive := func() ChangeEvent {
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(2 * time.Second):
+			t.Fatal("timeout waiting for change event")
+			return ChangeEvent{}
+		}
+	}
+
+	t.Run("Plain Change", func(t *testing.T) {
+		require.NoError(t, writeFileAtomic(configPath, []byte("database:\n  password: file://db-password\n  port: 6432\n")))
+		event := receive()
+		assert.Equal(t, []string{"database.port"}, event.Keys())
+		assert.Empty(t, event.Rotated)
+		assert.Empty(t, rotated)
+	})
+
+	t.Run("Rotated Secret File", func(t *testing.T) {
+		require.NoError(t, writeFileAtomic(secretPath, []byte("n3w-s3cret\n")))
+		event := receive()
+		assert.Equal(t, []string{"database.password"}, event.Rotated)
+		require.Len(t, rotated, 1)
+		<-rotated
+		assert.Equal(t, "n3w-s3cret", cfg.GetString("database.password"))
+	})
+
+	t.Run("Unsubscribed", func(t *testing.T) {
+		unsubscribe()
+		require.NoError(t, writeFileAtomic(secretPath, []byte("other\n")))
+		assert.Equal(t, []string{"database.password"}, receive().Rotated)
+		assert.Empty(t, rotated)
+	})
+}
@@ -0,0 +1,280 @@
+package config
+
+import (
+	"bufio"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// scaffoldNode is a key of a generated sample config: a leaf value or a
+// table of child keys.
+type scaffoldNode struct {
+	key      string
+	comments []string
+	value    interface{}
+	children []*scaffoldNode
+	table    bool
+	// The leaf's field type and raw tags, for Document.
+	typ    reflect.Type
+	def    string
+	rules  string
+	secret bool
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Scaffold writes a sample config for schema in the given format (yaml,
+// toml, json, jsonc, or json5). Keys are named the way they are decoded, by
+// mapstructure tag or lowercased field name. Each value is taken from the
+// field's default:"..." tag, or is the zero value of its type, and the
+// field's validate tag and secret tag are written as comments (strict JSON
+// has no comments and gets none).
+func Scaffold(schema interface{}, format string, w io.Writer) error {
+	t := reflect.TypeOf(schema)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return fmt.Errorf("schema must be a struct or pointer to struct, got %T", schema)
+	}
+	root, err := scaffoldFields(t, "")
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	switch strings.ToLower(format) {
+	case "yaml", "yml":
+		writeScaffoldYAML(bw, root, 0)
+	case "toml":
+		writeScaffoldTOML(bw, root, nil)
+	case "json":
+		writeScaffoldJSON(bw, root, 0, false)
+		bw.WriteString("\n")
+	case "jsonc", "json5":
+		writeScaffoldJSON(bw, root, 0, true)
+		bw.WriteString("\n")
+	default:
+		return fmt.Errorf("unsupported scaffold format '%s', supported formats are: json, json5, jsonc, toml, yaml", format)
+	}
+	return bw.Flush()
+}
+
+// scaffoldFields builds the nodes of struct type t. path names the struct
+// in error messages.
+func scaffoldFields(t reflect.Type, path string) ([]*scaffoldNode, error) {
+	var nodes []*scaffoldNode
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("mapstructure"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && ft != reflect.TypeOf(time.Time{}) {
+			children, err := scaffoldFields(ft, path+f.Name+".")
+			if err != nil {
+				return nil, err
+			}
+			if f.Anonymous && strings.Contains(opts, "squash") {
+				nodes = append(nodes, children...)
+				continue
+			}
+			node := &scaffoldNode{key: name, children: children, table: true}
+			if v := f.Tag.Get("validate"); v != "" {
+				node.comments = append(node.comments, "validate: "+v)
+			}
+			nodes = append(nodes, node)
+			continue
+		}
+
+		node := &scaffoldNode{
+			key:    name,
+			typ:    f.Type,
+			def:    f.Tag.Get("default"),
+			rules:  f.Tag.Get("validate"),
+			secret: f.Tag.Get("secret") == "true",
+		}
+		if v := f.Tag.Get("validate"); v != "" {
+			node.comments = append(node.comments, "validate: "+v)
+		}
+		if f.Tag.Get("secret") == "true" {
+			node.comments = append(node.comments, "secret: set through the environment or a secret store")
+		}
+		value, err := scaffoldValue(ft, f.Tag.Get("default"))
+		if err != nil {
+			return nil, fmt.Errorf("field %s%s: invalid default %q: %w", path, f.Name, f.Tag.Get("default"), err)
+		}
+		node.value = value
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// scaffoldValue parses def as a value of type t, or returns the zero value
+// of t when def is empty. Slice defaults are comma-separated, and types
+// implementing encoding.TextUnmarshaler keep def as written once it parses.
+func scaffoldValue(t reflect.Type, def string) (interface{}, error) {
+	// Types like Size and Duration parse their own defaults.
+	if def != "" && reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		if err := reflect.New(t).Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(def)); err != nil {
+			return nil, err
+		}
+		return def, nil
+	}
+	if t == durationType {
+		if def == "" {
+			return "0s", nil
+		}
+		d, err := time.ParseDuration(def)
+		if err != nil {
+			return nil, err
+		}
+		return d.String(), nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return def, nil
+	case reflect.Bool:
+		if def == "" {
+			return false, nil
+		}
+		return strconv.ParseBool(def)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if def == "" {
+			return 0, nil
+		}
+		return strconv.ParseInt(def, 10, t.Bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if def == "" {
+			return 0, nil
+		}
+		return strconv.ParseUint(def, 10, t.Bits())
+	case reflect.Float32, reflect.Float64:
+		if def == "" {
+			return 0, nil
+		}
+		return strconv.ParseFloat(def, t.Bits())
+	case reflect.Slice, reflect.Array:
+		items := []interface{}{}
+		if def == "" {
+			return items, nil
+		}
+		for _, part := range strings.Split(def, ",") {
+			item, err := scaffoldValue(t.Elem(), strings.TrimSpace(part))
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case reflect.Map:
+		return map[string]interface{}{}, nil
+	default:
+		if def != "" {
+			return nil, fmt.Errorf("defaults are not supported for %s fields", t)
+		}
+		return "", nil
+	}
+}
+
+// scaffoldScalar renders a leaf value. JSON scalars and arrays are also
+// valid YAML flow values and TOML values.
+func scaffoldScalar(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+func writeScaffoldYAML(w *bufio.Writer, nodes []*scaffoldNode, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for _, n := range nodes {
+		for _, c := range n.comments {
+			fmt.Fprintf(w, "%s# %s\n", indent, c)
+		}
+		if n.table {
+			fmt.Fprintf(w, "%s%s:\n", indent, n.key)
+			writeScaffoldYAML(w, n.children, depth+1)
+			continue
+		}
+		fmt.Fprintf(w, "%s%s: %s\n", indent, n.key, scaffoldScalar(n.value))
+	}
+}
+
+var bareTOMLKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func tomlKey(key string) string {
+	if bareTOMLKey.MatchString(key) {
+		return key
+	}
+	return strconv.Quote(key)
+}
+
+// writeScaffoldTOML writes the leaves of a table, then each sub-table under
+// its own [header].
+func writeScaffoldTOML(w *bufio.Writer, nodes []*scaffoldNode, path []string) {
+	for _, n := range nodes {
+		if n.table {
+			continue
+		}
+		for _, c := range n.comments {
+			fmt.Fprintf(w, "# %s\n", c)
+		}
+		fmt.Fprintf(w, "%s = %s\n", tomlKey(n.key), scaffoldScalar(n.value))
+	}
+	for _, n := range nodes {
+		if !n.table {
+			continue
+		}
+		sub := append(append([]string(nil), path...), tomlKey(n.key))
+		w.WriteString("\n")
+		for _, c := range n.comments {
+			fmt.Fprintf(w, "# %s\n", c)
+		}
+		fmt.Fprintf(w, "[%s]\n", strings.Join(sub, "."))
+		writeScaffoldTOML(w, n.children, sub)
+	}
+}
+
+func writeScaffoldJSON(w *bufio.Writer, nodes []*scaffoldNode, depth int, comments bool) {
+	indent := strings.Repeat("  ", depth+1)
+	w.WriteString("{\n")
+	for i, n := range nodes {
+		if comments {
+			for _, c := range n.comments {
+				fmt.Fprintf(w, "%s// %s\n", indent, c)
+			}
+		}
+		fmt.Fprintf(w, "%s%s: ", indent, scaffoldScalar(n.key))
+		if n.table {
+			writeScaffoldJSON(w, n.children, depth+1, comments)
+		} else {
+			w.WriteString(scaffoldScalar(n.value))
+		}
+		if i < len(nodes)-1 {
+			w.WriteString(",")
+		}
+		w.WriteString("\n")
+	}
+	w.WriteString(strings.Repeat("  ", depth) + "}")
+}
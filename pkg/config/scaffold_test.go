@@ -0,0 +1,69 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type scaffoldConfig struct {
+	Server struct {
+		Host    string        `mapstructure:"host" default:"localhost" validate:"required,hostname"`
+		Port    int           `mapstructure:"port" default:"8080" validate:"required,min=1,max=65535"`
+		Timeout time.Duration `mapstructure:"timeout" default:"30s"`
+		Tags    []string      `mapstructure:"tags" default:"a, b"`
+	} `mapstructure:"server"`
+	Database struct {
+		Password string `mapstructure:"password" secret:"true"`
+		MaxConns int    `mapstructure:"maxConns"`
+	} `mapstructure:"database"`
+	Debug bool
+}
+
+func TestScaffold(t *testing.T) {
+	for _, format := range []string{"yaml", "toml", "json", "jsonc", "json5"} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, Scaffold(&scaffoldConfig{}, format, &buf))
+
+			dec, err := lookupDecoder(format)
+			require.NoError(t, err)
+			settings, err := dec.Decode(buf.Bytes())
+			require.NoError(t, err, buf.String())
+
+			server := settings["server"].(map[string]interface{})
+			assert.Equal(t, "localhost", server["host"])
+			assert.EqualValues(t, 8080, server["port"])
+			assert.Equal(t, "30s", server["timeout"])
+			assert.Equal(t, []interface{}{"a", "b"}, server["tags"])
+			assert.Equal(t, false, settings["debug"])
+			assert.Contains(t, settings["database"], "maxConns")
+
+			if format != "json" {
+				assert.Contains(t, buf.String(), "validate: required,min=1,max=65535")
+				assert.Contains(t, buf.String(), "secret:")
+			}
+		})
+	}
+
+	t.Run("Decodes Into Schema", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, Scaffold(AppConfig{}, "yaml", &buf))
+		_, err := decodeYAML(buf.Bytes())
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid Default", func(t *testing.T) {
+		var bad struct {
+			Port int `default:"http"`
+		}
+		assert.ErrorContains(t, Scaffold(&bad, "yaml", &bytes.Buffer{}), "field Port")
+	})
+
+	t.Run("Unsupported Format", func(t *testing.T) {
+		assert.Error(t, Scaffold(&scaffoldConfig{}, "ini", &bytes.Buffer{}))
+	})
+}
@@ -2,18 +2,18 @@ package config
 
 type AppConfig struct {
 	Server struct {
-		Port            string `mapstructure:"port" validate:"required,numeric"`
-		ReadTimeout     string `mapstructure:"read_timeout" validate:"required,endswith=s"`
-		WriteTimeout    string `mapstructure:"write_timeout" validate:"required,endswith=s"`
-		IdleTimeout     string `mapstructure:"idle_timeout" validate:"required,endswith=s"`
-		ShutdownTimeout string `mapstructure:"shutdown_timeout" validate:"required,endswith=s"`
+		Port            string   `mapstructure:"port" validate:"required,numeric"`
+		ReadTimeout     Duration `mapstructure:"read_timeout" validate:"required"`
+		WriteTimeout    Duration `mapstructure:"write_timeout" validate:"required"`
+		IdleTimeout     Duration `mapstructure:"idle_timeout" validate:"required"`
+		ShutdownTimeout Duration `mapstructure:"shutdown_timeout" validate:"required"`
 	} `mapstructure:"server"`
 	Crawler struct {
-		MaxDepth   int    `mapstructure:"maxDepth"`
-		UserAgent  string `mapstructure:"userAgent"`
-		Async      bool   `mapstructure:"async"`
-		Timeout    string `mapstructure:"timeout" validate:"required,endswith=s"`
-		NumWorkers int    `mapstructure:"num_workers"`
+		MaxDepth   int      `mapstructure:"maxDepth"`
+		UserAgent  string   `mapstructure:"userAgent"`
+		Async      bool     `mapstructure:"async"`
+		Timeout    Duration `mapstructure:"timeout" validate:"required"`
+		NumWorkers int      `mapstructure:"num_workers"`
 	} `mapstructure:"crawler"`
 	Checkpoint struct {
 		Enabled  bool   `mapstructure:"enabled"`
@@ -26,16 +26,16 @@ type AppConfig struct {
 	} `mapstructure:"logging"`
 	Storage struct {
 		Elasticsearch struct {
-			Endpoint   string `mapstructure:"endpoint" validate:"required,url"`
-			Index      string `mapstructure:"index" validate:"required"`
-			Timeout    string `mapstructure:"timeout" validate:"required,endswith=s"`
-			RetryLimit int    `mapstructure:"retryLimit" validate:"required,min=1,max=10"`
+			Endpoint   string   `mapstructure:"endpoint" validate:"required,url"`
+			Index      string   `mapstructure:"index" validate:"required"`
+			Timeout    Duration `mapstructure:"timeout" validate:"required"`
+			RetryLimit int      `mapstructure:"retryLimit" validate:"required,min=1,max=10"`
 		} `mapstructure:"elasticsearch"`
 	} `mapstructure:"storage"`
 	Redis struct {
 		Endpoint   string `mapstructure:"endpoint"`
 		DB         int    `mapstructure:"db"`
-		Password   string `mapstructure:"password"`
+		Password   string `mapstructure:"password" secret:"true"`
 		QueueKey   string `mapstructure:"queueKey"`
 		VisitedKey string `mapstructure:"visitedKey"`
 		RetryLimit int    `mapstructure:"retryLimit"`
@@ -0,0 +1,355 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"go.uber.org/zap"
+)
+
+// ErrAccessDenied is matched by errors reporting reads outside a scoped view.
+var ErrAccessDenied = errors.New("access denied")
+
+// AccessError is returned when a scoped view is asked for a key outside its
+// allowed prefixes.
+type AccessError struct {
+	Key string
+}
+
+func (e *AccessError) Error() string {
+	return fmt.Sprintf("access denied to config key '%s'", e.Key)
+}
+
+func (e *AccessError) Is(target error) bool {
+	return target == ErrAccessDenied
+}
+
+// ScopedConfig is a read-only Config restricted to a set of key prefixes.
+// Reads outside the allowed prefixes return zero values, report IsSet as
+// false, and are logged; GetE returns an *AccessError for them instead.
+type ScopedConfig struct {
+	parent   *ConfigManager
+	prefixes []string
+}
+
+// ScopedView returns a Config restricted to keys under allowedPrefixes, for
+// handing to code that must not see the rest of the configuration.
+// A prefix grants access to the key itself and everything nested below it;
+// ancestors of a prefix are not readable.
+func (cm *ConfigManager) ScopedView(allowedPrefixes ...string) *ScopedConfig {
+	prefixes := make([]string, len(allowedPrefixes))
+	for i, p := range allowedPrefixes {
+		prefixes[i] = strings.ToLower(strings.Trim(p, cm.keyDelimiter))
+	}
+	return &ScopedConfig{parent: cm, prefixes: prefixes}
+}
+
+// allowed reports whether key falls under one of the allowed prefixes.
+func (s *ScopedConfig) allowed(key string) bool {
+	key = strings.ToLower(key)
+	for _, p := range s.prefixes {
+		if p != "" && (key == p || strings.HasPrefix(key, p+s.parent.keyDelimiter)) {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeHooks returns the parent's decode hooks.
+func (s *ScopedConfig) decodeHooks() mapstructure.DecodeHookFunc {
+	return s.parent.decodeHooks()
+}
+
+// check logs and reports a denied read.
+func (s *ScopedConfig) check(key string) bool {
+	if s.allowed(key) {
+		return true
+	}
+	s.parent.logger.Warn("Denied config read outside scoped view", zap.String("key", key))
+	return false
+}
+
+// GetE returns the value for key, or an *AccessError if the key is outside
+// the view.
+func (s *ScopedConfig) GetE(key string) (interface{}, error) {
+	if !s.check(key) {
+		return nil, &AccessError{Key: key}
+	}
+	return s.parent.Get(key), nil
+}
+
+// Load is not permitted through a scoped view.
+func (s *ScopedConfig) Load() error {
+	return fmt.Errorf("scoped view cannot load configuration: %w", ErrAccessDenied)
+}
+
+// Set is not permitted through a scoped view.
+func (s *ScopedConfig) Set(key string, value interface{}) error {
+	return fmt.Errorf("scoped view cannot set '%s': %w", key, ErrAccessDenied)
+}
+
+// SetDefault is not permitted through a scoped view.
+func (s *ScopedConfig) SetDefault(key string, value interface{}) error {
+	return fmt.Errorf("scoped view cannot set default for '%s': %w", key, ErrAccessDenied)
+}
+
+// Unset is not permitted through a scoped view.
+func (s *ScopedConfig) Unset(key string) error {
+	return fmt.Errorf("scoped view cannot unset '%s': %w", key, ErrAccessDenied)
+}
+
+// Get returns a value for the given key inside the view.
+func (s *ScopedConfig) Get(key string) interface{} {
+	v, _ := s.GetE(key)
+	return v
+}
+
+// GetString returns a string value for the given key inside the view.
+func (s *ScopedConfig) GetString(key string) string {
+	if !s.check(key) {
+		return ""
+	}
+	return s.parent.GetString(key)
+}
+
+// GetInt returns an integer value for the given key inside the view.
+func (s *ScopedConfig) GetInt(key string) int {
+	if !s.check(key) {
+		return 0
+	}
+	return s.parent.GetInt(key)
+}
+
+// GetFloat64 returns a float64 value for the given key inside the view.
+func (s *ScopedConfig) GetFloat64(key string) float64 {
+	if !s.check(key) {
+		return 0
+	}
+	return s.parent.GetFloat64(key)
+}
+
+// GetBool returns a boolean value for the given key inside the view.
+func (s *ScopedConfig) GetBool(key string) bool {
+	if !s.check(key) {
+		return false
+	}
+	return s.parent.GetBool(key)
+}
+
+// GetStringSlice returns a string slice value for the given key inside the view.
+func (s *ScopedConfig) GetStringSlice(key string) []string {
+	if !s.check(key) {
+		return nil
+	}
+	return s.parent.GetStringSlice(key)
+}
+
+// GetStringMap returns a map[string]interface{} value for the given key inside the view.
+func (s *ScopedConfig) GetStringMap(key string) map[string]interface{} {
+	if !s.check(key) {
+		return nil
+	}
+	return s.parent.GetStringMap(key)
+}
+
+// GetDuration returns a duration value for the given key inside the view.
+func (s *ScopedConfig) GetDuration(key string) time.Duration {
+	if !s.check(key) {
+		return 0
+	}
+	return s.parent.GetDuration(key)
+}
+
+// GetTime returns a time.Time value for the given key inside the view.
+func (s *ScopedConfig) GetTime(key string) time.Time {
+	if !s.check(key) {
+		return time.Time{}
+	}
+	return s.parent.GetTime(key)
+}
+
+// GetInt32 returns an int32 value for the given key inside the view.
+func (s *ScopedConfig) GetInt32(key string) int32 {
+	if !s.check(key) {
+		return 0
+	}
+	return s.parent.GetInt32(key)
+}
+
+// GetInt64 returns an int64 value for the given key inside the view.
+func (s *ScopedConfig) GetInt64(key string) int64 {
+	if !s.check(key) {
+		return 0
+	}
+	return s.parent.GetInt64(key)
+}
+
+// GetUint returns a uint value for the given key inside the view.
+func (s *ScopedConfig) GetUint(key string) uint {
+	if !s.check(key) {
+		return 0
+	}
+	return s.parent.GetUint(key)
+}
+
+// GetUint16 returns a uint16 value for the given key inside the view.
+func (s *ScopedConfig) GetUint16(key string) uint16 {
+	if !s.check(key) {
+		return 0
+	}
+	return s.parent.GetUint16(key)
+}
+
+// GetUint32 returns a uint32 value for the given key inside the view.
+func (s *ScopedConfig) GetUint32(key string) uint32 {
+	if !s.check(key) {
+		return 0
+	}
+	return s.parent.GetUint32(key)
+}
+
+// GetUint64 returns a uint64 value for the given key inside the view.
+func (s *ScopedConfig) GetUint64(key string) uint64 {
+	if !s.check(key) {
+		return 0
+	}
+	return s.parent.GetUint64(key)
+}
+
+// GetIntSlice returns an int slice value for the given key inside the view.
+func (s *ScopedConfig) GetIntSlice(key string) []int {
+	if !s.check(key) {
+		return nil
+	}
+	return s.parent.GetIntSlice(key)
+}
+
+// GetStringMapString returns a string map value for the given key inside the view.
+func (s *ScopedConfig) GetStringMapString(key string) map[string]string {
+	if !s.check(key) {
+		return nil
+	}
+	return s.parent.GetStringMapString(key)
+}
+
+// GetStringMapStringSlice returns a string slice map value for the given key inside the view.
+func (s *ScopedConfig) GetStringMapStringSlice(key string) map[string][]string {
+	if !s.check(key) {
+		return nil
+	}
+	return s.parent.GetStringMapStringSlice(key)
+}
+
+// GetSizeInBytes returns the value for the given key inside the view as a number
+// of bytes.
+func (s *ScopedConfig) GetSizeInBytes(key string) uint {
+	if !s.check(key) {
+		return 0
+	}
+	return s.parent.GetSizeInBytes(key)
+}
+
+// IsSet reports false for keys outside the view without logging, so
+// callers can probe for optional keys.
+func (s *ScopedConfig) IsSet(key string) bool {
+	return s.allowed(key) && s.parent.IsSet(key)
+}
+
+// Lookup returns the value for the given key inside the view and whether
+// the key is set. Keys outside the view are reported as not set.
+func (s *ScopedConfig) Lookup(key string) (interface{}, bool) {
+	if !s.check(key) {
+		return nil, false
+	}
+	return s.parent.Lookup(key)
+}
+
+// GetStringOk returns a string value for the given key inside the view and
+// whether the key is set.
+func (s *ScopedConfig) GetStringOk(key string) (string, bool) {
+	if !s.check(key) {
+		return "", false
+	}
+	return s.parent.GetStringOk(key)
+}
+
+// GetIntOk returns an int value for the given key inside the view and
+// whether the key is set.
+func (s *ScopedConfig) GetIntOk(key string) (int, bool) {
+	if !s.check(key) {
+		return 0, false
+	}
+	return s.parent.GetIntOk(key)
+}
+
+// GetFloat64Ok returns a float64 value for the given key inside the view
+// and whether the key is set.
+func (s *ScopedConfig) GetFloat64Ok(key string) (float64, bool) {
+	if !s.check(key) {
+		return 0, false
+	}
+	return s.parent.GetFloat64Ok(key)
+}
+
+// GetBoolOk returns a boolean value for the given key inside the view and
+// whether the key is set.
+func (s *ScopedConfig) GetBoolOk(key string) (bool, bool) {
+	if !s.check(key) {
+		return false, false
+	}
+	return s.parent.GetBoolOk(key)
+}
+
+// GetDurationOk returns a duration value for the given key inside the view
+// and whether the key is set.
+func (s *ScopedConfig) GetDurationOk(key string) (time.Duration, bool) {
+	if !s.check(key) {
+		return 0, false
+	}
+	return s.parent.GetDurationOk(key)
+}
+
+// GetSchema always returns nil: the application schema spans the whole
+// configuration.
+func (s *ScopedConfig) GetSchema() interface{} {
+	return nil
+}
+
+// UnmarshalKey decodes the subtree at key inside the view into out, or
+// returns an *AccessError if key is outside it.
+func (s *ScopedConfig) UnmarshalKey(key string, out interface{}) error {
+	if !s.check(key) {
+		return &AccessError{Key: key}
+	}
+	return s.parent.UnmarshalKey(key, out)
+}
+
+// Watch subscribes to reloads of the parent configuration.
+func (s *ScopedConfig) Watch(ctx context.Context, onChange func()) error {
+	return s.parent.Watch(ctx, onChange)
+}
+
+// AllKeys returns the keys inside the view.
+func (s *ScopedConfig) AllKeys() []string {
+	var keys []string
+	for _, key := range s.parent.AllKeys() {
+		if s.allowed(key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// AllSettings returns a deep copy of the settings inside the view as a
+// nested map.
+func (s *ScopedConfig) AllSettings() map[string]interface{} {
+	settings := make(map[string]interface{})
+	for _, key := range s.AllKeys() {
+		setPath(settings, s.parent.splitKey(key), deepCopyValue(s.parent.Get(key)))
+	}
+	return settings
+}
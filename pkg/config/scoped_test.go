@@ -0,0 +1,77 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestScopedView(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	logger, _ := zap.NewDevelopment()
+	cfg := New(configPath, logger)
+	require.NoError(t, cfg.Load())
+
+	var view Config = cfg.ScopedView("server")
+	scoped := view.(*ScopedConfig)
+
+	t.Run("Reads Inside Scope", func(t *testing.T) {
+		assert.Equal(t, 8080, view.GetInt("server.port"))
+		assert.Equal(t, "localhost", view.GetString("server.host"))
+		assert.True(t, view.IsSet("server.port"))
+
+		v, err := scoped.GetE("server.host")
+		require.NoError(t, err)
+		assert.Equal(t, "localhost", v)
+	})
+
+	t.Run("Reads Outside Scope", func(t *testing.T) {
+		assert.Equal(t, "", view.GetString("database.host"))
+		assert.Equal(t, 0, view.GetInt("database.port"))
+		assert.Nil(t, view.Get("database"))
+		assert.False(t, view.IsSet("database.name"))
+
+		_, err := scoped.GetE("database.host")
+		var accessErr *AccessError
+		require.ErrorAs(t, err, &accessErr)
+		assert.Equal(t, "database.host", accessErr.Key)
+		assert.ErrorIs(t, err, ErrAccessDenied)
+	})
+
+	t.Run("Unmarshal Inside Scope", func(t *testing.T) {
+		var server struct {
+			Port int    `mapstructure:"port"`
+			Host string `mapstructure:"host"`
+		}
+		require.NoError(t, view.UnmarshalKey("server", &server))
+		assert.Equal(t, 8080, server.Port)
+
+		var db map[string]interface{}
+		var accessErr *AccessError
+		assert.ErrorAs(t, view.UnmarshalKey("database", &db), &accessErr)
+		assert.Nil(t, db)
+	})
+
+	t.Run("Prefix Boundaries", func(t *testing.T) {
+		narrow := cfg.ScopedView("server.host")
+		assert.Equal(t, "localhost", narrow.GetString("server.host"))
+		assert.Nil(t, narrow.Get("server"), "ancestors of a prefix are not readable")
+		assert.Equal(t, 0, cfg.ScopedView("serv").GetInt("server.port"))
+	})
+
+	t.Run("Listing Is Filtered", func(t *testing.T) {
+		assert.ElementsMatch(t, []string{"server.port", "server.host", "server.timeout"}, view.AllKeys())
+		settings := view.AllSettings()
+		assert.Contains(t, settings, "server")
+		assert.NotContains(t, settings, "database")
+	})
+
+	t.Run("No Schema Or Load", func(t *testing.T) {
+		assert.Nil(t, view.GetSchema())
+		assert.ErrorIs(t, view.Load(), ErrAccessDenied)
+	})
+}
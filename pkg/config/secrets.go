@@ -0,0 +1,224 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// SecretResolver fetches the secret a reference points to. ref is the part
+// of the reference after "scheme://", e.g. "secret/data/app#password" for
+// vault://secret/data/app#password.
+type SecretResolver interface {
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+// SecretResolverFunc adapts a function to the SecretResolver interface.
+type SecretResolverFunc func(ctx context.Context, ref string) (string, error)
+
+// Resolve calls f(ctx, ref).
+func (f SecretResolverFunc) Resolve(ctx context.Context, ref string) (string, error) {
+	return f(ctx, ref)
+}
+
+// WithSecretResolver resolves string values of the form scheme://ref with r
+// at load time, e.g. WithSecretResolver("vault", vaultResolver) for
+// vault://secret/data/app#password, so secrets reach the getters and schemas
+// without being stored in the config document. Values using schemes without
+// a resolver are left as they are. Keys holding resolved secrets are masked
+// like WithSecretKeys keys. FileSecretResolver and EnvSecretResolver
+// implement the file and env schemes.
+func WithSecretResolver(scheme string, r SecretResolver) Option {
+	return func(cm *ConfigManager) {
+		if cm.secretResolvers == nil {
+			cm.secretResolvers = make(map[string]SecretResolver)
+		}
+		cm.secretResolvers[strings.ToLower(scheme)] = r
+	}
+}
+
+// FileSecretResolver resolves references to the content of a file, such as
+// a mounted Kubernetes or Docker secret, without its trailing newline.
+// Relative paths are relative to Dir. The files are watched for changes.
+type FileSecretResolver struct {
+	Dir string
+}
+
+// Resolve reads the file at ref.
+func (r FileSecretResolver) Resolve(_ context.Context, ref string) (string, error) {
+	data, err := os.ReadFile(r.file(ref))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// file returns the path of the file ref names.
+func (r FileSecretResolver) file(ref string) string {
+	if filepath.IsAbs(ref) {
+		return ref
+	}
+	return filepath.Join(r.Dir, ref)
+}
+
+// EnvSecretResolver resolves references to the value of the environment
+// variable they name.
+type EnvSecretResolver struct{}
+
+// Resolve returns the value of the variable ref.
+func (EnvSecretResolver) Resolve(_ context.Context, ref string) (string, error) {
+	value, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", ref)
+	}
+	return value, nil
+}
+
+// SecretError is returned by a load when a secret reference cannot be
+// resolved.
+type SecretError struct {
+	Key string
+	Ref string
+	Err error
+}
+
+func (e *SecretError) Error() string {
+	return fmt.Sprintf("resolving secret %s for %s: %v", e.Ref, e.Key, e.Err)
+}
+
+func (e *SecretError) Unwrap() error {
+	return e.Err
+}
+
+// secretRef matches a whole value of the form scheme://ref.
+var secretRef = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9+.-]*)://(.*)$`)
+
+// secretTimeout bounds the resolution of the secrets of one load.
+const secretTimeout = 30 * time.Second
+
+// appliedSecrets describes the secrets applySecrets resolved.
+type appliedSecrets struct {
+	// keys holds the keys that held a secret, sorted.
+	keys []string
+	// refs holds the references and encrypted values resolved.
+	refs map[string]bool
+}
+
+// applySecrets replaces the secret references and age-encrypted values in
+// v with the secrets they hold. Each reference is resolved once per load.
+// A dry run reads the live leases of leased references without keeping new
+// ones, since it only holds cm.mu for reading.
+func (cm *ConfigManager) applySecrets(v *viper.Viper, dryRun bool) (*viper.Viper, appliedSecrets, error) {
+	var applied appliedSecrets
+	if len(cm.secretResolvers) == 0 && len(cm.ageIdentities) == 0 {
+		return v, applied, nil
+	}
+	ctx, cancel := context.WithTimeout(cm.sup.ctx, secretTimeout)
+	defer cancel()
+
+	settings := v.AllSettings()
+	resolved := make(map[string]string)
+	var keys []string
+	var errs []error
+	var resolve func(value interface{}, key string) interface{}
+	resolve = func(value interface{}, key string) interface{} {
+		switch t := value.(type) {
+		case string:
+			ref, fetch := cm.secretSource(ctx, t, dryRun)
+			if fetch == nil {
+				return value
+			}
+			secret, ok := resolved[t]
+			if !ok {
+				var err error
+				if secret, err = fetch(); err != nil {
+					errs = append(errs, &SecretError{Key: key, Ref: ref, Err: err})
+					return value
+				}
+				resolved[t] = secret
+			}
+			keys = append(keys, key)
+			return secret
+		case map[string]interface{}:
+			for k, elem := range t {
+				t[k] = resolve(elem, key+cm.keyDelimiter+k)
+			}
+		case []interface{}:
+			// Secrets in a list mask the whole list.
+			before := len(keys)
+			for i, elem := range t {
+				t[i] = resolve(elem, key)
+			}
+			if len(keys) > before {
+				keys = append(keys[:before], key)
+			}
+		}
+		return value
+	}
+	for k, value := range settings {
+		settings[k] = resolve(value, k)
+	}
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool {
+			return errs[i].(*SecretError).Key < errs[j].(*SecretError).Key
+		})
+		return nil, applied, errors.Join(errs...)
+	}
+	if len(keys) == 0 {
+		return v, applied, nil
+	}
+
+	rebuilt := cm.newViper()
+	if err := rebuilt.MergeConfigMap(settings); err != nil {
+		return nil, applied, err
+	}
+	sort.Strings(keys)
+	applied.keys = keys
+	applied.refs = make(map[string]bool, len(resolved))
+	for s := range resolved {
+		applied.refs[s] = true
+	}
+	return rebuilt, applied, nil
+}
+
+// secretSource returns how to fetch the secret s holds and how to name it
+// in errors, or a nil fetch if s is a plain value.
+func (cm *ConfigManager) secretSource(ctx context.Context, s string, dryRun bool) (string, func() (string, error)) {
+	if len(cm.ageIdentities) > 0 && ageEncrypted(s) {
+		return agePrefix + "...]", func() (string, error) {
+			return decryptAgeValue(s, cm.ageIdentities)
+		}
+	}
+	m := secretRef.FindStringSubmatch(s)
+	if m == nil {
+		return "", nil
+	}
+	r, ok := cm.secretResolvers[strings.ToLower(m[1])]
+	if !ok {
+		return "", nil
+	}
+	if fr, ok := r.(FileSecretResolver); ok {
+		cm.watchTargets.addFile(fr.file(m[2]))
+	}
+	if lr, ok := r.(LeasedSecretResolver); ok {
+		if l, ok := cm.leases[s]; ok {
+			return s, func() (string, error) { return l.lease.Value, nil }
+		}
+		if !dryRun {
+			return s, func() (string, error) {
+				return cm.leasedSecret(ctx, s, m[2], lr)
+			}
+		}
+	}
+	return s, func() (string, error) {
+		return r.Resolve(ctx, m[2])
+	}
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSecretResolvers(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "db-password"), []byte("s3cret\n"), 0600))
+	vault := map[string]string{"secret/data/app#token": "vault-token"}
+	calls := 0
+	vaultResolver := SecretResolverFunc(func(_ context.Context, ref string) (string, error) {
+		calls++
+		if secret, ok := vault[ref]; ok {
+			return secret, nil
+		}
+		return "", errors.New("not found")
+	})
+	opts := []Option{
+		WithSecretResolver("file", FileSecretResolver{Dir: dir}),
+		WithSecretResolver("env", EnvSecretResolver{}),
+		WithSecretResolver("vault", vaultResolver),
+	}
+
+	t.Run("Resolves References", func(t *testing.T) {
+		t.Setenv("API_KEY", "key-123")
+		calls = 0
+		cfg := New(writeInterpolateConfig(t, `
+database:
+  password: file://db-password
+api:
+  key: env://API_KEY
+  token: vault://secret/data/app#token
+  backup_token: vault://secret/data/app#token
+  url: https://example.com
+tokens:
+  - vault://secret/data/app#token
+`), zap.NewNop(), opts...)
+		require.NoError(t, cfg.Load())
+
+		assert.Equal(t, "s3cret", cfg.GetString("database.password"))
+		assert.Equal(t, "key-123", cfg.GetString("api.key"))
+		assert.Equal(t, "vault-token", cfg.GetString("api.token"))
+		assert.Equal(t, "https://example.com", cfg.GetString("api.url"))
+		assert.Equal(t, []string{"vault-token"}, cfg.GetStringSlice("tokens"))
+		assert.Equal(t, 1, calls)
+
+		redacted := cfg.AllSettingsRedacted()
+		assert.Equal(t, RedactedValue, redacted["database"].(map[string]interface{})["password"])
+		assert.Equal(t, RedactedValue, redacted["api"].(map[string]interface{})["token"])
+		assert.Equal(t, "https://example.com", redacted["api"].(map[string]interface{})["url"])
+		assert.Equal(t, RedactedValue, redacted["tokens"])
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		var schema struct {
+			Database struct {
+				Password string `mapstructure:"password"`
+			} `mapstructure:"database"`
+		}
+		cfg := New(writeInterpolateConfig(t, "database:\n  password: file://db-password\n"), zap.NewNop(),
+			append(opts, WithSchema(&schema))...)
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, "s3cret", schema.Database.Password)
+	})
+
+	t.Run("Unresolvable", func(t *testing.T) {
+		unsetenv(t, "MISSING_KEY")
+		cfg := New(writeInterpolateConfig(t, `
+api:
+  key: env://MISSING_KEY
+  token: vault://secret/data/other#token
+`), zap.NewNop(), opts...)
+		err := cfg.Load()
+
+		var serr *SecretError
+		require.ErrorAs(t, err, &serr)
+		assert.Equal(t, "api.key", serr.Key)
+		assert.Contains(t, err.Error(), "resolving secret vault://secret/data/other#token for api.token: not found")
+	})
+
+	t.Run("Without Resolvers", func(t *testing.T) {
+		cfg := New(writeInterpolateConfig(t, "database:\n  password: file://db-password\n"), zap.NewNop())
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, "file://db-password", cfg.GetString("database.password"))
+	})
+}
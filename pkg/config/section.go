@@ -0,0 +1,96 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// section is a configuration subtree owned by a component and validated
+// independently of the rest of the document.
+type section struct {
+	key    string
+	schema interface{}
+}
+
+// SectionError reports a registered section that failed validation.
+type SectionError struct {
+	Section string
+	Err     error
+}
+
+func (e *SectionError) Error() string {
+	return fmt.Sprintf("section '%s': %v", e.Section, e.Err)
+}
+
+func (e *SectionError) Unwrap() error {
+	return e.Err
+}
+
+// PartialApplyError is returned by Load when a reload was applied except for
+// the listed sections, which kept their previous values.
+type PartialApplyError struct {
+	Rejected []*SectionError
+}
+
+func (e *PartialApplyError) Error() string {
+	msgs := make([]string, len(e.Rejected))
+	for i, r := range e.Rejected {
+		msgs[i] = r.Error()
+	}
+	return "configuration partially applied, rejected " + strings.Join(msgs, "; ")
+}
+
+// RegisterSection registers a component-owned subtree at key with its own
+// schema. On every load the subtree is unmarshaled into schema and validated
+// on its own; if it fails during a reload, the previous values for that
+// subtree are kept while the rest of the document is applied. Default tags
+// on schema fields apply under key.
+func (cm *ConfigManager) RegisterSection(key string, schema interface{}) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	key = strings.ToLower(key)
+	cm.sections = append(cm.sections, section{key: key, schema: schema})
+	cm.addTagDefaults(schema, key+cm.keyDelimiter)
+}
+
+// applySections validates every registered section against next. Failed
+// sections are restored from the live configuration and reported; on the
+// initial load there is nothing to restore, so the first failure is returned.
+func (cm *ConfigManager) applySections(next *viper.Viper) (*viper.Viper, []*SectionError, error) {
+	var rejected []*SectionError
+	for _, sec := range cm.sections {
+		if err := cm.decodeSchema(next, sec.key, sec.schema); err != nil {
+			if !cm.loaded {
+				return nil, nil, &SectionError{Section: sec.key, Err: err}
+			}
+			rejected = append(rejected, &SectionError{Section: sec.key, Err: err})
+		}
+	}
+	if len(rejected) == 0 {
+		return next, nil, nil
+	}
+
+	// Rebuild the settings with the rejected subtrees taken from the live config.
+	settings := next.AllSettings()
+	previous := cm.viper.AllSettings()
+	for _, r := range rejected {
+		path := cm.splitKey(r.Section)
+		if old, ok := lookupPath(previous, path); ok {
+			setPath(settings, path, old)
+		} else {
+			deletePath(settings, path)
+		}
+		cm.logger.Warn("Rejected configuration section, keeping previous values",
+			zap.String("section", r.Section),
+			zap.Error(r.Err))
+	}
+
+	rebuilt := cm.newViper()
+	if err := rebuilt.MergeConfigMap(settings); err != nil {
+		return nil, nil, err
+	}
+	return rebuilt, rejected, nil
+}
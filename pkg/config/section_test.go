@@ -0,0 +1,148 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type testServerSection struct {
+	Port int    `mapstructure:"port" validate:"required,min=1,max=65535"`
+	Host string `mapstructure:"host" validate:"required"`
+}
+
+type testDatabaseSection struct {
+	Name     string `mapstructure:"name" validate:"required"`
+	MaxConns int    `mapstructure:"maxConns" validate:"required,min=1"`
+}
+
+func TestPartialApply(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	logger, _ := zap.NewDevelopment()
+	cfg := New(configPath, logger)
+	server := &testServerSection{}
+	database := &testDatabaseSection{}
+	cfg.RegisterSection("server", server)
+	cfg.RegisterSection("database", database)
+
+	require.NoError(t, cfg.Load())
+	assert.Equal(t, 8080, server.Port)
+	assert.Equal(t, 10, database.MaxConns)
+
+	t.Run("Invalid Section Keeps Previous Values", func(t *testing.T) {
+		content := []byte(`
+server:
+  port: 9090
+  host: "localhost"
+database:
+  name: "testdb"
+  maxConns: 0
+  extra: true
+`)
+		require.NoError(t, os.WriteFile(configPath, content, 0644))
+
+		err := cfg.Load()
+		var partial *PartialApplyError
+		require.ErrorAs(t, err, &partial)
+		require.Len(t, partial.Rejected, 1)
+		assert.Equal(t, "database", partial.Rejected[0].Section)
+
+		// Valid section applied
+		assert.Equal(t, 9090, cfg.GetInt("server.port"))
+		assert.Equal(t, 9090, server.Port)
+
+		// Rejected section retains previous values, new keys are dropped
+		assert.Equal(t, 10, cfg.GetInt("database.maxConns"))
+		assert.Equal(t, 5432, cfg.GetInt("database.port"))
+		assert.False(t, cfg.IsSet("database.extra"))
+		assert.Equal(t, 10, database.MaxConns)
+	})
+
+	t.Run("Valid Reload Clears Rejection", func(t *testing.T) {
+		content := []byte(`
+server:
+  port: 9091
+  host: "localhost"
+database:
+  name: "otherdb"
+  maxConns: 20
+`)
+		require.NoError(t, os.WriteFile(configPath, content, 0644))
+
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, "otherdb", cfg.GetString("database.name"))
+		assert.Equal(t, 20, database.MaxConns)
+	})
+}
+
+func TestPartialApplyInitialLoad(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := []byte(`
+server:
+  port: 8080
+`)
+	require.NoError(t, os.WriteFile(configPath, content, 0644))
+
+	logger, _ := zap.NewDevelopment()
+	cfg := New(configPath, logger)
+	cfg.RegisterSection("server", &testServerSection{})
+
+	// Nothing to fall back to on the first load.
+	err := cfg.Load()
+	var sectionErr *SectionError
+	require.ErrorAs(t, err, &sectionErr)
+	assert.Equal(t, "server", sectionErr.Section)
+	assert.False(t, cfg.IsSet("server.port"))
+}
+
+func TestPartialApplyIsApplied(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	tracer, meter := &fakeTracer{}, newFakeMeter()
+	cfg := New(configPath, zap.NewNop(), WithTracer(tracer), WithMeter(meter))
+	cfg.RegisterSection("database", &testDatabaseSection{})
+	require.NoError(t, cfg.Load())
+
+	// Freeze on the next applied load.
+	WithFrozen()(cfg)
+	content := []byte(`
+server:
+  port: 9090
+database:
+  name: "testdb"
+  maxConns: 0
+`)
+	require.NoError(t, os.WriteFile(configPath, content, 0644))
+	var partial *PartialApplyError
+	require.ErrorAs(t, cfg.Load(), &partial)
+	assert.Equal(t, 9090, cfg.GetInt("server.port"))
+
+	assert.True(t, cfg.Frozen())
+	stats := cfg.Stats()
+	assert.Equal(t, uint64(2), stats.Loads)
+	assert.Zero(t, stats.LoadErrors)
+	assert.Equal(t, uint64(1), stats.PartialLoads)
+	assert.Equal(t, uint64(1), stats.RejectedSections)
+	assert.Equal(t, uint64(2), stats.Generation)
+
+	var span *fakeSpan
+	for _, s := range tracer.spans {
+		if s.name == SpanLoad {
+			span = s
+		}
+	}
+	require.NotNil(t, span)
+	assert.NoError(t, span.err)
+	assert.True(t, span.ended)
+	assert.Equal(t, int64(2), span.attrs["config.generation"])
+	assert.NotEmpty(t, span.attrs["config.hash"])
+	assert.Equal(t, "database", span.attrs["config.sections.rejected"])
+	assert.Equal(t, "partial", meter.durations[MetricLoadDuration][1]["config.result"])
+}
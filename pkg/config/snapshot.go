@@ -0,0 +1,50 @@
+package config
+
+import (
+	"reflect"
+
+	"github.com/spf13/viper"
+)
+
+// Schema returns the WithSchema struct of the last applied load as an
+// immutable copy. Unlike GetSchema, which returns the struct reloads
+// overwrite in place, the copy is never modified, so it can be read without
+// locking while watch-triggered reloads run; call Schema again to see
+// later versions. Each copy has passed validation. It returns nil before
+// the first load or if T is not the schema's struct type. Callers must not
+// modify the copy.
+func Schema[T any](cm *ConfigManager) *T {
+	s, _ := cm.schemaSnapshot.Load().(*T)
+	return s
+}
+
+// storeSchemaSnapshot publishes a copy of the schema just decoded for
+// Schema. Reloads replace the fields of cm.schema rather than mutating what
+// they point to, so a shallow copy is not affected by later loads. The
+// caller must hold cm.mu.
+func (cm *ConfigManager) storeSchemaSnapshot() {
+	rv := reflect.ValueOf(cm.schema)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return
+	}
+	cm.schemaSnapshot.Store(copyStruct(cm.schema))
+}
+
+// storeViper installs v as the live configuration. Getters read it through
+// current without taking cm.mu, so v must not be modified afterwards: loads
+// stage a fresh instance and swap it in whole. The caller must hold cm.mu.
+func (cm *ConfigManager) storeViper(v *viper.Viper) {
+	cm.viper = v
+	live := &liveConfig{viper: v}
+	if cm.envPrefix != "" && automaticEnv(cm.precedence) {
+		live.envPrefix, live.envDelim = cm.envPrefix, cm.keyDelimiter
+	}
+	cm.live.Store(live)
+}
+
+// current returns the live configuration for lock-free reads. It may be
+// replaced by a concurrent load, so a getter reading several values should
+// call it once.
+func (cm *ConfigManager) current() *viper.Viper {
+	return cm.live.Load().viper
+}
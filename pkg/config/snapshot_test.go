@@ -0,0 +1,172 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSchemaSnapshot(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	writePort := func(port int) {
+		content := "server:\n  port: " + strconv.Itoa(port) + "\n  host: localhost\n  timeout: 30s\n" +
+			"database:\n  host: db\n  port: 5432\n  name: app\n  maxConns: 5\n"
+		require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+	}
+
+	cfg := New(configPath, zap.NewNop(), WithSchema(&TestConfig{}))
+	assert.Nil(t, Schema[TestConfig](cfg), "nil before the first load")
+	writePort(1000)
+	require.NoError(t, cfg.Load())
+
+	first := Schema[TestConfig](cfg)
+	require.NotNil(t, first)
+	assert.Equal(t, 1000, first.Server.Port)
+	assert.Nil(t, Schema[AppConfig](cfg), "nil for another type")
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			s := Schema[TestConfig](cfg)
+			assert.GreaterOrEqual(t, s.Server.Port, 1000)
+			assert.Equal(t, "db", s.Database.Host)
+		}
+	}()
+	for port := 1001; port <= 1020; port++ {
+		writePort(port)
+		require.NoError(t, cfg.Load())
+	}
+	close(stop)
+	wg.Wait()
+
+	assert.Equal(t, 1000, first.Server.Port, "earlier copies are never modified")
+	assert.Equal(t, 1020, Schema[TestConfig](cfg).Server.Port)
+
+	// A rejected reload keeps the last valid copy.
+	require.NoError(t, os.WriteFile(configPath, []byte("server:\n  port: -1\n"), 0644))
+	require.Error(t, cfg.Load())
+	assert.Equal(t, 1020, Schema[TestConfig](cfg).Server.Port)
+}
+
+func TestLockFreeReads(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+	cfg := New(configPath, zap.NewNop())
+	require.NoError(t, cfg.Load())
+
+	// Getters must not wait for a writer holding the lock, e.g. a slow load.
+	cfg.mu.Lock()
+	done := make(chan int)
+	go func() { done <- cfg.GetInt("server.port") }()
+	select {
+	case port := <-done:
+		assert.Equal(t, 8080, port)
+	case <-time.After(2 * time.Second):
+		t.Fatal("GetInt blocked on the manager's lock")
+	}
+	cfg.mu.Unlock()
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			port := cfg.GetInt("server.port")
+			assert.True(t, port == 8080 || port >= 9000, "port %d", port)
+			assert.Equal(t, "localhost", cfg.GetString("server.host"))
+		}
+	}()
+	for port := 9000; port < 9050; port++ {
+		require.NoError(t, cfg.Set("server.port", port))
+	}
+	close(stop)
+	wg.Wait()
+	assert.Equal(t, 9049, cfg.GetInt("server.port"))
+}
+
+func newBenchmarkConfig(b *testing.B) *ConfigManager {
+	b.Helper()
+	path := filepath.Join(b.TempDir(), "config.yaml")
+	content := "server:\n  port: 8080\n  host: localhost\n  timeout: 30s\n"
+	require.NoError(b, os.WriteFile(path, []byte(content), 0644))
+	cfg := New(path, zap.NewNop())
+	require.NoError(b, cfg.Load())
+	b.Cleanup(func() { cfg.Close() })
+	return cfg
+}
+
+// BenchmarkGetInt compares getters with reads under the manager's lock, as
+// getters did before snapshots, idle and while runtime changes reload the
+// configuration.
+func BenchmarkGetInt(b *testing.B) {
+	reads := map[string]func(cfg *ConfigManager){
+		"Snapshot": func(cfg *ConfigManager) { cfg.GetInt("server.port") },
+		"Locked": func(cfg *ConfigManager) {
+			cfg.mu.RLock()
+			cfg.viper.GetInt("server.port")
+			cfg.mu.RUnlock()
+		},
+	}
+	for _, name := range []string{"Snapshot", "Locked"} {
+		read := reads[name]
+		b.Run(name+"/Idle", func(b *testing.B) {
+			benchmarkReads(b, read, false)
+		})
+		b.Run(name+"/Reloading", func(b *testing.B) {
+			benchmarkReads(b, read, true)
+		})
+	}
+}
+
+func benchmarkReads(b *testing.B, read func(*ConfigManager), reload bool) {
+	cfg := newBenchmarkConfig(b)
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	if reload {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for port := 9000; ; port++ {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				_ = cfg.Set("server.port", port)
+			}
+		}()
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			read(cfg)
+		}
+	})
+	b.StopTimer()
+	close(stop)
+	wg.Wait()
+}
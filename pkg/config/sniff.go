@@ -0,0 +1,44 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"regexp"
+)
+
+var (
+	tomlTableRe = regexp.MustCompile(`^\[{1,2}[A-Za-z0-9_.\-"' ]+\]{1,2}\s*(#.*)?$`)
+	tomlKeyRe   = regexp.MustCompile(`^[A-Za-z0-9_\-."']+\s*=`)
+)
+
+// sniffFormat guesses the format of a config document without a file
+// extension, as with ConfigMap keys mounted as plain "config" files.
+// Objects are JSON (or JSONC, then JSON5, when they don't parse strictly),
+// table headers and key = value pairs are TOML, and anything else is
+// treated as YAML.
+func sniffFormat(data []byte) string {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		if json.Valid(trimmed) {
+			return "json"
+		}
+		if json.Valid(stripJSONC(trimmed)) {
+			return "jsonc"
+		}
+		return "json5"
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(trimmed))
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		if tomlTableRe.Match(line) || tomlKeyRe.Match(line) {
+			return "toml"
+		}
+		break
+	}
+	return "yaml"
+}
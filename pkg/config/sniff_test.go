@@ -0,0 +1,61 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSniffFormat(t *testing.T) {
+	cases := map[string]string{
+		`{"server": {"port": 8080}}`:           "json",
+		"{\n  // comment\n  \"a\": 1,\n}":      "jsonc",
+		"{server: {port: 0x1F90}}":             "json5",
+		"# comment\n[server]\nport = 8080\n":   "toml",
+		"[[servers]]\nport = 8080\n":           "toml",
+		"title = \"app\"\n":                    "toml",
+		"server:\n  port: 8080\n":              "yaml",
+		"---\nserver:\n  port: 8080\n":         "yaml",
+		"# key = value in a comment\nkey: 1\n": "yaml",
+		"\xef\xbb\xbf{\"a\": 1}":               "json",
+		"- not a map\n":                        "yaml",
+	}
+	for content, want := range cases {
+		assert.Equal(t, want, sniffFormat([]byte(content)), content)
+	}
+}
+
+func TestExtensionlessConfigFile(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	contents := map[string]string{
+		"json": `{"server": {"port": 8080}}`,
+		"toml": "[server]\nport = 8080\n",
+		"yaml": "server:\n  port: 8080\n",
+	}
+	for format, content := range contents {
+		t.Run(format, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config")
+			require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+
+			cfg := New(configPath, logger)
+			require.NoError(t, cfg.Load())
+			assert.Equal(t, 8080, cfg.GetInt("server.port"))
+		})
+	}
+
+	t.Run("Explicit Config Type", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "settings.conf")
+		require.NoError(t, os.WriteFile(configPath, []byte("server:\n  port: 8080\n"), 0644))
+
+		cfg := New(configPath, logger, WithConfigType("yaml"))
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+
+		cfg = New(configPath, logger)
+		assert.Error(t, cfg.Load(), "unknown extension without override")
+	})
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"sync"
+	"time"
+)
+
+// WatchStatus describes the health of the manager's watcher, so dashboards
+// can tell "no changes" apart from a watcher that has silently died.
+type WatchStatus struct {
+	// Active reports whether a watcher goroutine is running.
+	Active bool
+	// Paused reports whether reloads are suspended by PauseWatch.
+	Paused bool
+	// Mode is "file" or "remote-poll", or empty if Watch was never called.
+	Mode string
+	// LastSuccess is the time of the last successful remote poll, or of the
+	// last file change seen.
+	LastSuccess time.Time
+	// ConsecutiveFailures counts failed polls or watcher errors since the
+	// last success; LastError is the most recent one.
+	ConsecutiveFailures int
+	LastError           error
+	// NextPoll is when the remote source is polled next; zero for files.
+	NextPoll time.Time
+}
+
+// watchState records a watcher's health. A nil *watchState ignores updates.
+type watchState struct {
+	mu     sync.Mutex
+	status WatchStatus
+}
+
+func (s *watchState) update(fn func(st *WatchStatus)) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(&s.status)
+}
+
+func (s *watchState) started(mode string) {
+	s.update(func(st *WatchStatus) {
+		st.Active, st.Mode = true, mode
+	})
+}
+
+func (s *watchState) stopped() {
+	s.update(func(st *WatchStatus) {
+		st.Active, st.NextPoll = false, time.Time{}
+	})
+}
+
+func (s *watchState) succeeded() {
+	s.update(func(st *WatchStatus) {
+		st.LastSuccess = time.Now()
+		st.ConsecutiveFailures, st.LastError = 0, nil
+	})
+}
+
+func (s *watchState) failed(err error) {
+	s.update(func(st *WatchStatus) {
+		st.ConsecutiveFailures++
+		st.LastError = err
+	})
+}
+
+func (s *watchState) scheduled(next time.Time) {
+	s.update(func(st *WatchStatus) {
+		st.NextPoll = next
+	})
+}
+
+// WatchStatus returns the current health of the watcher.
+func (cm *ConfigManager) WatchStatus() WatchStatus {
+	cm.watchState.mu.Lock()
+	status := cm.watchState.status
+	cm.watchState.mu.Unlock()
+	status.Paused = cm.WatchPaused()
+	return status
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWatchStatus(t *testing.T) {
+	t.Run("Remote Poll", func(t *testing.T) {
+		remote := useFakeRemote(t)
+		remote.set("app/config", []byte(`{"server": {"port": 8080}}`))
+
+		cfg := newRemoteTestConfig(WithWatcher(), WithPollInterval(5*time.Millisecond))
+		require.NoError(t, cfg.Load())
+		assert.False(t, cfg.WatchStatus().Active)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		require.NoError(t, cfg.Watch(ctx, func() {}))
+		require.Eventually(t, func() bool { return !cfg.WatchStatus().LastSuccess.IsZero() }, time.Second, time.Millisecond)
+
+		status := cfg.WatchStatus()
+		assert.True(t, status.Active)
+		assert.Equal(t, "remote-poll", status.Mode)
+		assert.Zero(t, status.ConsecutiveFailures)
+		assert.False(t, status.NextPoll.IsZero())
+
+		remote.mu.Lock()
+		delete(remote.docs, "app/config")
+		remote.mu.Unlock()
+		require.Eventually(t, func() bool { return cfg.WatchStatus().ConsecutiveFailures >= 2 }, time.Second, time.Millisecond)
+		assert.Error(t, cfg.WatchStatus().LastError)
+
+		require.NoError(t, cfg.Close())
+		assert.False(t, cfg.WatchStatus().Active)
+	})
+
+	t.Run("File", func(t *testing.T) {
+		configPath, cleanup := setupTestConfig(t)
+		defer cleanup()
+
+		cfg := New(configPath, zap.NewNop())
+		defer cfg.Close()
+		require.NoError(t, cfg.Load())
+		require.NoError(t, cfg.Watch(context.Background(), func() {}))
+		cfg.PauseWatch()
+
+		status := cfg.WatchStatus()
+		assert.True(t, status.Active)
+		assert.True(t, status.Paused)
+		assert.Equal(t, "file", status.Mode)
+		assert.True(t, status.NextPoll.IsZero())
+
+		content, err := os.ReadFile(configPath)
+		require.NoError(t, err)
+		require.NoError(t, writeFileAtomic(configPath, content))
+		require.Eventually(t, func() bool { return !cfg.WatchStatus().LastSuccess.IsZero() }, time.Second, time.Millisecond)
+	})
+}
@@ -0,0 +1,250 @@
+package config
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// SubConfig is a Config whose keys are relative to a prefix of its parent,
+// so a library can accept a Config for just its section and be reused
+// across applications that nest it under different keys. It reads the
+// parent's live configuration and shares its reloads and watcher.
+type SubConfig struct {
+	parent *ConfigManager
+	prefix string
+}
+
+// Sub returns a Config for the subtree at prefix, e.g. Sub("storage") reads
+// "storage.elasticsearch.index" as "elasticsearch.index".
+func (cm *ConfigManager) Sub(prefix string) *SubConfig {
+	return &SubConfig{parent: cm, prefix: strings.ToLower(strings.Trim(prefix, cm.keyDelimiter))}
+}
+
+// Sub returns a Config for the subtree at prefix relative to this view.
+func (s *SubConfig) Sub(prefix string) *SubConfig {
+	return s.parent.Sub(s.key(prefix))
+}
+
+// key returns the parent key for a key relative to the view.
+func (s *SubConfig) key(key string) string {
+	delim := s.parent.keyDelimiter
+	key = strings.Trim(key, delim)
+	if s.prefix == "" {
+		return key
+	}
+	if key == "" {
+		return s.prefix
+	}
+	return s.prefix + delim + key
+}
+
+// decodeHooks returns the parent's decode hooks.
+func (s *SubConfig) decodeHooks() mapstructure.DecodeHookFunc {
+	return s.parent.decodeHooks()
+}
+
+// Load reloads the parent configuration.
+func (s *SubConfig) Load() error {
+	return s.parent.Load()
+}
+
+// Get returns a value for the given key.
+func (s *SubConfig) Get(key string) interface{} {
+	return s.parent.Get(s.key(key))
+}
+
+// GetString returns a string value for the given key.
+func (s *SubConfig) GetString(key string) string {
+	return s.parent.GetString(s.key(key))
+}
+
+// GetInt returns an int value for the given key.
+func (s *SubConfig) GetInt(key string) int {
+	return s.parent.GetInt(s.key(key))
+}
+
+// GetFloat64 returns a float64 value for the given key.
+func (s *SubConfig) GetFloat64(key string) float64 {
+	return s.parent.GetFloat64(s.key(key))
+}
+
+// GetBool returns a bool value for the given key.
+func (s *SubConfig) GetBool(key string) bool {
+	return s.parent.GetBool(s.key(key))
+}
+
+// GetStringSlice returns a string slice value for the given key.
+func (s *SubConfig) GetStringSlice(key string) []string {
+	return s.parent.GetStringSlice(s.key(key))
+}
+
+// GetStringMap returns a map value for the given key.
+func (s *SubConfig) GetStringMap(key string) map[string]interface{} {
+	return s.parent.GetStringMap(s.key(key))
+}
+
+// GetDuration returns a duration value for the given key.
+func (s *SubConfig) GetDuration(key string) time.Duration {
+	return s.parent.GetDuration(s.key(key))
+}
+
+// GetTime returns a time value for the given key.
+func (s *SubConfig) GetTime(key string) time.Time {
+	return s.parent.GetTime(s.key(key))
+}
+
+// GetInt32 returns an int32 value for the given key.
+func (s *SubConfig) GetInt32(key string) int32 {
+	return s.parent.GetInt32(s.key(key))
+}
+
+// GetInt64 returns an int64 value for the given key.
+func (s *SubConfig) GetInt64(key string) int64 {
+	return s.parent.GetInt64(s.key(key))
+}
+
+// GetUint returns a uint value for the given key.
+func (s *SubConfig) GetUint(key string) uint {
+	return s.parent.GetUint(s.key(key))
+}
+
+// GetUint16 returns a uint16 value for the given key.
+func (s *SubConfig) GetUint16(key string) uint16 {
+	return s.parent.GetUint16(s.key(key))
+}
+
+// GetUint32 returns a uint32 value for the given key.
+func (s *SubConfig) GetUint32(key string) uint32 {
+	return s.parent.GetUint32(s.key(key))
+}
+
+// GetUint64 returns a uint64 value for the given key.
+func (s *SubConfig) GetUint64(key string) uint64 {
+	return s.parent.GetUint64(s.key(key))
+}
+
+// GetIntSlice returns an int slice value for the given key.
+func (s *SubConfig) GetIntSlice(key string) []int {
+	return s.parent.GetIntSlice(s.key(key))
+}
+
+// GetStringMapString returns a string map value for the given key.
+func (s *SubConfig) GetStringMapString(key string) map[string]string {
+	return s.parent.GetStringMapString(s.key(key))
+}
+
+// GetStringMapStringSlice returns a string slice map value for the given key.
+func (s *SubConfig) GetStringMapStringSlice(key string) map[string][]string {
+	return s.parent.GetStringMapStringSlice(s.key(key))
+}
+
+// GetSizeInBytes returns the value for the given key as a number of bytes.
+func (s *SubConfig) GetSizeInBytes(key string) uint {
+	return s.parent.GetSizeInBytes(s.key(key))
+}
+
+// IsSet checks if the key is set.
+func (s *SubConfig) IsSet(key string) bool {
+	return s.parent.IsSet(s.key(key))
+}
+
+// Lookup returns the value for the given key and whether the key is set.
+func (s *SubConfig) Lookup(key string) (interface{}, bool) {
+	return s.parent.Lookup(s.key(key))
+}
+
+// GetStringOk returns a string value for the given key and whether the key
+// is set.
+func (s *SubConfig) GetStringOk(key string) (string, bool) {
+	return s.parent.GetStringOk(s.key(key))
+}
+
+// GetIntOk returns an int value for the given key and whether the key is
+// set.
+func (s *SubConfig) GetIntOk(key string) (int, bool) {
+	return s.parent.GetIntOk(s.key(key))
+}
+
+// GetFloat64Ok returns a float64 value for the given key and whether the
+// key is set.
+func (s *SubConfig) GetFloat64Ok(key string) (float64, bool) {
+	return s.parent.GetFloat64Ok(s.key(key))
+}
+
+// GetBoolOk returns a bool value for the given key and whether the key is
+// set.
+func (s *SubConfig) GetBoolOk(key string) (bool, bool) {
+	return s.parent.GetBoolOk(s.key(key))
+}
+
+// GetDurationOk returns a duration value for the given key and whether the
+// key is set.
+func (s *SubConfig) GetDurationOk(key string) (time.Duration, bool) {
+	return s.parent.GetDurationOk(s.key(key))
+}
+
+// GetSchema always returns nil: the application schema spans the whole
+// configuration.
+func (s *SubConfig) GetSchema() interface{} {
+	return nil
+}
+
+// UnmarshalKey decodes the subtree at key into out; an empty key decodes
+// the whole view.
+func (s *SubConfig) UnmarshalKey(key string, out interface{}) error {
+	return s.parent.UnmarshalKey(s.key(key), out)
+}
+
+// Watch calls onChange after every reload of the parent that changes a key
+// inside the view, until ctx is done.
+func (s *SubConfig) Watch(ctx context.Context, onChange func()) error {
+	return s.parent.WatchEvents(ctx, func(event ChangeEvent) {
+		for _, c := range event.Changes {
+			if s.prefix == "" || c.Key == s.prefix || strings.HasPrefix(c.Key, s.prefix+s.parent.keyDelimiter) {
+				onChange()
+				return
+			}
+		}
+	})
+}
+
+// AllKeys returns the keys inside the view, relative to its prefix.
+func (s *SubConfig) AllKeys() []string {
+	var keys []string
+	for _, key := range s.parent.AllKeys() {
+		if s.prefix == "" {
+			keys = append(keys, key)
+		} else if rel, ok := strings.CutPrefix(key, s.prefix+s.parent.keyDelimiter); ok {
+			keys = append(keys, rel)
+		}
+	}
+	return keys
+}
+
+// AllSettings returns a deep copy of the settings inside the view as a
+// nested map.
+func (s *SubConfig) AllSettings() map[string]interface{} {
+	settings := make(map[string]interface{})
+	for _, key := range s.AllKeys() {
+		setPath(settings, s.parent.splitKey(key), deepCopyValue(s.Get(key)))
+	}
+	return settings
+}
+
+// Set applies a runtime override of key inside the view.
+func (s *SubConfig) Set(key string, value interface{}) error {
+	return s.parent.Set(s.key(key), value)
+}
+
+// SetDefault sets a runtime default for key inside the view.
+func (s *SubConfig) SetDefault(key string, value interface{}) error {
+	return s.parent.SetDefault(s.key(key), value)
+}
+
+// Unset removes runtime changes to key inside the view.
+func (s *SubConfig) Unset(key string) error {
+	return s.parent.Unset(s.key(key))
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSub(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	cfg := New(configPath, zap.NewNop())
+	defer cfg.Close()
+	require.NoError(t, cfg.Load())
+
+	var db Config = cfg.Sub("database")
+
+	t.Run("Relative Keys", func(t *testing.T) {
+		assert.Equal(t, "127.0.0.1", db.GetString("host"))
+		assert.Equal(t, 5432, db.GetInt("port"))
+		assert.True(t, db.IsSet("name"))
+		assert.False(t, db.IsSet("server.port"))
+		assert.ElementsMatch(t, []string{"host", "port", "name", "maxconns"}, db.AllKeys())
+		assert.Equal(t, "testdb", db.AllSettings()["name"])
+		assert.Nil(t, db.GetSchema())
+
+		var whole struct {
+			Host string `mapstructure:"host"`
+		}
+		require.NoError(t, db.UnmarshalKey("", &whole))
+		assert.Equal(t, "127.0.0.1", whole.Host)
+	})
+
+	t.Run("Nested", func(t *testing.T) {
+		assert.Equal(t, 8080, cfg.Sub("").Sub("server").GetInt("port"))
+	})
+
+	t.Run("Writes Go To Parent", func(t *testing.T) {
+		require.NoError(t, db.Set("port", 6543))
+		assert.Equal(t, 6543, cfg.GetInt("database.port"))
+		require.NoError(t, db.Unset("port"))
+		assert.Equal(t, 5432, cfg.GetInt("database.port"))
+	})
+
+	t.Run("Watch Sees Only Its Section", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		changed := make(chan struct{}, 10)
+		require.NoError(t, db.Watch(ctx, func() { changed <- struct{}{} }))
+
+		content, err := os.ReadFile(configPath)
+		require.NoError(t, err)
+		require.NoError(t, writeFileAtomic(configPath, append(content, []byte("extra: true\n")...)))
+		select {
+		case <-changed:
+			t.Fatal("unrelated change woke the section watcher")
+		case <-time.After(300 * time.Millisecond):
+		}
+
+		updated := strings.Replace(string(content), "maxConns: 10", "maxConns: 20", 1)
+		require.NoError(t, writeFileAtomic(configPath, []byte(updated)))
+		select {
+		case <-changed:
+		case <-time.After(2 * time.Second):
+			t.Fatal("timeout waiting for section change")
+		}
+		assert.Equal(t, 20, db.GetInt("maxconns"))
+	})
+}
@@ -0,0 +1,304 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// subscriberSet holds the callbacks registered through Subscribe.
+type subscriberSet struct {
+	mu      sync.Mutex
+	nextID  uint64
+	subs    map[uint64]subscriber
+	started bool
+}
+
+// subscriber is a registered callback. name is the function the caller
+// passed in, which differs from fn for the wrappers of OnKeyChange and the
+// other typed helpers; it labels slow-callback warnings and metrics.
+type subscriber struct {
+	fn   func(ChangeEvent)
+	name string
+}
+
+// Subscribe registers onChange to receive a ChangeEvent for every reload
+// triggered by the watcher, as WatchEvents does, and returns a function
+// that removes it. Any number of modules may subscribe independently: the
+// manager watches its source once and fans each event out to every
+// subscriber in registration order. The watcher is shared with Watch and
+// WatchEvents, so subscribing works whether or not they are in use. A
+// subscriber that panics is logged and does not affect the others or later
+// events.
+func (cm *ConfigManager) Subscribe(onChange func(ChangeEvent)) (unsubscribe func()) {
+	return cm.subscribe(callbackName(onChange), onChange)
+}
+
+// subscribe registers onChange under name, the caller's callback it wraps.
+func (cm *ConfigManager) subscribe(name string, onChange func(ChangeEvent)) (unsubscribe func()) {
+	s := &cm.subscribers
+	s.mu.Lock()
+	if s.subs == nil {
+		s.subs = make(map[uint64]subscriber)
+	}
+	s.nextID++
+	id := s.nextID
+	s.subs[id] = subscriber{fn: onChange, name: name}
+	start := !s.started
+	s.started = true
+	s.mu.Unlock()
+
+	if start {
+		if err := cm.WatchEvents(context.Background(), cm.publish); err != nil {
+			cm.logger.Error("Failed to start watching for subscribers", zap.Error(err))
+			s.mu.Lock()
+			s.started = false
+			s.mu.Unlock()
+		}
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			delete(s.subs, id)
+			s.mu.Unlock()
+		})
+	}
+}
+
+// publish delivers event to every current subscriber.
+func (cm *ConfigManager) publish(event ChangeEvent) {
+	s := &cm.subscribers
+	s.mu.Lock()
+	ids := make([]uint64, 0, len(s.subs))
+	for id := range s.subs {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	subs := make([]subscriber, len(ids))
+	for i, id := range ids {
+		subs[i] = s.subs[id]
+	}
+	s.mu.Unlock()
+
+	for i, sub := range subs {
+		cm.notify(ids[i], sub, event)
+	}
+	if !event.Time.IsZero() {
+		cm.telemetry.recordDuration(MetricReloadLatency, event.Time, event.Err)
+	}
+}
+
+// notify calls fn, recovering from a panic so one subscriber cannot break
+// delivery to the others, and records how long it took.
+func (cm *ConfigManager) notify(id uint64, sub subscriber, event ChangeEvent) {
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			cm.logger.Error("Change subscriber panicked",
+				zap.Uint64("subscriber", id),
+				zap.Any("panic", r),
+				zap.Stack("stack"))
+		}
+		cm.observeCallback(id, sub.name, event, start)
+	}()
+	sub.fn(event)
+}
+
+// OnKeyChange calls onChange with the old and new value whenever a reload
+// changes key, and returns a function that removes it. A nil old value
+// means the key was added, a nil new value that it was removed.
+// Reloads that leave key alone do not wake the callback.
+func (cm *ConfigManager) OnKeyChange(key string, onChange func(old, new interface{})) (unsubscribe func()) {
+	return cm.onKeyChange(key, callbackName(onChange), onChange)
+}
+
+// onKeyChange is OnKeyChange with the subscriber named name.
+func (cm *ConfigManager) onKeyChange(key, name string, onChange func(old, new interface{})) (unsubscribe func()) {
+	key = strings.ToLower(key)
+	return cm.subscribe(name, func(event ChangeEvent) {
+		for _, c := range event.Changes {
+			if c.Key == key {
+				onChange(c.Old, c.New)
+				return
+			}
+		}
+	})
+}
+
+// OnKeyChangeAs calls onChange with the new value of key converted to T,
+// the way Get converts it, whenever a reload changes key, and returns a
+// function that removes it. The value is read after the reload, so a removed
+// key falls back to its default; a key left without a value, or one that
+// cannot be converted to T, is logged and skipped.
+func OnKeyChangeAs[T any](cm *ConfigManager, key string, onChange func(T)) (unsubscribe func()) {
+	return cm.onKeyChange(key, callbackName(onChange), func(_, _ interface{}) {
+		v, err := Get[T](cm, key)
+		if err != nil {
+			cm.logger.Warn("Skipped key change callback", zap.String("key", key), zap.Error(err))
+			return
+		}
+		onChange(v)
+	})
+}
+
+// BindInt calls onChange with the new int value of key whenever a reload
+// changes it, so components such as worker pools can resize themselves. It
+// returns a function that removes the callback; see OnKeyChangeAs.
+func (cm *ConfigManager) BindInt(key string, onChange func(int)) (unsubscribe func()) {
+	return OnKeyChangeAs(cm, key, onChange)
+}
+
+// BindString calls onChange with the new string value of key whenever a
+// reload changes it; see OnKeyChangeAs.
+func (cm *ConfigManager) BindString(key string, onChange func(string)) (unsubscribe func()) {
+	return OnKeyChangeAs(cm, key, onChange)
+}
+
+// BindBool calls onChange with the new bool value of key whenever a reload
+// changes it; see OnKeyChangeAs.
+func (cm *ConfigManager) BindBool(key string, onChange func(bool)) (unsubscribe func()) {
+	return OnKeyChangeAs(cm, key, onChange)
+}
+
+// BindFloat64 calls onChange with the new float64 value of key whenever a
+// reload changes it; see OnKeyChangeAs.
+func (cm *ConfigManager) BindFloat64(key string, onChange func(float64)) (unsubscribe func()) {
+	return OnKeyChangeAs(cm, key, onChange)
+}
+
+// BindDuration calls onChange with the new duration value of key whenever a
+// reload changes it; see OnKeyChangeAs.
+func (cm *ConfigManager) BindDuration(key string, onChange func(time.Duration)) (unsubscribe func()) {
+	return OnKeyChangeAs(cm, key, onChange)
+}
+
+// BindStringSlice calls onChange with the new string slice value of key
+// whenever a reload changes it; see OnKeyChangeAs.
+func (cm *ConfigManager) BindStringSlice(key string, onChange func([]string)) (unsubscribe func()) {
+	return OnKeyChangeAs(cm, key, onChange)
+}
+
+// OnPrefixChange calls onChange with the changes to keys starting with
+// prefix, e.g. "database.", whenever a reload changes at least one of them,
+// and returns a function that removes it.
+func (cm *ConfigManager) OnPrefixChange(prefix string, onChange func([]Change)) (unsubscribe func()) {
+	prefix = strings.ToLower(prefix)
+	return cm.subscribe(callbackName(onChange), func(event ChangeEvent) {
+		var matched []Change
+		for _, c := range event.Changes {
+			if strings.HasPrefix(c.Key, prefix) {
+				matched = append(matched, c)
+			}
+		}
+		if len(matched) > 0 {
+			onChange(matched)
+		}
+	})
+}
+
+// changesBuffer is the number of events Changes buffers for a slow reader.
+const changesBuffer = 16
+
+// Changes returns a channel receiving a ChangeEvent for every reload
+// triggered by the watcher, for consumers built around select loops. The
+// channel is closed when ctx is done or the manager is closed. Events are
+// sent from the manager's goroutine, never re-entering consumer code; if the
+// reader falls more than 16 events behind, the oldest are dropped.
+func (cm *ConfigManager) Changes(ctx context.Context) <-chan ChangeEvent {
+	ch := make(chan ChangeEvent, changesBuffer)
+	var mu sync.Mutex
+	closed := false
+
+	// The channel's reader is named after the function that called Changes.
+	unsubscribe := cm.subscribe(callerName(), func(event ChangeEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		if closed {
+			return
+		}
+		for {
+			select {
+			case ch <- event:
+				return
+			default:
+			}
+			// Make room by dropping the oldest buffered event.
+			select {
+			case <-ch:
+				cm.logger.Warn("Dropped change event for slow reader")
+			default:
+			}
+		}
+	})
+	closeCh := func() {
+		unsubscribe()
+		mu.Lock()
+		closed = true
+		close(ch)
+		mu.Unlock()
+	}
+
+	started := cm.sup.Go("changes", func(supCtx context.Context) {
+		select {
+		case <-ctx.Done():
+		case <-supCtx.Done():
+		}
+		closeCh()
+	})
+	if !started {
+		closeCh()
+	}
+	return ch
+}
+
+// WatchSchema calls onChange with copies of the WithSchema struct before
+// and after every reload that changes the configuration, so consumers get
+// typed before/after views instead of re-casting GetSchema. T must be the
+// schema's struct type. It stops when ctx is done or the manager is closed.
+func WatchSchema[T any](ctx context.Context, cm *ConfigManager, onChange func(old, new *T)) error {
+	current, ok := cm.GetSchema().(*T)
+	if !ok || current == nil {
+		var want *T
+		return fmt.Errorf("schema is %T, not %T", cm.GetSchema(), want)
+	}
+	snapshot := func() *T {
+		cm.mu.RLock()
+		defer cm.mu.RUnlock()
+		copied := *current
+		return &copied
+	}
+
+	var mu sync.Mutex
+	last := snapshot()
+	unsubscribe := cm.subscribe(callbackName(onChange), func(event ChangeEvent) {
+		if event.Err != nil && len(event.Changes) == 0 {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		next := snapshot()
+		old := last
+		last = next
+		onChange(old, next)
+	})
+
+	started := cm.sup.Go("schema-watch", func(supCtx context.Context) {
+		select {
+		case <-ctx.Done():
+		case <-supCtx.Done():
+		}
+		unsubscribe()
+	})
+	if !started {
+		unsubscribe()
+		return ErrClosed
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package config
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSubscribe(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	cfg := New(configPath, zap.NewNop())
+	defer cfg.Close()
+	require.NoError(t, cfg.Load())
+
+	first := make(chan ChangeEvent, 10)
+	second := make(chan ChangeEvent, 10)
+	unsubscribeFirst := cfg.Subscribe(func(event ChangeEvent) { first <- event })
+	cfg.Subscribe(func(ChangeEvent) { panic("subscriber bug") })
+	cfg.Subscribe(func(event ChangeEvent) { second <- event })
+
+	receive := func(ch chan ChangeEvent) ChangeEvent {
+		select {
+		case event := <-ch:
+			return event
+		case <-time.After(2 * time.Second):
+			t.Fatal("timeout waiting for change event")
+			return ChangeEvent{}
+		}
+	}
+
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	require.NoError(t, writeFileAtomic(configPath, append(content, []byte("extra: true\n")...)))
+	assert.Equal(t, []string{"extra"}, receive(first).Keys())
+	assert.Equal(t, []string{"extra"}, receive(second).Keys())
+
+	unsubscribeFirst()
+	unsubscribeFirst()
+	require.NoError(t, writeFileAtomic(configPath, content))
+	assert.Equal(t, []string{"extra"}, receive(second).Keys())
+	select {
+	case <-first:
+		t.Fatal("unsubscribed callback was called")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestSubscribeWhileWatching(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	cfg := New(configPath, zap.NewNop())
+	defer cfg.Close()
+	require.NoError(t, cfg.Load())
+
+	watched := make(chan struct{}, 10)
+	events := make(chan ChangeEvent, 10)
+	subscribed := make(chan ChangeEvent, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, cfg.Watch(ctx, func() { watched <- struct{}{} }))
+	require.NoError(t, cfg.WatchEvents(context.Background(), func(event ChangeEvent) { events <- event }))
+	cfg.Subscribe(func(event ChangeEvent) { subscribed <- event })
+
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	require.NoError(t, writeFileAtomic(configPath, append(content, []byte("extra: true\n")...)))
+	for _, ch := range []chan ChangeEvent{events, subscribed} {
+		select {
+		case event := <-ch:
+			assert.Equal(t, []string{"extra"}, event.Keys())
+		case <-time.After(2 * time.Second):
+			t.Fatal("timeout waiting for change event")
+		}
+	}
+	select {
+	case <-watched:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for Watch callback")
+	}
+
+	// Leaving Watch keeps the watcher running for the others.
+	cancel()
+	require.NoError(t, writeFileAtomic(configPath, content))
+	select {
+	case event := <-subscribed:
+		assert.Equal(t, []string{"extra"}, event.Keys())
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for change event")
+	}
+}
+
+func TestScopedSubscriptions(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	cfg := New(configPath, zap.NewNop())
+	defer cfg.Close()
+	require.NoError(t, cfg.Load())
+
+	ports := make(chan [2]interface{}, 10)
+	database := make(chan []Change, 10)
+	cfg.OnKeyChange("Server.Port", func(old, new interface{}) { ports <- [2]interface{}{old, new} })
+	cfg.OnPrefixChange("database.", func(changes []Change) { database <- changes })
+
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	update := func(from, to string) {
+		content = []byte(strings.Replace(string(content), from, to, 1))
+		require.NoError(t, writeFileAtomic(configPath, content))
+	}
+
+	update("port: 8080", "port: 9090")
+	select {
+	case p := <-ports:
+		assert.Equal(t, [2]interface{}{8080, 9090}, p)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for key change")
+	}
+
+	update("maxConns: 10", "maxConns: 20")
+	select {
+	case changes := <-database:
+		assert.Equal(t, []Change{{Key: "database.maxconns", Old: 10, New: 20, Source: File}}, changes)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for prefix change")
+	}
+	assert.Empty(t, ports, "unrelated edits must not wake key subscribers")
+}
+
+func TestTypedKeyChange(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	cfg := New(configPath, zap.NewNop())
+	defer cfg.Close()
+	require.NoError(t, cfg.Load())
+
+	workers := make(chan int, 10)
+	timeouts := make(chan time.Duration, 10)
+	cfg.BindInt("database.maxConns", func(n int) { workers <- n })
+	OnKeyChangeAs(cfg, "server.timeout", func(d time.Duration) { timeouts <- d })
+
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	update := func(from, to string) {
+		content = []byte(strings.Replace(string(content), from, to, 1))
+		require.NoError(t, writeFileAtomic(configPath, content))
+	}
+
+	update("maxConns: 10", `maxConns: "20"`)
+	select {
+	case n := <-workers:
+		assert.Equal(t, 20, n)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for int change")
+	}
+
+	update(`timeout: "30s"`, `timeout: "1m"`)
+	select {
+	case d := <-timeouts:
+		assert.Equal(t, time.Minute, d)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for duration change")
+	}
+
+	update(`maxConns: "20"`, `maxConns: "lots"`)
+	require.Eventually(t, func() bool { return cfg.GetString("database.maxconns") == "lots" },
+		2*time.Second, 10*time.Millisecond)
+	select {
+	case n := <-workers:
+		t.Fatalf("unconvertible value delivered as %d", n)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestChanges(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	cfg := New(configPath, zap.NewNop())
+	defer cfg.Close()
+	require.NoError(t, cfg.Load())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := cfg.Changes(ctx)
+
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	require.NoError(t, writeFileAtomic(configPath, append(content, []byte("extra: true\n")...)))
+
+	select {
+	case event := <-changes:
+		assert.Equal(t, []string{"extra"}, event.Keys())
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for change event")
+	}
+
+	cancel()
+	require.Eventually(t, func() bool {
+		select {
+		case _, ok := <-changes:
+			return !ok
+		default:
+			return false
+		}
+	}, 2*time.Second, 10*time.Millisecond)
+
+	t.Run("Closed With Manager", func(t *testing.T) {
+		cfg := New(configPath, zap.NewNop())
+		changes := cfg.Changes(context.Background())
+		require.NoError(t, cfg.Close())
+		_, ok := <-changes
+		assert.False(t, ok)
+	})
+}
+
+func TestWatchSchema(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	cfg := New(configPath, zap.NewNop(), WithSchema(&TestConfig{}))
+	defer cfg.Close()
+	require.NoError(t, cfg.Load())
+
+	assert.Error(t, WatchSchema(context.Background(), cfg, func(old, new *AppConfig) {}))
+
+	type pair struct{ old, new *TestConfig }
+	changes := make(chan pair, 10)
+	require.NoError(t, WatchSchema(context.Background(), cfg, func(old, new *TestConfig) {
+		changes <- pair{old, new}
+	}))
+
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	require.NoError(t, writeFileAtomic(configPath, []byte(strings.Replace(string(content), "port: 8080", "port: 9090", 1))))
+
+	select {
+	case p := <-changes:
+		assert.Equal(t, 8080, p.old.Server.Port)
+		assert.Equal(t, 9090, p.new.Server.Port)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for schema change")
+	}
+}
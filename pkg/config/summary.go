@@ -0,0 +1,81 @@
+package config
+
+import (
+	"sort"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LogEffectiveConfig logs the effective configuration as a single entry at
+// level, for the startup logs on-call engineers read first: every key,
+// sorted, with its value, source, and origin, secrets masked as in
+// AllSettingsRedacted. Call it once after Load; nothing is computed when
+// the logger discards level.
+func (cm *ConfigManager) LogEffectiveConfig(level zapcore.Level) {
+	if !cm.logger.Enabled(level) {
+		return
+	}
+
+	cm.mu.RLock()
+	keys := cm.viper.AllKeys()
+	sort.Strings(keys)
+	secrets := cm.secretPatterns()
+	summary := make(keySummary, len(keys))
+	for i, key := range keys {
+		summary[i] = keyEntry{
+			key:    key,
+			value:  cm.viper.Get(key),
+			source: cm.origin(key),
+			origin: cm.originName(key),
+			secret: isSecret(key, secrets, cm.keyDelimiter),
+		}
+	}
+	fields := []zap.Field{
+		zap.String("source", cm.sourceName()),
+		zap.Uint64("generation", cm.generation),
+		zap.String("fingerprint", cm.fingerprint()),
+	}
+	if cm.profile != "" {
+		fields = append(fields, zap.String("profile", cm.profile))
+	}
+	cm.mu.RUnlock()
+
+	cm.logger.Log(level, "Effective configuration", append(fields, zap.Int("count", len(summary)), zap.Array("keys", summary))...)
+}
+
+// keySummary lists the keys logged by LogEffectiveConfig.
+type keySummary []keyEntry
+
+func (s keySummary) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, e := range s {
+		if err := enc.AppendObject(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// keyEntry is one key of a keySummary.
+type keyEntry struct {
+	key    string
+	value  interface{}
+	source Source
+	origin string
+	secret bool
+}
+
+// MarshalLogObject logs the key, with a secret value masked.
+func (e keyEntry) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("key", e.key)
+	if e.secret {
+		enc.AddString("value", RedactedValue)
+	} else if err := enc.AddReflected("value", e.value); err != nil {
+		return err
+	}
+	enc.AddString("source", e.source.String())
+	if e.origin != "" {
+		enc.AddString("origin", e.origin)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogEffectiveConfig(t *testing.T) {
+	configPath := writeInterpolateConfig(t, `
+server:
+  port: 8080
+database:
+  password: hunter2
+`)
+	t.Setenv("APP_SERVER_HOST", "example.com")
+	core, logs := observer.New(zap.InfoLevel)
+	cfg := New(configPath, zap.New(core),
+		WithEnvPrefix("APP"),
+		WithSecretKeys("*.password"),
+		WithDefaults(map[string]interface{}{"server.host": "localhost", "log.level": "info"}))
+	require.NoError(t, cfg.Load())
+	logs.TakeAll()
+
+	cfg.LogEffectiveConfig(zap.DebugLevel)
+	assert.Zero(t, logs.Len())
+
+	cfg.LogEffectiveConfig(zap.InfoLevel)
+	entries := logs.FilterMessage("Effective configuration").All()
+	require.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, configPath, fields["source"])
+	assert.Equal(t, uint64(1), fields["generation"])
+	assert.Equal(t, cfg.Fingerprint(), fields["fingerprint"])
+	assert.EqualValues(t, 4, fields["count"])
+	assert.NotContains(t, fields, "profile")
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"key": "database.password", "value": RedactedValue, "source": "file", "origin": configPath},
+		map[string]interface{}{"key": "log.level", "value": "info", "source": "defaults"},
+		map[string]interface{}{"key": "server.host", "value": "example.com", "source": "env", "origin": "APP_SERVER_HOST"},
+		map[string]interface{}{"key": "server.port", "value": 8080, "source": "file", "origin": configPath},
+	}, fields["keys"])
+}
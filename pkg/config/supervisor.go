@@ -0,0 +1,142 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// shutdownTimeout bounds how long Close waits for background goroutines.
+const shutdownTimeout = 5 * time.Second
+
+// supervisor owns every background goroutine started by a ConfigManager.
+// Goroutines receive a context that is cancelled on Close, which then waits
+// for all of them to return.
+type supervisor struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	stopped bool
+	running map[string]int
+}
+
+func newSupervisor() *supervisor {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &supervisor{ctx: ctx, cancel: cancel, running: make(map[string]int)}
+}
+
+// Go runs fn in a supervised goroutine under the given task name. It reports
+// false, without running fn, once the supervisor has been stopped.
+func (s *supervisor) Go(name string, fn func(ctx context.Context)) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return false
+	}
+	s.running[name]++
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() {
+			s.mu.Lock()
+			if s.running[name]--; s.running[name] == 0 {
+				delete(s.running, name)
+			}
+			s.mu.Unlock()
+		}()
+		fn(s.ctx)
+	}()
+	return true
+}
+
+// AfterFunc runs fn under the given task name once d has passed, like
+// time.AfterFunc, but in a supervised goroutine, so Close cancels a running
+// fn and waits for it. Nothing runs if the supervisor has been stopped by
+// then.
+func (s *supervisor) AfterFunc(name string, d time.Duration, fn func(ctx context.Context)) *time.Timer {
+	return time.AfterFunc(d, func() { s.Go(name, fn) })
+}
+
+// count returns the number of running goroutines and a breakdown by task.
+func (s *supervisor) count() (int, map[string]int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	total := 0
+	tasks := make(map[string]int, len(s.running))
+	for name, n := range s.running {
+		tasks[name] = n
+		total += n
+	}
+	return total, tasks
+}
+
+// stop cancels all goroutines and waits up to timeout for them to return.
+func (s *supervisor) stop(timeout time.Duration) error {
+	s.mu.Lock()
+	s.stopped = true
+	s.mu.Unlock()
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+		return errors.New("timeout waiting for background goroutines to stop")
+	}
+}
+
+// Stats is a snapshot of a ConfigManager's runtime state.
+type Stats struct {
+	// Goroutines is the number of background goroutines currently running.
+	Goroutines int
+	// Tasks breaks Goroutines down by task, e.g. "file-watch" or "remote-poll".
+	Tasks map[string]int
+	// Loads and LoadErrors count load attempts and failed loads.
+	Loads      uint64
+	LoadErrors uint64
+	// PartialLoads counts loads applied except for some sections, which
+	// are not counted in LoadErrors; RejectedSections counts the sections
+	// those loads rejected.
+	PartialLoads     uint64
+	RejectedSections uint64
+	// LastLoad is the time of the last successfully applied load.
+	LastLoad time.Time
+	// Generation is the version of the live configuration.
+	Generation uint64
+	// SlowCallbacks counts subscriber calls over the WithSubscriberBudget.
+	SlowCallbacks uint64
+}
+
+// Goroutines returns the number of background goroutines owned by the
+// manager. It drops to zero once Close has returned.
+func (cm *ConfigManager) Goroutines() int {
+	n, _ := cm.sup.count()
+	return n
+}
+
+// Stats returns a snapshot of the manager's goroutines and load counters.
+func (cm *ConfigManager) Stats() Stats {
+	n, tasks := cm.sup.count()
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return Stats{
+		Goroutines:       n,
+		Tasks:            tasks,
+		Loads:            cm.loads,
+		LoadErrors:       cm.loadErrors,
+		PartialLoads:     cm.partialLoads,
+		RejectedSections: cm.rejectedSections,
+		LastLoad:         cm.lastLoad,
+		Generation:       cm.generation,
+		SlowCallbacks:    cm.slowCallbacks.Load(),
+	}
+}
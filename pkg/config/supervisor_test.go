@@ -0,0 +1,117 @@
+package config
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"go.uber.org/zap"
+)
+
+func TestNoGoroutineLeaks(t *testing.T) {
+	t.Run("Local Watcher", func(t *testing.T) {
+		defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+		configPath, cleanup := setupTestConfig(t)
+		defer cleanup()
+
+		for i := 0; i < 20; i++ {
+			cfg := New(configPath, zap.NewNop(), WithWatcher())
+			require.NoError(t, cfg.Load())
+			require.NoError(t, cfg.Watch(context.Background(), func() {}))
+			assert.Equal(t, 1, cfg.Goroutines())
+			require.NoError(t, cfg.Close())
+			assert.Zero(t, cfg.Goroutines())
+		}
+	})
+
+	t.Run("Remote Watcher", func(t *testing.T) {
+		remote := useFakeRemote(t)
+		remote.set("app/config", []byte(`{"server": {"port": 8080}}`))
+		defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+		for i := 0; i < 20; i++ {
+			cfg := newRemoteTestConfig(WithWatcher(), WithPollInterval(time.Millisecond))
+			require.NoError(t, cfg.Load())
+			require.NoError(t, cfg.Watch(context.Background(), func() {}))
+			time.Sleep(5 * time.Millisecond)
+			require.NoError(t, cfg.Close())
+			assert.Zero(t, cfg.Goroutines())
+		}
+	})
+
+	t.Run("Subscribers And Channels", func(t *testing.T) {
+		defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+		configPath, cleanup := setupTestConfig(t)
+		defer cleanup()
+
+		cfg := New(configPath, zap.NewNop(), WithDebounce(time.Millisecond))
+		require.NoError(t, cfg.Load())
+		cfg.Subscribe(func(ChangeEvent) {})
+		cfg.Changes(context.Background())
+		assert.Equal(t, 2, cfg.Goroutines())
+		require.NoError(t, cfg.Close())
+		assert.Zero(t, cfg.Goroutines())
+	})
+
+	t.Run("Watch Context Cancelled", func(t *testing.T) {
+		defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+		configPath, cleanup := setupTestConfig(t)
+		defer cleanup()
+
+		cfg := New(configPath, zap.NewNop())
+		defer cfg.Close()
+		require.NoError(t, cfg.Load())
+		ctx, cancel := context.WithCancel(context.Background())
+		require.NoError(t, cfg.Watch(ctx, func() {}))
+		cancel()
+		require.Eventually(t, func() bool { return cfg.Goroutines() == 0 }, time.Second, time.Millisecond)
+	})
+
+	t.Run("HTTP Client", func(t *testing.T) {
+		defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+		configPath, cleanup := setupTestConfig(t)
+		defer cleanup()
+
+		cfg := New(configPath, zap.NewNop())
+		require.NoError(t, cfg.Load())
+		_, err := cfg.HTTPClient("clients.api")
+		require.NoError(t, err)
+		require.NoError(t, cfg.Close())
+	})
+
+	t.Run("Closed Manager Refuses Work", func(t *testing.T) {
+		configPath, cleanup := setupTestConfig(t)
+		defer cleanup()
+
+		cfg := New(configPath, zap.NewNop())
+		require.NoError(t, cfg.Close())
+		assert.ErrorIs(t, cfg.Watch(context.Background(), func() {}), ErrClosed)
+		assert.ErrorIs(t, cfg.Load(), ErrClosed)
+	})
+}
+
+func TestStats(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	cfg := New(configPath, zap.NewNop(), WithSchema(&TestConfig{}))
+	defer cfg.Close()
+
+	require.NoError(t, cfg.Load())
+	require.Error(t, cfg.Set("server.port", -1))
+	require.NoError(t, cfg.Watch(context.Background(), func() {}))
+
+	stats := cfg.Stats()
+	assert.Equal(t, uint64(2), stats.Loads)
+	assert.Equal(t, uint64(1), stats.LoadErrors)
+	assert.False(t, stats.LastLoad.IsZero())
+	assert.Equal(t, 1, stats.Goroutines)
+	assert.Equal(t, map[string]int{"file-watch": 1}, stats.Tasks)
+}
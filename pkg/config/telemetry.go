@@ -0,0 +1,196 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Tracer starts the spans of config loads. It mirrors the part of an
+// OpenTelemetry trace.Tracer the manager uses, so this package does not
+// depend on OTel; package configotel adapts a TracerProvider.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer.
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	// SetError records err and marks the span as failed.
+	SetError(err error)
+	End()
+}
+
+// Meter records the metrics of config loads. It mirrors an OpenTelemetry
+// Float64Histogram and Int64Counter; package configotel adapts a
+// MeterProvider.
+type Meter interface {
+	// RecordDuration records d, in seconds, in the histogram name.
+	RecordDuration(ctx context.Context, name string, d time.Duration, attrs ...Attribute)
+	// Add adds n to the counter name.
+	Add(ctx context.Context, name string, n int64, attrs ...Attribute)
+}
+
+// Attribute is an attribute of a span or metric. Value is a string, an
+// int64, or a bool.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Span names and metric names.
+const (
+	SpanLoad        = "config.load"
+	SpanRemoteFetch = "config.remote.fetch"
+	SpanUnmarshal   = "config.unmarshal"
+	SpanValidate    = "config.validate"
+
+	MetricLoads               = "config.loads"
+	MetricLoadDuration        = "config.load.duration"
+	MetricRemoteFetchDuration = "config.remote.fetch.duration"
+	MetricSubscriberDuration  = "config.subscriber.duration"
+	MetricReloadLatency       = "config.reload.latency"
+)
+
+// WithTracer traces every load, including watcher reloads, with a
+// config.load span carrying the config source and, once applied, its
+// content hash and generation. Remote fetches, schema and section
+// unmarshalling, and validation get child spans.
+func WithTracer(t Tracer) Option {
+	return func(cm *ConfigManager) {
+		cm.telemetry.tracer = t
+	}
+}
+
+// WithMeter records the count and duration of loads, by source and result,
+// the duration of remote fetches, the duration of each Subscribe callback,
+// and the reload latency: the time from applying a watcher reload to the
+// return of its last subscriber.
+func WithMeter(m Meter) Option {
+	return func(cm *ConfigManager) {
+		cm.telemetry.meter = m
+	}
+}
+
+// telemetry is the tracer and meter of a manager. A nil *telemetry records
+// nothing.
+type telemetry struct {
+	tracer Tracer
+	meter  Meter
+	// ctx is the context of the span of the running load. It is written
+	// under cm.mu for writing and read under cm.mu.
+	ctx context.Context
+}
+
+// snapshot returns a copy of t for use without cm.mu, such as by a remote
+// fetch, which may outlive its load.
+func (t *telemetry) snapshot() *telemetry {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
+
+// noopSpan is the span of operations that are not traced.
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute) {}
+func (noopSpan) SetError(error)             {}
+func (noopSpan) End()                       {}
+
+// startLoad starts the span of a load. The caller must hold cm.mu for
+// writing.
+func (t *telemetry) startLoad(attrs ...Attribute) Span {
+	if t == nil || t.tracer == nil {
+		return noopSpan{}
+	}
+	ctx, span := t.tracer.Start(context.Background(), SpanLoad)
+	span.SetAttributes(attrs...)
+	t.ctx = ctx
+	return span
+}
+
+// endLoad ends the span of a load started at start, which failed with err,
+// and records its metrics. The caller must hold cm.mu for writing.
+func (t *telemetry) endLoad(span Span, start time.Time, err error, attrs ...Attribute) {
+	if t == nil {
+		return
+	}
+	var partial *PartialApplyError
+	if errors.As(err, &partial) {
+		// The load was applied; the rejected sections are reported apart
+		// from failures.
+		names := make([]string, len(partial.Rejected))
+		for i, r := range partial.Rejected {
+			names[i] = r.Section
+		}
+		span.SetAttributes(Attribute{Key: "config.sections.rejected", Value: strings.Join(names, ",")})
+		span.End()
+	} else {
+		endSpan(span, err)
+	}
+	t.ctx = nil
+	if t.meter != nil {
+		attrs = append(attrs, resultAttribute(err))
+		t.meter.Add(context.Background(), MetricLoads, 1, attrs...)
+		t.meter.RecordDuration(context.Background(), MetricLoadDuration, time.Since(start), attrs...)
+	}
+}
+
+// startSpan starts a child span of the running load's span. The caller must
+// hold cm.mu, unless t is a snapshot.
+func (t *telemetry) startSpan(name string, attrs ...Attribute) Span {
+	if t == nil || t.tracer == nil || t.ctx == nil {
+		return noopSpan{}
+	}
+	_, span := t.tracer.Start(t.ctx, name)
+	span.SetAttributes(attrs...)
+	return span
+}
+
+// recordDuration records the duration since start of an operation that
+// failed with err in the histogram name.
+func (t *telemetry) recordDuration(name string, start time.Time, err error, attrs ...Attribute) {
+	if t == nil || t.meter == nil {
+		return
+	}
+	attrs = append(attrs, resultAttribute(err))
+	t.meter.RecordDuration(context.Background(), name, time.Since(start), attrs...)
+}
+
+// endSpan ends span, which failed with err.
+func endSpan(span Span, err error) {
+	if err != nil {
+		span.SetError(err)
+	}
+	span.End()
+}
+
+func resultAttribute(err error) Attribute {
+	var partial *PartialApplyError
+	switch {
+	case err == nil:
+		return Attribute{Key: "config.result", Value: "ok"}
+	case errors.As(err, &partial):
+		return Attribute{Key: "config.result", Value: "partial"}
+	}
+	return Attribute{Key: "config.result", Value: "error"}
+}
+
+// sourceAttributes describes the source of the configuration.
+func (cm *ConfigManager) sourceAttributes() []Attribute {
+	if rp := cm.remoteProvider; rp != nil {
+		return []Attribute{
+			{Key: "config.source", Value: "remote"},
+			{Key: "config.remote.type", Value: rp.Type},
+			{Key: "config.remote.endpoint", Value: rp.endpoint()},
+			{Key: "config.remote.path", Value: rp.Path},
+		}
+	}
+	return []Attribute{
+		{Key: "config.source", Value: "file"},
+		{Key: "config.path", Value: cm.path},
+	}
+}
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type spanKey struct{}
+
+// fakeSpan is a span recorded by fakeTracer.
+type fakeSpan struct {
+	name   string
+	parent *fakeSpan
+	attrs  map[string]interface{}
+	err    error
+	ended  bool
+}
+
+func (s *fakeSpan) SetAttributes(attrs ...Attribute) {
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *fakeSpan) SetError(err error) { s.err = err }
+func (s *fakeSpan) End()               { s.ended = true }
+
+type fakeTracer struct {
+	mu    sync.Mutex
+	spans []*fakeSpan
+}
+
+func (t *fakeTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	parent, _ := ctx.Value(spanKey{}).(*fakeSpan)
+	s := &fakeSpan{name: name, parent: parent, attrs: make(map[string]interface{})}
+	t.spans = append(t.spans, s)
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+func (t *fakeTracer) find(name string) *fakeSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, s := range t.spans {
+		if s.name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+type fakeMeter struct {
+	counts    map[string]int64
+	durations map[string][]map[string]interface{}
+}
+
+func newFakeMeter() *fakeMeter {
+	return &fakeMeter{counts: make(map[string]int64), durations: make(map[string][]map[string]interface{})}
+}
+
+func (m *fakeMeter) RecordDuration(_ context.Context, name string, _ time.Duration, attrs ...Attribute) {
+	recorded := make(map[string]interface{})
+	for _, a := range attrs {
+		recorded[a.Key] = a.Value
+	}
+	m.durations[name] = append(m.durations[name], recorded)
+}
+
+func (m *fakeMeter) Add(_ context.Context, name string, n int64, _ ...Attribute) {
+	m.counts[name] += n
+}
+
+func TestTelemetry(t *testing.T) {
+	t.Run("Traces Local Load", func(t *testing.T) {
+		configPath, cleanup := setupTestConfig(t)
+		defer cleanup()
+		tracer, meter := &fakeTracer{}, newFakeMeter()
+		var schema TestConfig
+		cfg := New(configPath, zap.NewNop(), WithSchema(&schema), WithTracer(tracer), WithMeter(meter))
+		require.NoError(t, cfg.Load())
+
+		load := tracer.find(SpanLoad)
+		require.NotNil(t, load)
+		assert.True(t, load.ended)
+		assert.Nil(t, load.parent)
+		assert.Equal(t, "file", load.attrs["config.source"])
+		assert.Equal(t, configPath, load.attrs["config.path"])
+		assert.Equal(t, cfg.SourceHash(), load.attrs["config.hash"])
+		assert.Equal(t, int64(1), load.attrs["config.generation"])
+
+		for _, name := range []string{SpanUnmarshal, SpanValidate} {
+			span := tracer.find(name)
+			require.NotNil(t, span, name)
+			assert.Same(t, load, span.parent)
+			assert.True(t, span.ended)
+			assert.Equal(t, "config.TestConfig", span.attrs["config.schema"])
+		}
+
+		assert.Equal(t, int64(1), meter.counts[MetricLoads])
+		require.Len(t, meter.durations[MetricLoadDuration], 1)
+		assert.Equal(t, map[string]interface{}{"config.source": "file", "config.result": "ok"},
+			meter.durations[MetricLoadDuration][0])
+	})
+
+	t.Run("Failed Validation", func(t *testing.T) {
+		configPath := writeInterpolateConfig(t, `
+server:
+  port: 0
+`)
+		tracer, meter := &fakeTracer{}, newFakeMeter()
+		var schema TestConfig
+		cfg := New(configPath, zap.NewNop(), WithSchema(&schema), WithTracer(tracer), WithMeter(meter))
+		err := cfg.Load()
+		require.Error(t, err)
+
+		assert.Equal(t, err, tracer.find(SpanLoad).err)
+		assert.Error(t, tracer.find(SpanValidate).err)
+		assert.Nil(t, tracer.find(SpanUnmarshal).err)
+		assert.NotContains(t, tracer.find(SpanLoad).attrs, "config.hash")
+		assert.Equal(t, "error", meter.durations[MetricLoadDuration][0]["config.result"])
+	})
+
+	t.Run("Traces Remote Fetch", func(t *testing.T) {
+		remote := useFakeRemote(t)
+		remote.set("app/config", []byte(`{"server": {"port": 8080}}`))
+		tracer, meter := &fakeTracer{}, newFakeMeter()
+		cfg := newRemoteTestConfig(WithTracer(tracer), WithMeter(meter))
+		require.NoError(t, cfg.Load())
+
+		load := tracer.find(SpanLoad)
+		assert.Equal(t, "remote", load.attrs["config.source"])
+		assert.Equal(t, "localhost:8500", load.attrs["config.remote.endpoint"])
+
+		fetch := tracer.find(SpanRemoteFetch)
+		require.NotNil(t, fetch)
+		assert.Same(t, load, fetch.parent)
+		assert.True(t, fetch.ended)
+		assert.Equal(t, "consul", fetch.attrs["config.remote.type"])
+		assert.Equal(t, "app/config", fetch.attrs["config.remote.path"])
+		assert.Equal(t, map[string]interface{}{"config.remote.type": "consul", "config.result": "ok"},
+			meter.durations[MetricRemoteFetchDuration][0])
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		configPath, cleanup := setupTestConfig(t)
+		defer cleanup()
+		cfg := New(configPath, zap.NewNop())
+		require.NoError(t, cfg.Load())
+	})
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"reflect"
+	"text/template"
+)
+
+// WithTemplating renders config files, included and override files, tenant
+// overlays, and remote documents as text/template templates before they are
+// decoded. Templates are executed without data and may call env, default,
+// b64dec, and coalesce, which behave like their sprig namesakes, and funcs,
+// which can add functions or replace them:
+//
+//	host: {{ env "DB_HOST" | default "localhost" }}
+//
+// A template that fails to parse or execute fails the load.
+func WithTemplating(funcs template.FuncMap) Option {
+	return func(cm *ConfigManager) {
+		cm.templateFuncs = template.FuncMap{
+			"env":      os.Getenv,
+			"default":  templateDefault,
+			"b64dec":   templateB64Dec,
+			"coalesce": templateCoalesce,
+		}
+		for name, fn := range funcs {
+			cm.templateFuncs[name] = fn
+		}
+	}
+}
+
+// renderTemplate executes data, the content of the document named name, as
+// a template with funcs. Without funcs, templating is off and data is
+// returned as is.
+func renderTemplate(name string, data []byte, funcs template.FuncMap) ([]byte, error) {
+	if funcs == nil {
+		return data, nil
+	}
+	tmpl, err := template.New(name).Funcs(funcs).Parse(string(data))
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, nil); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// templateDefault returns def if value is missing or empty, and value
+// otherwise, so that it reads well at the end of a pipeline.
+func templateDefault(def interface{}, value ...interface{}) interface{} {
+	if len(value) == 0 || templateEmpty(value[0]) {
+		return def
+	}
+	return value[0]
+}
+
+// templateCoalesce returns the first value that is not empty, or nil.
+func templateCoalesce(values ...interface{}) interface{} {
+	for _, v := range values {
+		if !templateEmpty(v) {
+			return v
+		}
+	}
+	return nil
+}
+
+// templateB64Dec decodes a standard base64 string.
+func templateB64Dec(s string) (string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return "", err
+	}
+	return string(decoded), nil
+}
+
+// templateEmpty reports whether v is nil, an empty string, slice, or map,
+// or the zero value of its type.
+func templateEmpty(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return rv.Len() == 0
+	}
+	return rv.IsZero()
+}
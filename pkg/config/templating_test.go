@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestTemplating(t *testing.T) {
+	content := `
+server:
+  host: {{ env "TMPL_HOST" | default "localhost" }}
+  port: {{ coalesce (env "TMPL_PORT") 8080 }}
+database:
+  password: {{ b64dec "czNjcmV0" }}
+  name: {{ upper "app" }}
+{{- if eq (env "TMPL_TRACING") "on" }}
+tracing:
+  enabled: true
+{{- end }}
+`
+	funcs := template.FuncMap{"upper": strings.ToUpper}
+
+	t.Run("Renders Document", func(t *testing.T) {
+		t.Setenv("TMPL_HOST", "example.com")
+		t.Setenv("TMPL_TRACING", "on")
+		cfg := New(writeInterpolateConfig(t, content), zap.NewNop(), WithTemplating(funcs))
+		require.NoError(t, cfg.Load())
+
+		assert.Equal(t, "example.com", cfg.GetString("server.host"))
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+		assert.Equal(t, "s3cret", cfg.GetString("database.password"))
+		assert.Equal(t, "APP", cfg.GetString("database.name"))
+		assert.True(t, cfg.GetBool("tracing.enabled"))
+	})
+
+	t.Run("Defaults", func(t *testing.T) {
+		unsetenv(t, "TMPL_HOST", "TMPL_PORT", "TMPL_TRACING")
+		cfg := New(writeInterpolateConfig(t, content), zap.NewNop(), WithTemplating(funcs))
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, "localhost", cfg.GetString("server.host"))
+		assert.False(t, cfg.IsSet("tracing"))
+	})
+
+	t.Run("Included File", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "db.yaml"),
+			[]byte(`database: {name: {{ upper "orders" }}}`), 0644))
+		path := filepath.Join(dir, "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("include: [db.yaml]\n"), 0644))
+		cfg := New(path, zap.NewNop(), WithTemplating(funcs))
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, "ORDERS", cfg.GetString("database.name"))
+	})
+
+	t.Run("Template Error", func(t *testing.T) {
+		cfg := New(writeInterpolateConfig(t, "host: {{ env }}\n"), zap.NewNop(), WithTemplating(nil))
+		assert.Error(t, cfg.Load())
+	})
+
+	t.Run("Off By Default", func(t *testing.T) {
+		cfg := New(writeInterpolateConfig(t, "host: '{{ env \"HOME\" }}'\n"), zap.NewNop())
+		require.NoError(t, cfg.Load())
+		assert.Equal(t, `{{ env "HOME" }}`, cfg.GetString("host"))
+	})
+}
@@ -0,0 +1,249 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// TenantConfig serves per-tenant configuration for multi-tenant services: a
+// base configuration plus one overlay file per tenant, e.g.
+// tenants/acme.yaml, merged on top of it. Each tenant gets a manager of its
+// own, created on first use and cached, that reloads when its overlay
+// changes, independently of the other tenants, and when the base reloads.
+type TenantConfig struct {
+	base    *ConfigManager
+	dir     string
+	ext     string
+	logger  logSink
+	opts    []Option
+	mu      sync.Mutex
+	tenants map[string]*ConfigManager
+	closed  bool
+	// stop removes the base subscription.
+	stop func()
+}
+
+// NewTenantConfig returns a TenantConfig layering the overlays in dir over
+// base. Overlays are named after the tenant with the base config file's
+// extension. opts apply to every tenant manager, e.g. WithSchema to
+// validate the merged configuration of each tenant; each tenant decodes
+// into its own copy of the schema, returned by its GetSchema. A nil logger
+// uses the logger of base.
+func NewTenantConfig(base *ConfigManager, dir string, logger *zap.Logger, opts ...Option) *TenantConfig {
+	sink := base.logger
+	if logger != nil {
+		sink = zapSink{logger}
+	}
+	tc := &TenantConfig{
+		base:    base,
+		dir:     dir,
+		ext:     filepath.Ext(base.path),
+		logger:  sink,
+		opts:    opts,
+		tenants: make(map[string]*ConfigManager),
+	}
+	tc.stop = base.Subscribe(func(event ChangeEvent) {
+		if event.Err == nil {
+			tc.reloadAll()
+		}
+	})
+	return tc
+}
+
+// ForTenant returns the configuration of tenant id: the base with the
+// tenant's overlay merged on top, or the base alone if the tenant has no
+// overlay file. The manager is loaded and starts watching its overlay on
+// first use; later calls return the cached manager.
+func (tc *TenantConfig) ForTenant(id string) (*ConfigManager, error) {
+	if id == "" || id != filepath.Base(id) || strings.HasPrefix(id, ".") {
+		return nil, fmt.Errorf("invalid tenant id %q", id)
+	}
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if tc.closed {
+		return nil, ErrClosed
+	}
+	if cm, ok := tc.tenants[id]; ok {
+		return cm, nil
+	}
+
+	path := filepath.Join(tc.dir, id+tc.ext)
+	cm := newManager(path, tc.logger.With(zap.String("tenant", id)), tc.opts...)
+	if cm.schema != nil {
+		// Tenants must not decode into the same struct.
+		cm.schema = scratchCopy(cm.schema)
+	}
+	cm.provider = &tenantProvider{
+		base:        tc.base,
+		path:        path,
+		logger:      cm.logger,
+		configType:  cm.configType,
+		jsonnet:     cm.jsonnet,
+		funcs:       cm.templateFuncs,
+		profile:     cm.profile,
+		interpolate: !cm.noInterpolation,
+		delimiter:   cm.keyDelimiter,
+		merge:       newMergePolicy(cm.mergeRules, cm.keyDelimiter),
+		targets:     cm.watchTargets,
+	}
+	if err := cm.Load(); err != nil {
+		cm.Close()
+		return nil, fmt.Errorf("tenant %s: %w", id, err)
+	}
+	cm.Subscribe(func(event ChangeEvent) {
+		if event.Err == nil {
+			cm.logger.Debug("Reloaded tenant configuration", zap.Strings("keys", event.Keys()))
+		}
+	})
+	tc.tenants[id] = cm
+	return cm, nil
+}
+
+// Tenants returns the ids of the tenants loaded so far, sorted.
+func (tc *TenantConfig) Tenants() []string {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	ids := make([]string, 0, len(tc.tenants))
+	for id := range tc.tenants {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// reloadAll reloads every tenant after a base reload and notifies their
+// subscribers of the changes.
+func (tc *TenantConfig) reloadAll() {
+	tc.mu.Lock()
+	tenants := make([]*ConfigManager, 0, len(tc.tenants))
+	for _, cm := range tc.tenants {
+		tenants = append(tenants, cm)
+	}
+	tc.mu.Unlock()
+
+	for _, cm := range tenants {
+		event := cm.loadEvent()
+		if event.Err != nil {
+			cm.logger.Error("Failed to reload tenant configuration", zap.Error(event.Err))
+			cm.reportReloadError(event.Err)
+		}
+		if event.Err != nil || len(event.Changes) > 0 {
+			cm.publish(event)
+		}
+	}
+}
+
+// Close stops following the base and closes every tenant manager. The base
+// manager is left open.
+func (tc *TenantConfig) Close() error {
+	tc.mu.Lock()
+	if tc.closed {
+		tc.mu.Unlock()
+		return nil
+	}
+	tc.closed = true
+	tenants := tc.tenants
+	tc.tenants = nil
+	tc.mu.Unlock()
+
+	tc.stop()
+	var errs []error
+	for _, cm := range tenants {
+		errs = append(errs, cm.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// tenantProvider loads the base manager's effective configuration with a
+// tenant overlay file merged on top.
+type tenantProvider struct {
+	base        *ConfigManager
+	path        string
+	logger      logSink
+	configType  string
+	jsonnet     *JsonnetOptions
+	funcs       template.FuncMap
+	profile     string
+	interpolate bool
+	delimiter   string
+	merge       *mergePolicy
+	targets     *watchRegistry
+	origins     map[string]Source
+	files       map[string]string
+	templates   map[string]interpolation
+}
+
+func (p *tenantProvider) Load(v *viper.Viper) error {
+	p.base.mu.RLock()
+	settings := deepCopyMap(p.base.viper.AllSettings())
+	origins := make(map[string]Source, len(p.base.origins))
+	files := make(map[string]string)
+	for _, key := range p.base.viper.AllKeys() {
+		origins[key] = p.base.originOf(key, p.base.origins)
+		if origins[key] == File {
+			files[key] = p.base.originName(key)
+		}
+	}
+	// Keep the base's secrets and placeholders out of the tenant's Save.
+	templates := p.base.resolvedTemplates()
+	p.base.mu.RUnlock()
+
+	data, err := os.ReadFile(p.path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error reading tenant overlay: %w", err)
+	}
+	if data != nil {
+		trace := &fileTrace{}
+		overlay, err := decodeConfigFile(p.logger, p.path, data, p.configType, p.delimiter, p.jsonnet, p.funcs, p.profile, p.targets, trace)
+		if err != nil {
+			return fmt.Errorf("error reading tenant overlay: %w", err)
+		}
+		if p.interpolate {
+			if err := interpolateEnv(overlay, p.delimiter, templates); err != nil {
+				return fmt.Errorf("error reading tenant overlay: %w", err)
+			}
+		}
+		overlay = lowerKeys(overlay)
+		p.merge.merge(settings, overlay)
+		for _, key := range leafKeys(overlay, "", p.delimiter) {
+			origins[key] = File
+		}
+		for key, file := range trace.locate(origins, p.delimiter) {
+			files[key] = file
+		}
+	}
+	p.origins = origins
+	p.files = files
+	p.templates = templates
+	return v.MergeConfigMap(settings)
+}
+
+// lastOrigins returns the source of each key in the last loaded
+// configuration: File for keys set by the overlay, the base's source for
+// the others.
+func (p *tenantProvider) lastOrigins() map[string]Source {
+	return p.origins
+}
+
+// lastTemplates returns the resolved values of the base and the values env
+// interpolation changed in the overlay.
+func (p *tenantProvider) lastTemplates() map[string]interpolation {
+	return p.templates
+}
+
+// lastFiles returns the file each key taken from File was read from: the
+// overlay or a file it includes for overlay keys, the base's file for the
+// others.
+func (p *tenantProvider) lastFiles() map[string]string {
+	return p.files
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestTenantConfig(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+	dir := filepath.Join(t.TempDir(), "tenants")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	acme := filepath.Join(dir, "acme.yaml")
+	require.NoError(t, os.WriteFile(acme, []byte("database:\n  name: acme\n  maxConns: 50\n"), 0644))
+
+	base := New(configPath, zap.NewNop())
+	defer base.Close()
+	require.NoError(t, base.Load())
+	// The tenants subscribe to the base, sharing the watcher started here.
+	require.NoError(t, base.Watch(context.Background(), func() {}))
+
+	type Schema struct {
+		Database struct {
+			Name string `mapstructure:"name" validate:"required"`
+		} `mapstructure:"database"`
+	}
+	shared := &Schema{}
+	tc := NewTenantConfig(base, dir, zap.NewNop(), WithSchema(shared))
+	defer tc.Close()
+
+	acmeCfg, err := tc.ForTenant("acme")
+	require.NoError(t, err)
+	globex, err := tc.ForTenant("globex")
+	require.NoError(t, err)
+
+	t.Run("Merged And Cached", func(t *testing.T) {
+		assert.Equal(t, "acme", acmeCfg.GetString("database.name"))
+		assert.Equal(t, 50, acmeCfg.GetInt("database.maxconns"))
+		assert.Equal(t, 8080, acmeCfg.GetInt("server.port"))
+		assert.Equal(t, "testdb", globex.GetString("database.name"))
+		assert.Equal(t, "acme", acmeCfg.GetSchema().(*Schema).Database.Name)
+		assert.Equal(t, "testdb", globex.GetSchema().(*Schema).Database.Name)
+		assert.Empty(t, shared.Database.Name)
+		assert.Contains(t, acmeCfg.Describe(), KeyInfo{Key: "database.name", Type: "string", Source: File, Origin: acme})
+		assert.Equal(t, configPath, acmeCfg.OriginName("server.port"))
+
+		again, err := tc.ForTenant("acme")
+		require.NoError(t, err)
+		assert.Same(t, acmeCfg, again)
+		assert.Equal(t, []string{"acme", "globex"}, tc.Tenants())
+	})
+
+	t.Run("Invalid Id", func(t *testing.T) {
+		for _, id := range []string{"", "../etc", "a/b", ".hidden"} {
+			_, err := tc.ForTenant(id)
+			assert.Error(t, err, id)
+		}
+	})
+
+	t.Run("Overlay Reloads Independently", func(t *testing.T) {
+		require.NoError(t, writeFileAtomic(acme, []byte("database:\n  name: acme-eu\n")))
+		require.Eventually(t, func() bool { return acmeCfg.GetString("database.name") == "acme-eu" },
+			2*time.Second, 10*time.Millisecond)
+		assert.Equal(t, "testdb", globex.GetString("database.name"))
+		assert.Equal(t, 10, acmeCfg.GetInt("database.maxconns"))
+	})
+
+	t.Run("Base Reload", func(t *testing.T) {
+		ports := make(chan interface{}, 10)
+		acmeCfg.OnKeyChange("server.port", func(_, new interface{}) { ports <- new })
+
+		content, err := os.ReadFile(configPath)
+		require.NoError(t, err)
+		require.NoError(t, writeFileAtomic(configPath, []byte(strings.Replace(string(content), "port: 8080", "port: 9090", 1))))
+		require.Eventually(t, func() bool { return globex.GetInt("server.port") == 9090 },
+			2*time.Second, 10*time.Millisecond)
+		select {
+		case p := <-ports:
+			assert.Equal(t, 9090, p)
+		case <-time.After(2 * time.Second):
+			t.Fatal("timeout waiting for tenant change")
+		}
+		assert.Equal(t, "acme-eu", acmeCfg.GetString("database.name"))
+	})
+
+	t.Run("Base Runtime Override", func(t *testing.T) {
+		require.NoError(t, base.Set("server.port", 9191))
+		assert.Equal(t, 9191, acmeCfg.GetInt("server.port"))
+		assert.Equal(t, 9191, globex.GetInt("server.port"))
+		require.NoError(t, base.Unset("server.port"))
+		assert.Equal(t, 9090, globex.GetInt("server.port"))
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		require.NoError(t, tc.Close())
+		_, err := tc.ForTenant("acme")
+		assert.ErrorIs(t, err, ErrClosed)
+	})
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/cast"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// WithTimeLayouts sets the layouts GetTime and schema decoding try, in
+// order, for string values, e.g. WithTimeLayouts("2006-01-02",
+// time.RFC3339). Values matching none of them are rejected. Without it,
+// strings are parsed with viper's built-in list of formats.
+func WithTimeLayouts(layouts ...string) Option {
+	return func(cm *ConfigManager) {
+		cm.timeLayouts = append([]string(nil), layouts...)
+	}
+}
+
+// WithTimeLocation sets the location of times whose string value has no
+// zone offset. The default is UTC.
+func WithTimeLocation(loc *time.Location) Option {
+	return func(cm *ConfigManager) {
+		cm.timeLocation = loc
+	}
+}
+
+// parseTime converts a config value to a time with the configured layouts
+// and location.
+func (cm *ConfigManager) parseTime(value interface{}) (time.Time, error) {
+	loc := cm.timeLocation
+	if loc == nil {
+		loc = time.UTC
+	}
+	s, ok := value.(string)
+	if !ok || len(cm.timeLayouts) == 0 {
+		return cast.ToTimeInDefaultLocationE(value, loc)
+	}
+	s = strings.TrimSpace(s)
+	for _, layout := range cm.timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("time %q matches none of the layouts %q", s, cm.timeLayouts)
+}
+
+// stringToTimeHook decodes strings into time.Time fields with parseTime.
+func (cm *ConfigManager) stringToTimeHook() mapstructure.DecodeHookFuncType {
+	return func(from, to reflect.Type, data interface{}) (interface{}, error) {
+		if to != timeType || from.Kind() != reflect.String {
+			return data, nil
+		}
+		return cm.parseTime(data)
+	}
+}
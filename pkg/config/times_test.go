@@ -0,0 +1,75 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestTimeLayouts(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := `
+retention:
+  cutoff: "2024-03-01"
+  at: "2024-03-01T10:30:00+02:00"
+  stamp: "01/03/2024"
+`
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	t.Run("Default Formats", func(t *testing.T) {
+		cfg := New(configPath, zap.NewNop())
+		require.NoError(t, cfg.Load())
+		assert.True(t, cfg.GetTime("retention.cutoff").Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)))
+		assert.True(t, cfg.GetTime("retention.stamp").IsZero())
+	})
+
+	t.Run("Layouts And Location", func(t *testing.T) {
+		cfg := New(configPath, zap.NewNop(),
+			WithTimeLayouts("2006-01-02", time.RFC3339, "02/01/2006"),
+			WithTimeLocation(berlin))
+		require.NoError(t, cfg.Load())
+
+		cutoff := cfg.GetTime("retention.cutoff")
+		assert.True(t, cutoff.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, berlin)))
+		assert.Equal(t, berlin, cutoff.Location())
+		assert.True(t, cfg.GetTime("retention.at").Equal(time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC)))
+		assert.True(t, cfg.GetTime("retention.stamp").Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, berlin)))
+		assert.True(t, cfg.GetTime("retention.missing").IsZero())
+
+		stamp, err := Get[time.Time](cfg, "retention.stamp")
+		require.NoError(t, err)
+		assert.True(t, stamp.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, berlin)))
+		assert.True(t, cfg.Sub("retention").GetTime("cutoff").Equal(cutoff))
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		type Retention struct {
+			Cutoff time.Time `mapstructure:"cutoff"`
+			Stamp  time.Time `mapstructure:"stamp"`
+		}
+		type Schema struct {
+			Retention Retention `mapstructure:"retention"`
+		}
+
+		var schema Schema
+		cfg := New(configPath, zap.NewNop(),
+			WithSchema(&schema),
+			WithTimeLayouts("2006-01-02", "02/01/2006", time.RFC3339),
+			WithTimeLocation(berlin))
+		require.NoError(t, cfg.Load())
+		assert.True(t, schema.Retention.Stamp.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, berlin)))
+
+		var bad Schema
+		cfg = New(configPath, zap.NewNop(), WithSchema(&bad), WithTimeLayouts(time.RFC3339))
+		err := cfg.Load()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "matches none of the layouts")
+	})
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// errExpirySuperseded skips the expiry of an override changed since it was
+// set with a TTL.
+var errExpirySuperseded = errors.New("override changed since it was set")
+
+// SetWithTTL overrides key with value like Set, and reverts the override
+// when ttl lapses, e.g. to raise a rate limit during an incident without
+// leaving it raised. The expiry reloads the configuration and sends the
+// change back to the sources' value to Subscribe and Changes consumers as
+// a ChangeEvent. Changing or unsetting key before then cancels the expiry.
+// If the configuration without the override is rejected, the override
+// stays and the rejection is logged.
+func (cm *ConfigManager) SetWithTTL(key string, value interface{}, ttl time.Duration) error {
+	if ttl <= 0 {
+		return fmt.Errorf("override TTL must be positive, got %s", ttl)
+	}
+	key = strings.ToLower(key)
+	var id uint64
+	err := cm.updateRuntime(key, func(r *runtimeLayers) error {
+		cm.ttlSeq++
+		id = cm.ttlSeq
+		r.overrides[key] = value
+		delete(r.masked, key)
+		r.expiries[key] = id
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	if cm.closed {
+		return nil
+	}
+	if cm.ttlTimers == nil {
+		cm.ttlTimers = make(map[uint64]*time.Timer)
+		cm.closeHooks = append(cm.closeHooks, cm.stopTTLTimers)
+	}
+	cm.ttlTimers[id] = cm.sup.AfterFunc("ttl-expiry", ttl, func(context.Context) { cm.expireOverride(key, id) })
+	return nil
+}
+
+// expireOverride reverts the override of key set with the TTL timer id,
+// unless it has changed since. updateRuntime logs the outcome and
+// publishes the change to subscribers.
+func (cm *ConfigManager) expireOverride(key string, id uint64) {
+	_ = cm.updateRuntime("expire "+key, func(r *runtimeLayers) error {
+		delete(cm.ttlTimers, id)
+		if r.expiries[key] != id {
+			return errExpirySuperseded
+		}
+		delete(r.overrides, key)
+		delete(r.expiries, key)
+		return nil
+	})
+}
+
+// stopTTLTimers stops the pending expiries. The caller must hold cm.mu.
+func (cm *ConfigManager) stopTTLTimers() {
+	for id, t := range cm.ttlTimers {
+		t.Stop()
+		delete(cm.ttlTimers, id)
+	}
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSetWithTTL(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	t.Run("Expires", func(t *testing.T) {
+		cfg := New(configPath, zap.NewNop())
+		defer cfg.Close()
+		require.NoError(t, cfg.Load())
+		events := make(chan ChangeEvent, 2)
+		cfg.Subscribe(func(event ChangeEvent) { events <- event })
+
+		require.NoError(t, cfg.SetWithTTL("server.port", 9090, 20*time.Millisecond))
+		assert.Equal(t, 9090, cfg.GetInt("server.port"))
+		// Subscribers see the override start as well as end.
+		set := <-events
+		assert.Equal(t, []Change{{Key: "server.port", Old: 8080, New: 9090, Source: Override}}, set.Changes)
+
+		select {
+		case event := <-events:
+			require.Len(t, event.Changes, 1)
+			assert.Equal(t, Change{Key: "server.port", Old: 9090, New: 8080, Source: File}, event.Changes[0])
+		case <-time.After(2 * time.Second):
+			t.Fatal("override did not expire")
+		}
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+	})
+
+	t.Run("Superseded", func(t *testing.T) {
+		cfg := New(configPath, zap.NewNop())
+		defer cfg.Close()
+		require.NoError(t, cfg.Load())
+
+		require.NoError(t, cfg.SetWithTTL("server.port", 9090, 20*time.Millisecond))
+		require.NoError(t, cfg.Set("server.port", 7070))
+		time.Sleep(60 * time.Millisecond)
+		assert.Equal(t, 7070, cfg.GetInt("server.port"))
+	})
+
+	t.Run("Stopped By Close", func(t *testing.T) {
+		cfg := New(configPath, zap.NewNop())
+		require.NoError(t, cfg.Load())
+		require.NoError(t, cfg.SetWithTTL("server.port", 9090, time.Hour))
+		require.NoError(t, cfg.Close())
+		assert.Empty(t, cfg.ttlTimers)
+	})
+
+	t.Run("Close During Expiry", func(t *testing.T) {
+		cfg := New(configPath, zap.NewNop())
+		require.NoError(t, cfg.Load())
+		expiring, release := make(chan struct{}), make(chan struct{})
+		cfg.Subscribe(func(event ChangeEvent) {
+			if event.Changes[0].New != 8080 {
+				return
+			}
+			close(expiring)
+			<-release
+		})
+		require.NoError(t, cfg.SetWithTTL("server.port", 9090, 10*time.Millisecond))
+		select {
+		case <-expiring:
+		case <-time.After(2 * time.Second):
+			t.Fatal("override did not expire")
+		}
+		assert.Equal(t, 1, cfg.Stats().Tasks["ttl-expiry"])
+
+		closed := make(chan error, 1)
+		go func() { closed <- cfg.Close() }()
+		select {
+		case <-closed:
+			t.Fatal("Close returned before the expiry")
+		case <-time.After(50 * time.Millisecond):
+		}
+		close(release)
+		require.NoError(t, <-closed)
+		assert.Zero(t, cfg.Goroutines())
+	})
+
+	t.Run("Invalid TTL", func(t *testing.T) {
+		cfg := New(configPath, zap.NewNop())
+		require.NoError(t, cfg.Load())
+		assert.Error(t, cfg.SetWithTTL("server.port", 9090, 0))
+	})
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// ErrKeyNotSet is matched by errors reporting a key without a value.
+var ErrKeyNotSet = errors.New("config key not set")
+
+// Get returns the value for key converted to T, decoding it the way schemas
+// are decoded: with weak typing, mapstructure tags for structs, and the
+// hooks for durations, comma-separated slices, times, Size, Duration, and
+// other encoding.TextUnmarshaler types. It returns an error matching
+// ErrKeyNotSet if key has no value, and the scoped view's *AccessError for
+// keys outside a ScopedConfig.
+func Get[T any](c Config, key string) (T, error) {
+	var out T
+	var raw interface{}
+	if e, ok := c.(interface {
+		GetE(key string) (interface{}, error)
+	}); ok {
+		v, err := e.GetE(key)
+		if err != nil {
+			return out, err
+		}
+		raw = v
+	} else {
+		raw = c.Get(key)
+	}
+	if raw == nil {
+		return out, fmt.Errorf("%w: '%s'", ErrKeyNotSet, key)
+	}
+
+	return convert[T](c, key, raw)
+}
+
+// convert decodes raw, the value of key in c, into a T with c's decode
+// hooks.
+func convert[T any](c Config, key string, raw interface{}) (T, error) {
+	var out T
+	var hooks mapstructure.DecodeHookFunc = (&ConfigManager{}).decodeHooks()
+	if h, ok := c.(interface {
+		decodeHooks() mapstructure.DecodeHookFunc
+	}); ok {
+		hooks = h.decodeHooks()
+	}
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       hooks,
+		WeaklyTypedInput: true,
+		Result:           &out,
+	})
+	if err != nil {
+		return out, err
+	}
+	if err := dec.Decode(raw); err != nil {
+		return out, fmt.Errorf("decoding config key '%s' as %T: %w", key, out, err)
+	}
+	return out, nil
+}
+
+// MustGet is like Get but panics if the key is not set or cannot be
+// converted, for values the application cannot start without.
+func MustGet[T any](c Config, key string) T {
+	v, err := Get[T](c, key)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestTypedGet(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := `
+server:
+  port: "8080"
+  timeout: 30s
+  max_body: 4MiB
+peers: [a, b]
+limits:
+  reads: 10
+  writes: 5
+`
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+	cfg := New(configPath, zap.NewNop())
+	require.NoError(t, cfg.Load())
+
+	port, err := Get[int](cfg, "server.port")
+	require.NoError(t, err)
+	assert.Equal(t, 8080, port)
+
+	timeout, err := Get[time.Duration](cfg, "server.timeout")
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, timeout)
+
+	assert.Equal(t, 4*MiB, MustGet[Size](cfg, "server.max_body"))
+	assert.Equal(t, []string{"a", "b"}, MustGet[[]string](cfg, "peers"))
+	assert.Equal(t, map[string]int{"reads": 10, "writes": 5}, MustGet[map[string]int](cfg, "limits"))
+
+	type server struct {
+		Port    int           `mapstructure:"port"`
+		Timeout time.Duration `mapstructure:"timeout"`
+		MaxBody Size          `mapstructure:"max_body"`
+	}
+	s, err := Get[server](cfg, "server")
+	require.NoError(t, err)
+	assert.Equal(t, server{Port: 8080, Timeout: 30 * time.Second, MaxBody: 4 * MiB}, s)
+
+	t.Run("Errors", func(t *testing.T) {
+		_, err := Get[int](cfg, "missing")
+		assert.ErrorIs(t, err, ErrKeyNotSet)
+
+		_, err = Get[int](cfg, "peers")
+		assert.ErrorContains(t, err, "decoding config key 'peers' as int")
+
+		assert.Panics(t, func() { MustGet[string](cfg, "missing") })
+
+		_, err = Get[int](cfg.ScopedView("limits"), "server.port")
+		var accessErr *AccessError
+		assert.True(t, errors.As(err, &accessErr))
+	})
+}
@@ -0,0 +1,221 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/cast"
+	"github.com/spf13/viper"
+)
+
+// Size is a byte count that decodes from human-readable strings such as
+// "512", "25MiB", or "1.5GB" as well as plain integers. Decimal units (KB,
+// MB, ...) are powers of 1000 and binary units (KiB or Ki, MiB or Mi, ...)
+// powers of 1024. Validate tags compare the byte count, e.g. max=104857600.
+type Size int64
+
+// Common sizes.
+const (
+	Byte Size = 1
+	KiB       = 1024 * Byte
+	MiB       = 1024 * KiB
+	GiB       = 1024 * MiB
+	TiB       = 1024 * GiB
+)
+
+var sizeUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"k":   1e3,
+	"kb":  1e3,
+	"m":   1e6,
+	"mb":  1e6,
+	"g":   1e9,
+	"gb":  1e9,
+	"t":   1e12,
+	"tb":  1e12,
+	"p":   1e15,
+	"pb":  1e15,
+	"ki":  1 << 10,
+	"kib": 1 << 10,
+	"mi":  1 << 20,
+	"mib": 1 << 20,
+	"gi":  1 << 30,
+	"gib": 1 << 30,
+	"ti":  1 << 40,
+	"tib": 1 << 40,
+	"pi":  1 << 50,
+	"pib": 1 << 50,
+}
+
+var sizePattern = regexp.MustCompile(`^([0-9]*\.?[0-9]+)\s*([a-zA-Z]*)$`)
+
+// ParseSize parses a human-readable byte count.
+func ParseSize(s string) (Size, error) {
+	m := sizePattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	unit, ok := sizeUnits[strings.ToLower(m[2])]
+	if !ok {
+		return 0, fmt.Errorf("invalid size %q: unknown unit %q", s, m[2])
+	}
+	n, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q: %w", s, err)
+	}
+	bytes := n * unit
+	if bytes > math.MaxInt64 {
+		return 0, fmt.Errorf("invalid size %q: out of range", s)
+	}
+	return Size(bytes), nil
+}
+
+// String formats s in the largest binary unit that divides it exactly.
+func (s Size) String() string {
+	for _, u := range []struct {
+		name string
+		size Size
+	}{{"TiB", TiB}, {"GiB", GiB}, {"MiB", MiB}, {"KiB", KiB}} {
+		if s != 0 && s%u.size == 0 {
+			return strconv.FormatInt(int64(s/u.size), 10) + u.name
+		}
+	}
+	return strconv.FormatInt(int64(s), 10) + "B"
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Size) UnmarshalText(text []byte) error {
+	parsed, err := ParseSize(string(text))
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Size) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Duration is a time.Duration that also accepts days ("d") and weeks ("w")
+// in addition to the units of time.ParseDuration, e.g. "1h30m" or "7d".
+// Validate tags compare it as a time.Duration, e.g. min=1s.
+type Duration time.Duration
+
+var durationPart = regexp.MustCompile(`([0-9]*\.?[0-9]+)([a-zµμ]+)`)
+
+// ParseDuration parses a duration such as "90s", "1h30m", or "2d12h".
+func ParseDuration(s string) (Duration, error) {
+	s = strings.TrimSpace(s)
+	if !strings.ContainsAny(s, "dw") {
+		d, err := time.ParseDuration(s)
+		return Duration(d), err
+	}
+
+	neg := strings.HasPrefix(s, "-")
+	rest := strings.TrimLeft(s, "+-")
+	var total time.Duration
+	for rest != "" {
+		loc := durationPart.FindStringSubmatchIndex(rest)
+		if loc == nil || loc[0] != 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		num, unit := rest[loc[2]:loc[3]], rest[loc[4]:loc[5]]
+		var part time.Duration
+		switch unit {
+		case "d", "w":
+			n, err := strconv.ParseFloat(num, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			day := 24 * time.Hour
+			if unit == "w" {
+				day *= 7
+			}
+			part = time.Duration(n * float64(day))
+		default:
+			d, err := time.ParseDuration(num + unit)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			part = d
+		}
+		total += part
+		rest = rest[loc[1]:]
+	}
+	if neg {
+		total = -total
+	}
+	return Duration(total), nil
+}
+
+// String formats d like time.Duration does.
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// decodeHooks returns the hooks used for every schema, section, and Get
+// decode. They keep viper's default hooks, parse times with the WithTimeLayouts
+// layouts, and decode strings into types implementing
+// encoding.TextUnmarshaler, such as Size and Duration.
+func (cm *ConfigManager) decodeHooks() mapstructure.DecodeHookFunc {
+	return mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+		cm.stringToTimeHook(),
+		mapstructure.TextUnmarshallerHookFunc(),
+	)
+}
+
+// decodeHook returns decodeHooks as a viper decoder option.
+func (cm *ConfigManager) decodeHook() viper.DecoderConfigOption {
+	return viper.DecodeHook(cm.decodeHooks())
+}
+
+// registerUnitTypes makes validate tags compare Duration fields as
+// time.Duration values.
+func registerUnitTypes(v *validator.Validate) {
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		return time.Duration(field.Int())
+	}, Duration(0))
+}
+
+// GetSize returns the value for key as a byte count, parsing strings such
+// as "25MiB". It returns 0 if the value is not a valid size.
+func (cm *ConfigManager) GetSize(key string) Size {
+	return cachedValue(cm, sizeValue, key, func(v *viper.Viper, key string) Size {
+		switch v := v.Get(key).(type) {
+		case nil:
+			return 0
+		case string:
+			s, _ := ParseSize(v)
+			return s
+		default:
+			return Size(cast.ToInt64(v))
+		}
+	})
+}
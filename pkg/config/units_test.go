@@ -0,0 +1,92 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestParseSize(t *testing.T) {
+	for in, want := range map[string]Size{
+		"512":    512,
+		"512B":   512,
+		"25MiB":  25 * MiB,
+		"25Mi":   25 * MiB,
+		"1.5GB":  1500000000,
+		"2 kb":   2000,
+		"1TiB":   TiB,
+		"0.5KiB": 512,
+	} {
+		got, err := ParseSize(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	for _, in := range []string{"", "MiB", "12XB", "-3MB", "1e3"} {
+		_, err := ParseSize(in)
+		assert.Error(t, err, in)
+	}
+	assert.Equal(t, "25MiB", (25 * MiB).String())
+	assert.Equal(t, "1000B", Size(1000).String())
+}
+
+func TestParseDuration(t *testing.T) {
+	for in, want := range map[string]time.Duration{
+		"90s":     90 * time.Second,
+		"1h30m":   90 * time.Minute,
+		"7d":      7 * 24 * time.Hour,
+		"2d12h":   60 * time.Hour,
+		"1w":      7 * 24 * time.Hour,
+		"-1d":     -24 * time.Hour,
+		"1.5d":    36 * time.Hour,
+		"1d500ms": 24*time.Hour + 500*time.Millisecond,
+	} {
+		got, err := ParseDuration(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, time.Duration(got), in)
+	}
+	for _, in := range []string{"", "d", "1x", "1d foo"} {
+		_, err := ParseDuration(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestUnitTypes(t *testing.T) {
+	type uploadConfig struct {
+		MaxUpload Size     `mapstructure:"max_upload" validate:"max=104857600"`
+		BodyLimit Size     `mapstructure:"body_limit" default:"1MiB"`
+		TTL       Duration `mapstructure:"ttl" validate:"min=1m"`
+		Retention Duration `mapstructure:"retention"`
+	}
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(content string) {
+		require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+	}
+
+	write("max_upload: 25MiB\nttl: 1h30m\nretention: 30d\n")
+	schema := &uploadConfig{}
+	cfg := New(configPath, zap.NewNop(), WithSchema(schema))
+	require.NoError(t, cfg.Load())
+
+	assert.Equal(t, 25*MiB, schema.MaxUpload)
+	assert.Equal(t, MiB, schema.BodyLimit)
+	assert.Equal(t, Duration(90*time.Minute), schema.TTL)
+	assert.Equal(t, Duration(30*24*time.Hour), schema.Retention)
+	assert.Equal(t, 25*MiB, cfg.GetSize("max_upload"))
+	assert.Equal(t, MiB, cfg.GetSize("body_limit"))
+	assert.Zero(t, cfg.GetSize("missing"))
+
+	write("max_upload: 1GiB\nttl: 30s\n")
+	err := cfg.Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "'max_upload': max")
+	assert.Contains(t, err.Error(), "'ttl': min")
+
+	write("max_upload: lots\nttl: 1h\n")
+	assert.Error(t, cfg.Load())
+	assert.Equal(t, 25*MiB, schema.MaxUpload)
+}
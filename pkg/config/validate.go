@@ -0,0 +1,147 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ValidationError reports a config key that failed a validation rule.
+type ValidationError struct {
+	// Key is the config key of the field, e.g. "server.port".
+	Key string
+	// Value is the offending value, or RedactedValue for secrets.
+	Value interface{}
+	// Rule is the failed validate tag, e.g. "min", and Param its
+	// parameter, e.g. "1024". Both are empty for CUE constraints.
+	Rule  string
+	Param string
+	// Message describes the failure, translated by WithValidationLocale.
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("validation failed for field '%s': %s", e.Key, e.Message)
+}
+
+// ValidationErrors is returned when a configuration fails validation and
+// holds every failed rule, so all of them can be reported at once, e.g. in
+// an API response. errors.As also finds the individual *ValidationError.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, ve := range e {
+		msgs[i] = ve.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, ve := range e {
+		errs[i] = ve
+	}
+	return errs
+}
+
+// SchemaValidator is implemented by schemas and section schemas with
+// invariants that struct tags cannot express, such as "read_timeout must be
+// less than idle_timeout" or "tls.cert requires tls.key". Validate is called
+// on the decoded struct after its tags pass; an error rejects the load like
+// a failed tag does. Returning a *ValidationError or ValidationErrors names
+// the offending keys.
+type SchemaValidator interface {
+	Validate() error
+}
+
+// WithValidator makes the manager validate schemas and sections with v, for
+// applications that already configure a validator with their own tags.
+// Translations for WithValidationLocale are registered on it. A nil
+// validator disables validation.
+func WithValidator(v *validator.Validate) Option {
+	return func(cm *ConfigManager) {
+		cm.validate = v
+	}
+}
+
+// RegisterValidation registers a custom validation tag, e.g. "s3bucket",
+// for use in schema and section struct tags. It must be called before the
+// configuration that uses the tag is loaded. Messages for the tag can be
+// supplied through WithValidationMessages.
+func (cm *ConfigManager) RegisterValidation(tag string, fn validator.Func, callValidationEvenIfNull ...bool) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	if cm.validate == nil {
+		return errors.New("validation is disabled")
+	}
+	return cm.validate.RegisterValidation(tag, fn, callValidationEvenIfNull...)
+}
+
+// RegisterStructValidation registers a struct-level validation for the
+// given struct types, for rules spanning several fields.
+func (cm *ConfigManager) RegisterStructValidation(fn validator.StructLevelFunc, types ...interface{}) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	if cm.validate == nil {
+		return
+	}
+	cm.validate.RegisterStructValidation(fn, types...)
+}
+
+// configKeyPath maps a validator struct namespace such as
+// "Config.Server.Port" onto the config key the field is decoded from, e.g.
+// "server.port", using the same mapstructure names as decoding and joined
+// with delim. Slice and map elements keep their [index] suffix.
+func configKeyPath(root reflect.Type, namespace, delim string) string {
+	segments := strings.Split(namespace, ".")
+	if len(segments) > 0 {
+		// The first segment names the root struct type.
+		segments = segments[1:]
+	}
+
+	t := root
+	var keys []string
+	for _, seg := range segments {
+		name, index, indexed := strings.Cut(seg, "[")
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		key := strings.ToLower(name)
+		var next reflect.Type
+		if t != nil && t.Kind() == reflect.Struct {
+			if f, ok := t.FieldByName(name); ok {
+				tag, opts, _ := strings.Cut(f.Tag.Get("mapstructure"), ",")
+				if tag != "" {
+					key = strings.ToLower(tag)
+				}
+				if f.Anonymous && strings.Contains(opts, "squash") {
+					key = ""
+				}
+				next = f.Type
+			}
+		}
+		if indexed {
+			key += "[" + index
+			for next != nil && next.Kind() == reflect.Ptr {
+				next = next.Elem()
+			}
+			switch {
+			case next == nil:
+			case next.Kind() == reflect.Slice, next.Kind() == reflect.Array, next.Kind() == reflect.Map:
+				next = next.Elem()
+			default:
+				next = nil
+			}
+		}
+		if key != "" {
+			keys = append(keys, key)
+		}
+		t = next
+	}
+	return strings.Join(keys, delim)
+}
@@ -0,0 +1,199 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type bucketConfig struct {
+	Storage struct {
+		Bucket string `mapstructure:"bucket" validate:"s3bucket"`
+		Region string `mapstructure:"region"`
+	} `mapstructure:"storage"`
+}
+
+func isS3Bucket(fl validator.FieldLevel) bool {
+	name := fl.Field().String()
+	return len(name) >= 3 && name == strings.ToLower(name) && !strings.Contains(name, "_")
+}
+
+func TestCustomValidators(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	write := func(content string) {
+		require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+	}
+	logger := zap.NewNop()
+
+	t.Run("RegisterValidation", func(t *testing.T) {
+		write("storage:\n  bucket: My_Bucket\n")
+		cfg := New(configPath, logger, WithSchema(&bucketConfig{}),
+			WithValidationLocale("en"),
+			WithValidationMessages("en", MessageCatalog{"s3bucket": "{0} must be a valid S3 bucket name"}))
+		require.NoError(t, cfg.RegisterValidation("s3bucket", isS3Bucket))
+
+		err := cfg.Load()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Bucket must be a valid S3 bucket name")
+
+		write("storage:\n  bucket: my-bucket\n")
+		assert.NoError(t, cfg.Load())
+	})
+
+	t.Run("RegisterStructValidation", func(t *testing.T) {
+		write("storage:\n  bucket: logs\n")
+		cfg := New(configPath, logger, WithSchema(&bucketConfig{}))
+		require.NoError(t, cfg.RegisterValidation("s3bucket", isS3Bucket))
+		cfg.RegisterStructValidation(func(sl validator.StructLevel) {
+			c := sl.Current().Interface().(bucketConfig)
+			if c.Storage.Region == "" {
+				sl.ReportError(c.Storage.Region, "Region", "Region", "required_with_bucket", "")
+			}
+		}, bucketConfig{})
+		assert.ErrorContains(t, cfg.Load(), "required_with_bucket")
+	})
+
+	t.Run("WithValidator", func(t *testing.T) {
+		v := validator.New()
+		require.NoError(t, v.RegisterValidation("s3bucket", isS3Bucket))
+		write("storage:\n  bucket: x\n")
+		cfg := New(configPath, logger, WithSchema(&bucketConfig{}), WithValidator(v))
+		assert.ErrorContains(t, cfg.Load(), "s3bucket")
+	})
+}
+
+type aggregateConfig struct {
+	Server struct {
+		Port     int    `mapstructure:"port" validate:"min=1024"`
+		Host     string `mapstructure:"host" validate:"required"`
+		ReadWait int    `mapstructure:"read_wait" validate:"gte=0"`
+	} `mapstructure:"server"`
+	Upstreams []struct {
+		URL string `mapstructure:"url" validate:"required,url"`
+	} `mapstructure:"upstreams" validate:"dive"`
+}
+
+func TestValidationAggregation(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := "server:\n  port: 80\n  read_wait: -1\nupstreams:\n  - url: http://a\n  - url: nope\n"
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+
+	t.Run("Reports Every Failure By Config Key", func(t *testing.T) {
+		err := New(configPath, zap.NewNop(), WithSchema(&aggregateConfig{})).Load()
+		require.Error(t, err)
+		msg := err.Error()
+		assert.Contains(t, msg, "validation failed for field 'server.port': min")
+		assert.Contains(t, msg, "validation failed for field 'server.host': required")
+		assert.Contains(t, msg, "validation failed for field 'server.read_wait': gte")
+		assert.Contains(t, msg, "validation failed for field 'upstreams[1].url': url")
+		assert.NotContains(t, msg, "Server")
+	})
+
+	t.Run("Typed Errors", func(t *testing.T) {
+		err := New(configPath, zap.NewNop(), WithSchema(&aggregateConfig{})).Load()
+		var verrs ValidationErrors
+		require.True(t, errors.As(err, &verrs))
+		require.Len(t, verrs, 4)
+		assert.Equal(t, &ValidationError{
+			Key:     "server.port",
+			Value:   80,
+			Rule:    "min",
+			Param:   "1024",
+			Message: "min",
+		}, verrs[0])
+
+		var first *ValidationError
+		require.True(t, errors.As(err, &first))
+		assert.Equal(t, "server.port", first.Key)
+	})
+
+	t.Run("Secret Values Redacted", func(t *testing.T) {
+		type secretConfig struct {
+			Token string `mapstructure:"token" validate:"min=32" secret:"true"`
+		}
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("token: hunter2\n"), 0644))
+		err := New(path, zap.NewNop(), WithSchema(&secretConfig{})).Load()
+		var verrs ValidationErrors
+		require.True(t, errors.As(err, &verrs))
+		assert.Equal(t, RedactedValue, verrs[0].Value)
+	})
+
+	t.Run("Section Keys Are Prefixed", func(t *testing.T) {
+		type serverSection struct {
+			Port int `mapstructure:"port" validate:"min=1024"`
+		}
+		cfg := New(configPath, zap.NewNop())
+		cfg.RegisterSection("server", &serverSection{})
+		err := cfg.Load()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "validation failed for field 'server.port': min")
+	})
+}
+
+type timeoutConfig struct {
+	Server struct {
+		ReadTimeout time.Duration `mapstructure:"read_timeout" validate:"required"`
+		IdleTimeout time.Duration `mapstructure:"idle_timeout" validate:"required"`
+	} `mapstructure:"server"`
+}
+
+func (c *timeoutConfig) Validate() error {
+	if c.Server.ReadTimeout >= c.Server.IdleTimeout {
+		return &ValidationError{
+			Key:     "server.read_timeout",
+			Value:   c.Server.ReadTimeout,
+			Message: "must be less than server.idle_timeout",
+		}
+	}
+	return nil
+}
+
+func TestSchemaValidator(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(content string) {
+		require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+	}
+
+	t.Run("Invariant Holds", func(t *testing.T) {
+		write("server:\n  read_timeout: 5s\n  idle_timeout: 60s\n")
+		require.NoError(t, New(configPath, zap.NewNop(), WithSchema(&timeoutConfig{})).Load())
+	})
+
+	t.Run("Invariant Violated", func(t *testing.T) {
+		write("server:\n  read_timeout: 90s\n  idle_timeout: 60s\n")
+		err := New(configPath, zap.NewNop(), WithSchema(&timeoutConfig{})).Load()
+		require.Error(t, err)
+		var verr *ValidationError
+		require.True(t, errors.As(err, &verr))
+		assert.Equal(t, "server.read_timeout", verr.Key)
+		assert.Equal(t, "validation failed for field 'server.read_timeout': must be less than server.idle_timeout", err.Error())
+	})
+
+	t.Run("Tags Run First", func(t *testing.T) {
+		write("server:\n  read_timeout: 5s\n")
+		err := New(configPath, zap.NewNop(), WithSchema(&timeoutConfig{})).Load()
+		var verrs ValidationErrors
+		require.True(t, errors.As(err, &verrs))
+		assert.Equal(t, "server.idle_timeout", verrs[0].Key)
+	})
+
+	t.Run("Rejected Reload Keeps Previous", func(t *testing.T) {
+		write("server:\n  read_timeout: 5s\n  idle_timeout: 60s\n")
+		schema := &timeoutConfig{}
+		cfg := New(configPath, zap.NewNop(), WithSchema(schema))
+		require.NoError(t, cfg.Load())
+		write("server:\n  read_timeout: 90s\n  idle_timeout: 60s\n")
+		require.Error(t, cfg.Load())
+		assert.Equal(t, 5*time.Second, schema.Server.ReadTimeout)
+	})
+}
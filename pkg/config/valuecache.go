@@ -0,0 +1,108 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/spf13/viper"
+)
+
+// maxCachedValues bounds the values cached per generation, so getters called
+// with ever new keys cannot grow the cache without limit. Values read past
+// the bound are converted on every call.
+const maxCachedValues = 4096
+
+// liveConfig is a configuration published by storeViper, with the values
+// converted from it by the typed getters. A reload publishes a new
+// liveConfig, so the cache of each generation dies with it and a getter
+// never sees a value converted from another generation.
+type liveConfig struct {
+	viper *viper.Viper
+	// envPrefix and envDelim are set when viper's automatic env lookup is
+	// enabled, which reads PREFIX_KEY at call time rather than at load.
+	envPrefix string
+	envDelim  string
+	// values maps a valueKey to its cachedEntry, and size counts them.
+	values sync.Map
+	size   atomic.Int64
+}
+
+// valueKind identifies the conversion a cached value went through, so
+// GetInt and GetString of the same key are cached apart.
+type valueKind uint8
+
+const (
+	stringValue valueKind = iota
+	intValue
+	int32Value
+	int64Value
+	uintValue
+	uint16Value
+	uint32Value
+	uint64Value
+	float64Value
+	boolValue
+	durationValue
+	timeValue
+	sizeValue
+	sizeInBytesValue
+	stringSliceValue
+	intSliceValue
+)
+
+// valueKey is the key of a cached value.
+type valueKey struct {
+	kind valueKind
+	key  string
+}
+
+// cachedEntry is a cached value and, under automatic env, the environment
+// variable that overrides its key and the value it had at conversion.
+type cachedEntry struct {
+	value  interface{}
+	env    string
+	envVal string
+	envSet bool
+}
+
+// fresh reports whether the environment variable of e is unchanged since
+// its value was converted.
+func (e *cachedEntry) fresh() bool {
+	if e.env == "" {
+		return true
+	}
+	value, ok := os.LookupEnv(e.env)
+	return ok == e.envSet && value == e.envVal
+}
+
+// cachedValue returns convert(v, key) for the live configuration v, casting
+// and parsing key's value only the first time it is read as kind in the
+// current generation. Under automatic env, a cached value is converted again
+// once the variable overriding its key changes, as viper reads it on every
+// call. Cached values are shared by every caller, so getters returning
+// slices copy them.
+func cachedValue[T any](cm *ConfigManager, kind valueKind, key string, convert func(v *viper.Viper, key string) T) T {
+	live := cm.live.Load()
+	k := valueKey{kind: kind, key: key}
+	if cached, ok := live.values.Load(k); ok {
+		if e := cached.(*cachedEntry); e.fresh() {
+			return e.value.(T)
+		}
+	} else if live.size.Load() >= maxCachedValues {
+		return convert(live.viper, key)
+	} else {
+		live.size.Add(1)
+	}
+
+	e := &cachedEntry{}
+	if live.envPrefix != "" {
+		e.env = strings.ToUpper(live.envPrefix + "_" + strings.ReplaceAll(key, live.envDelim, "_"))
+		e.envVal, e.envSet = os.LookupEnv(e.env)
+	}
+	value := convert(live.viper, key)
+	e.value = value
+	live.values.Store(k, e)
+	return value
+}
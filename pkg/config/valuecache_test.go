@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestValueCache(t *testing.T) {
+	configPath := writeInterpolateConfig(t, `
+server:
+  port: "8080"
+  timeout: 30s
+  hosts: [a, b]
+`)
+	cfg := New(configPath, zap.NewNop())
+	require.NoError(t, cfg.Load())
+
+	assert.Equal(t, 30*time.Second, cfg.GetDuration("server.timeout"))
+	assert.Equal(t, 30*time.Second, cfg.GetDuration("server.timeout"))
+	assert.Equal(t, 8080, cfg.GetInt("server.port"))
+	assert.Equal(t, "8080", cfg.GetString("server.port"), "each conversion is cached apart")
+	assert.Zero(t, cfg.GetDuration("server.missing"))
+
+	hosts := cfg.GetStringSlice("server.hosts")
+	hosts[0] = "modified"
+	assert.Equal(t, []string{"a", "b"}, cfg.GetStringSlice("server.hosts"), "callers get their own copy")
+
+	// A reload starts a new generation with an empty cache.
+	require.NoError(t, cfg.Set("server.timeout", "1m"))
+	require.NoError(t, cfg.Set("server.missing", "5s"))
+	assert.Equal(t, time.Minute, cfg.GetDuration("server.timeout"))
+	assert.Equal(t, 5*time.Second, cfg.GetDuration("server.missing"))
+	assert.Equal(t, 8080, cfg.GetInt("server.port"))
+
+	// A rejected reload keeps the generation and its cache.
+	cached := cfg.live.Load()
+	require.NoError(t, writeFileAtomic(configPath, []byte("server: [")))
+	require.Error(t, cfg.Load())
+	assert.Same(t, cached, cfg.live.Load())
+	assert.Equal(t, time.Minute, cfg.GetDuration("server.timeout"))
+}
+
+func TestValueCacheEnv(t *testing.T) {
+	configPath := writeInterpolateConfig(t, "server:\n  port: 8080\n  timeout: 30s\n")
+	t.Setenv("APP_SERVER_TIMEOUT", "1m")
+	cfg := New(configPath, zap.NewNop(), WithEnvPrefix("APP"))
+	require.NoError(t, cfg.Load())
+	assert.Equal(t, time.Minute, cfg.GetDuration("server.timeout"))
+	assert.Equal(t, 8080, cfg.GetInt("server.port"))
+
+	// Automatic env is read on every call, so cached values follow the
+	// variables without a reload, as the uncached viper does.
+	t.Setenv("APP_SERVER_TIMEOUT", "2m")
+	t.Setenv("APP_SERVER_PORT", "9090")
+	assert.Equal(t, 2*time.Minute, cfg.GetDuration("server.timeout"))
+	assert.Equal(t, 9090, cfg.GetInt("server.port"))
+	assert.Equal(t, cfg.current().GetInt("server.port"), cfg.GetInt("server.port"))
+
+	require.NoError(t, os.Unsetenv("APP_SERVER_TIMEOUT"))
+	assert.Equal(t, cfg.current().GetDuration("server.timeout"), cfg.GetDuration("server.timeout"))
+}
+
+func TestValueCacheBound(t *testing.T) {
+	cfg := New(writeInterpolateConfig(t, "server:\n  port: 8080\n"), zap.NewNop())
+	require.NoError(t, cfg.Load())
+	for i := 0; i < maxCachedValues+100; i++ {
+		cfg.GetString(fmt.Sprintf("missing.key%d", i))
+	}
+	assert.Equal(t, int64(maxCachedValues), cfg.live.Load().size.Load())
+	// Keys past the bound are still read, just not cached.
+	assert.Equal(t, 8080, cfg.GetInt("server.port"))
+	_, cached := cfg.live.Load().values.Load(valueKey{kind: intValue, key: "server.port"})
+	assert.False(t, cached)
+}
+
+// BenchmarkGetDuration compares cached reads with converting the value on
+// every call.
+func BenchmarkGetDuration(b *testing.B) {
+	cfg := newBenchmarkConfig(b)
+	b.Run("Cached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			cfg.GetDuration("server.timeout")
+		}
+	})
+	b.Run("Uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			cfg.current().GetDuration("server.timeout")
+		}
+	})
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+)
+
+// Warnings returns the warn tag rules the live configuration fails. A field
+// tagged warn:"max=100" is checked like a validate tag, but failing it does
+// not reject the load: it is logged and reported here instead, for values
+// that are probably a mistake rather than certainly invalid.
+func (cm *ConfigManager) Warnings() []*ValidationError {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return append([]*ValidationError(nil), cm.warnings...)
+}
+
+// recordWarnings checks the warn tags of the schema and registered sections
+// just decoded and logs each failure. The caller must hold cm.mu.
+func (cm *ConfigManager) recordWarnings() {
+	if cm.validate == nil {
+		return
+	}
+	secrets := cm.secretPatterns()
+	var warnings []*ValidationError
+	check := func(schema interface{}, prefix string) {
+		walkWarnTags(reflect.ValueOf(schema), prefix, cm.keyDelimiter, func(key string, field reflect.Value, rules string) {
+			if err := cm.validate.VarCtx(context.Background(), field.Interface(), rules); err != nil {
+				var fieldErrors validator.ValidationErrors
+				if !errors.As(err, &fieldErrors) {
+					cm.logger.Error("Invalid warn tag", zap.String("key", key), zap.Error(err))
+					return
+				}
+				for _, fe := range fieldErrors {
+					value := fe.Value()
+					if isSecret(key, secrets, cm.keyDelimiter) {
+						value = RedactedValue
+					}
+					warnings = append(warnings, &ValidationError{
+						Key:     key,
+						Value:   value,
+						Rule:    fe.Tag(),
+						Param:   fe.Param(),
+						Message: strings.TrimSpace(cm.translator.translate(fe)),
+					})
+				}
+			}
+		})
+	}
+	if cm.schema != nil {
+		check(cm.schema, "")
+	}
+	for _, sec := range cm.sections {
+		check(sec.schema, sec.key+cm.keyDelimiter)
+	}
+
+	cm.warnings = warnings
+	for _, w := range warnings {
+		cm.logger.Warn("Configuration value fails warning rule",
+			zap.String("key", w.Key),
+			zap.Any("value", w.Value),
+			zap.String("rule", w.Rule),
+			zap.String("message", w.Message))
+	}
+}
+
+// walkWarnTags calls fn for every field of the struct v carries a warn tag
+// on, with its config key.
+func walkWarnTags(v reflect.Value, prefix, delim string, fn func(key string, field reflect.Value, rules string)) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("mapstructure"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		key := prefix + strings.ToLower(name)
+		if rules := f.Tag.Get("warn"); rules != "" {
+			fn(key, v.Field(i), rules)
+		}
+
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && ft != reflect.TypeOf(time.Time{}) {
+			if f.Anonymous && strings.Contains(opts, "squash") {
+				walkWarnTags(v.Field(i), prefix, delim, fn)
+			} else {
+				walkWarnTags(v.Field(i), key+delim, delim, fn)
+			}
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type warnConfig struct {
+	Database struct {
+		MaxConns int    `mapstructure:"maxconns" validate:"min=1" warn:"max=100"`
+		Password string `mapstructure:"password" warn:"min=12" secret:"true"`
+	} `mapstructure:"database"`
+}
+
+func TestWarnings(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(content string) {
+		require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+	}
+
+	write("database:\n  maxconns: 500\n  password: short\n")
+	core, logs := observer.New(zap.WarnLevel)
+	cfg := New(configPath, zap.New(core), WithSchema(&warnConfig{}), WithValidationLocale("en"))
+	require.NoError(t, cfg.Load(), "warnings do not block the load")
+
+	warnings := cfg.Warnings()
+	require.Len(t, warnings, 2)
+	assert.Equal(t, "database.maxconns", warnings[0].Key)
+	assert.Equal(t, 500, warnings[0].Value)
+	assert.Equal(t, "max", warnings[0].Rule)
+	assert.Equal(t, "must be 100 or less", warnings[0].Message)
+	assert.Equal(t, "database.password", warnings[1].Key)
+	assert.Equal(t, RedactedValue, warnings[1].Value)
+	assert.Equal(t, 2, logs.FilterMessage("Configuration value fails warning rule").Len())
+
+	write("database:\n  maxconns: 50\n  password: long-enough-secret\n")
+	require.NoError(t, cfg.Load())
+	assert.Empty(t, cfg.Warnings())
+
+	// A validate failure still rejects the load and keeps the warnings state.
+	write("database:\n  maxconns: 0\n")
+	require.Error(t, cfg.Load())
+	assert.Empty(t, cfg.Warnings())
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchRegistry is the set of files and directories the file watcher
+// covers. Providers and other components register the paths they read, and
+// changes to any of them feed the manager's single change stream.
+type watchRegistry struct {
+	mu    sync.Mutex
+	files map[string]bool
+	dirs  map[string]bool
+	// added is called for paths registered while a watcher is running.
+	added func(path string, dir bool)
+	// dirty records a change to a secondary target, whose content the
+	// provider does not hash.
+	dirty bool
+}
+
+func newWatchRegistry() *watchRegistry {
+	return &watchRegistry{files: make(map[string]bool), dirs: make(map[string]bool)}
+}
+
+// addFile registers a file. Its directory is watched, so atomic
+// replacements and symlink swaps are seen.
+func (r *watchRegistry) addFile(path string) {
+	r.add(path, false)
+}
+
+// addDir registers a directory; any change to an entry in it counts.
+func (r *watchRegistry) addDir(path string) {
+	r.add(path, true)
+}
+
+func (r *watchRegistry) add(path string, dir bool) {
+	if r == nil || path == "" {
+		return
+	}
+	path = filepath.Clean(path)
+	r.mu.Lock()
+	set := r.files
+	if dir {
+		set = r.dirs
+	}
+	if set[path] {
+		r.mu.Unlock()
+		return
+	}
+	set[path] = true
+	added := r.added
+	r.mu.Unlock()
+
+	if added != nil {
+		added(path, dir)
+	}
+}
+
+// targets returns the registered files and directories, sorted.
+func (r *watchRegistry) targets() (files, dirs []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return sortedKeys(r.files), sortedKeys(r.dirs)
+}
+
+// markDirty records that a secondary target changed.
+func (r *watchRegistry) markDirty() {
+	r.mu.Lock()
+	r.dirty = true
+	r.mu.Unlock()
+}
+
+// takeDirty reports and clears whether a secondary target changed.
+func (r *watchRegistry) takeDirty() bool {
+	if r == nil {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	dirty := r.dirty
+	r.dirty = false
+	return dirty
+}
+
+// watchDirs returns the directories fsnotify must watch to cover the
+// registered targets.
+func (r *watchRegistry) watchDirs() []string {
+	files, dirs := r.targets()
+	set := make(map[string]bool, len(files)+len(dirs))
+	for _, f := range files {
+		set[filepath.Dir(f)] = true
+	}
+	for _, d := range dirs {
+		set[d] = true
+	}
+	out := make([]string, 0, len(set))
+	for d := range set {
+		out = append(out, d)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// targetMatcher decides which fsnotify events change a registered target.
+// It remembers the resolved path of each file to detect symlink swaps.
+type targetMatcher struct {
+	registry *watchRegistry
+	resolved map[string]string
+}
+
+func newTargetMatcher(registry *watchRegistry) *targetMatcher {
+	m := &targetMatcher{registry: registry, resolved: make(map[string]string)}
+	files, _ := registry.targets()
+	for _, f := range files {
+		m.resolved[f], _ = filepath.EvalSymlinks(f)
+	}
+	return m
+}
+
+// match reports the targets changed by event: a write to or creation of a
+// registered file, a registered file now resolving to a different target,
+// or any change inside a registered directory.
+func (m *targetMatcher) match(event fsnotify.Event) []string {
+	name := filepath.Clean(event.Name)
+	files, dirs := m.registry.targets()
+
+	var changed []string
+	for _, f := range files {
+		current, _ := filepath.EvalSymlinks(f)
+		prev, seen := m.resolved[f]
+		written := name == f && event.Has(fsnotify.Write|fsnotify.Create)
+		relinked := seen && current != "" && current != prev
+		m.resolved[f] = current
+		if written || relinked {
+			changed = append(changed, f)
+		}
+	}
+	if event.Op != fsnotify.Chmod {
+		for _, d := range dirs {
+			if filepath.Dir(name) == d {
+				changed = append(changed, d)
+			}
+		}
+	}
+	return changed
+}
+
+// WithWatchPaths adds files or directories to the file watcher, such as
+// overlays or a mounted secrets directory read by a reload hook. A change
+// to any of them reloads the configuration like a change to the config
+// file does. Paths that do not exist when watching starts are skipped.
+func WithWatchPaths(paths ...string) Option {
+	return func(cm *ConfigManager) {
+		for _, p := range paths {
+			if info, err := os.Stat(p); err == nil && info.IsDir() {
+				cm.watchTargets.addDir(p)
+			} else {
+				cm.watchTargets.addFile(p)
+			}
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWatchTargets(t *testing.T) {
+	waitFor := func(t *testing.T, changes chan struct{}) {
+		t.Helper()
+		select {
+		case <-changes:
+		case <-time.After(2 * time.Second):
+			t.Fatal("timeout waiting for config change")
+		}
+	}
+
+	t.Run("Jsonnet Imports", func(t *testing.T) {
+		dir := t.TempDir()
+		libDir := filepath.Join(dir, "lib")
+		require.NoError(t, os.Mkdir(libDir, 0755))
+		basePath := filepath.Join(libDir, "base.libsonnet")
+		require.NoError(t, os.WriteFile(basePath, []byte(`{ server: { port: 8080 } }`), 0644))
+		configPath := filepath.Join(dir, "config.jsonnet")
+		require.NoError(t, os.WriteFile(configPath, []byte(`import 'lib/base.libsonnet'`), 0644))
+
+		// High-frequency mode hashes only the main file; the import must
+		// still reload.
+		cfg := New(configPath, zap.NewNop(), WithHighFrequencyReload())
+		defer cfg.Close()
+		require.NoError(t, cfg.Load())
+
+		changes := make(chan struct{}, 10)
+		require.NoError(t, cfg.Watch(context.Background(), func() { changes <- struct{}{} }))
+
+		require.NoError(t, writeFileAtomic(basePath, []byte(`{ server: { port: 9090 } }`)))
+		waitFor(t, changes)
+		assert.Equal(t, 9090, cfg.GetInt("server.port"))
+	})
+
+	t.Run("Extra Directory", func(t *testing.T) {
+		configPath, cleanup := setupTestConfig(t)
+		defer cleanup()
+		secrets := t.TempDir()
+
+		cfg := New(configPath, zap.NewNop(), WithWatchPaths(secrets))
+		defer cfg.Close()
+		require.NoError(t, cfg.Load())
+
+		changes := make(chan struct{}, 10)
+		require.NoError(t, cfg.Watch(context.Background(), func() { changes <- struct{}{} }))
+
+		require.NoError(t, os.WriteFile(filepath.Join(secrets, "db-password"), []byte("s3cret"), 0600))
+		waitFor(t, changes)
+	})
+
+	t.Run("Registered While Watching", func(t *testing.T) {
+		configPath, cleanup := setupTestConfig(t)
+		defer cleanup()
+		certDir := t.TempDir()
+		caFile := filepath.Join(certDir, "ca.pem")
+
+		cfg := New(configPath, zap.NewNop())
+		defer cfg.Close()
+		require.NoError(t, cfg.Load())
+
+		changes := make(chan struct{}, 10)
+		require.NoError(t, cfg.Watch(context.Background(), func() { changes <- struct{}{} }))
+		cfg.watchTargets.addFile(caFile)
+
+		require.NoError(t, os.WriteFile(filepath.Join(certDir, "unrelated"), nil, 0644))
+		select {
+		case <-changes:
+			t.Fatal("unregistered file triggered a reload")
+		case <-time.After(100 * time.Millisecond):
+		}
+		require.NoError(t, os.WriteFile(caFile, []byte("pem"), 0644))
+		waitFor(t, changes)
+	})
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// Save writes the effective configuration back to the config file. See
+// WriteConfigAs.
+func (cm *ConfigManager) Save() error {
+	if cm.path == "" {
+		return errors.New("no config file path to save to")
+	}
+	return cm.writeConfig(cm.path, cm.configType)
+}
+
+// WriteConfigAs writes the effective configuration to path, encoded in the
+// format given by its extension. Values are written as they appear in the
+// documents and runtime layers: secret references, key references, and
+// ${NAME} placeholders are kept, never what they resolved to. The file is written to a temporary file in
+// the same directory and renamed into place, so readers never observe a
+// partially written file.
+func (cm *ConfigManager) WriteConfigAs(path string) error {
+	return cm.writeConfig(path, "")
+}
+
+func (cm *ConfigManager) writeConfig(path, format string) error {
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+	enc, err := lookupEncoder(format)
+	if err != nil {
+		return err
+	}
+	data, err := enc.Encode(cm.unresolvedSettings())
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return fmt.Errorf("error writing config file: %w", err)
+	}
+	cm.logger.Info("Configuration written", zap.String("path", path))
+	return nil
+}
+
+// Export renders the effective configuration to w in the given format (e.g.
+// "yaml", "json", "toml", or a registered encoder), with secrets masked as
+// in AllSettingsRedacted. It is meant for --dump-config flags and support
+// bundles; use Save to persist the unmasked configuration.
+func (cm *ConfigManager) Export(w io.Writer, format string) error {
+	enc, err := lookupEncoder(format)
+	if err != nil {
+		return err
+	}
+	data, err := enc.Encode(cm.AllSettingsRedacted())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// writeFileAtomic replaces path with data using write-temp-then-rename. An
+// existing file keeps its permissions; new files are created with 0644.
+func writeFileAtomic(path string, data []byte) (err error) {
+	mode := os.FileMode(0644)
+	if info, statErr := os.Stat(path); statErr == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Chmod(mode); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// unresolvedSettings returns a deep copy of the effective settings with the
+// values env interpolation, key references, and secret resolution changed
+// put back as written, for Save and Push.
+func (cm *ConfigManager) unresolvedSettings() map[string]interface{} {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return deepCopyMap(cm.unresolved)
+}
+
+// templateSource is implemented by providers that record the values
+// resolved while loading, keyed by the key they were found at.
+type templateSource interface {
+	lastTemplates() map[string]interpolation
+}
+
+// restoreTemplates puts the recorded templates back into settings, for the
+// keys whose value is still the resolved one.
+func (cm *ConfigManager) restoreTemplates(settings map[string]interface{}, templates map[string]interpolation) {
+	for key, t := range templates {
+		path := cm.splitKey(key)
+		if current, ok := lookupPath(settings, path); ok && reflect.DeepEqual(current, t.value) {
+			setPath(settings, path, deepCopyValue(t.template))
+		}
+	}
+}
+
+// resolvedTemplates returns the values of the live configuration that
+// differ from the unresolved settings, with the unresolved value as their
+// template, so a manager layered on this one, such as a tenant's, can save
+// them unresolved. The caller must hold cm.mu.
+func (cm *ConfigManager) resolvedTemplates() map[string]interpolation {
+	templates := make(map[string]interpolation)
+	resolved := cm.viper.AllSettings()
+	for _, key := range leafKeys(cm.unresolved, "", cm.keyDelimiter) {
+		path := cm.splitKey(key)
+		original, _ := lookupPath(cm.unresolved, path)
+		if value, ok := lookupPath(resolved, path); ok && !reflect.DeepEqual(original, value) {
+			templates[key] = interpolation{template: deepCopyValue(original), value: deepCopyValue(value)}
+		}
+	}
+	return templates
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWriteConfig(t *testing.T) {
+	configPath, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	logger, _ := zap.NewDevelopment()
+	cfg := New(configPath, logger)
+	require.NoError(t, cfg.Load())
+	require.NoError(t, cfg.Set("server.port", 9090))
+
+	t.Run("Save", func(t *testing.T) {
+		require.NoError(t, os.Chmod(configPath, 0600))
+		require.NoError(t, cfg.Save())
+
+		info, err := os.Stat(configPath)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+		reloaded := New(configPath, logger)
+		require.NoError(t, reloaded.Load())
+		assert.Equal(t, 9090, reloaded.GetInt("server.port"))
+		assert.Equal(t, "testdb", reloaded.GetString("database.name"))
+
+		entries, err := os.ReadDir(filepath.Dir(configPath))
+		require.NoError(t, err)
+		assert.Len(t, entries, 1, "temporary file left behind")
+	})
+
+	t.Run("WriteConfigAs Other Format", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.toml")
+		require.NoError(t, cfg.WriteConfigAs(path))
+
+		reloaded := New(path, logger)
+		require.NoError(t, reloaded.Load())
+		assert.Equal(t, 9090, reloaded.GetInt("server.port"))
+	})
+
+	t.Run("Unsupported Format", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.jsonnet")
+		assert.Error(t, cfg.WriteConfigAs(path))
+		_, err := os.Stat(path)
+		assert.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestWriteConfigUnresolved(t *testing.T) {
+	t.Setenv("PROBE_PW", "hunter2")
+	t.Setenv("PROBE_HOST", "")
+	configPath := writeInterpolateConfig(t, `
+server:
+  host: ${PROBE_HOST:-localhost}
+  tags: ["${PROBE_HOST:-edge}", static]
+database:
+  password: env://PROBE_PW
+  dsn: postgres://${server.host}/app
+`)
+	cfg := New(configPath, zap.NewNop(), WithSecretResolver("env", EnvSecretResolver{}))
+	require.NoError(t, cfg.Load())
+	require.Equal(t, "hunter2", cfg.GetString("database.password"))
+	require.Equal(t, "postgres://localhost/app", cfg.GetString("database.dsn"))
+	require.NoError(t, cfg.Set("server.port", 9090))
+	require.NoError(t, cfg.Save())
+
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	saved := string(data)
+	assert.NotContains(t, saved, "hunter2")
+	assert.Contains(t, saved, "env://PROBE_PW")
+	assert.Contains(t, saved, "${PROBE_HOST:-localhost}")
+	assert.Contains(t, saved, "${PROBE_HOST:-edge}")
+	assert.Contains(t, saved, "postgres://${server.host}/app")
+
+	reloaded := New(configPath, zap.NewNop(), WithSecretResolver("env", EnvSecretResolver{}))
+	require.NoError(t, reloaded.Load())
+	assert.Equal(t, "hunter2", reloaded.GetString("database.password"))
+	assert.Equal(t, "localhost", reloaded.GetString("server.host"))
+	assert.Equal(t, []string{"edge", "static"}, reloaded.GetStringSlice("server.tags"))
+	assert.Equal(t, 9090, reloaded.GetInt("server.port"))
+
+	t.Run("Runtime Values Win", func(t *testing.T) {
+		require.NoError(t, cfg.Set("server.host", "example.com"))
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, cfg.WriteConfigAs(path))
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "host: example.com")
+		assert.False(t, strings.Contains(string(data), "PROBE_HOST:-localhost"))
+	})
+}